@@ -10,6 +10,7 @@ import (
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/lb"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
 )
 
 var _ tg.NameGenerator = (*NameGenerator)(nil)
@@ -39,7 +40,7 @@ func (gen *NameGenerator) NameLB(namespace string, ingressName string) string {
 }
 
 func (gen *NameGenerator) NameTG(namespace string, ingressName string, serviceName, servicePort string,
-	targetType string, protocol string) string {
+	targetType string, protocol string, protocolVersion string) string {
 	LBName := gen.NameLB(namespace, ingressName)
 
 	hasher := md5.New()
@@ -48,6 +49,10 @@ func (gen *NameGenerator) NameTG(namespace string, ingressName string, serviceNa
 	_, _ = hasher.Write([]byte(servicePort))
 	_, _ = hasher.Write([]byte(protocol))
 	_, _ = hasher.Write([]byte(targetType))
+	// the default protocol version is left out to keep the names of targetGroups created before it was supported
+	if protocolVersion != targetgroup.DefaultProtocolVersion {
+		_, _ = hasher.Write([]byte(protocolVersion))
+	}
 
 	return fmt.Sprintf("%.12s-%.19s", gen.ALBNamePrefix, hex.EncodeToString(hasher.Sum(nil)))
 }
@@ -0,0 +1,15 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NameTG(t *testing.T) {
+	gen := NameGenerator{ALBNamePrefix: "cluster"}
+
+	assert.Equal(t, "cluster-113b2811ddfccd8ccff", gen.NameTG("namespace", "ingress", "service", "80", "ip", "HTTP", "HTTP1"))
+	assert.Equal(t, "cluster-2c36aea127922acab83", gen.NameTG("namespace", "ingress", "service", "80", "ip", "HTTP", "HTTP2"))
+	assert.Equal(t, "cluster-1fc0c7f2290c34b0e19", gen.NameTG("namespace", "ingress", "service", "80", "ip", "HTTP", "GRPC"))
+}
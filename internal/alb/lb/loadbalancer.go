@@ -18,6 +18,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	util "github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/types"
@@ -30,6 +31,7 @@ import (
 // LoadBalancerController manages loadBalancer for ingress objects
 type Controller interface {
 	// Reconcile will make sure an LoadBalancer exists for specified ingress.
+	// The LoadBalancer may come with a RequeueAfter error, when it must be reconciled again later.
 	Reconcile(ctx context.Context, ingress *extensions.Ingress) (*LoadBalancer, error)
 
 	// Deletes will ensure no LoadBalancer exists for specified ingressKey.
@@ -137,15 +139,18 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 		}
 	}
 
-	tgGroup, err := controller.tgGroupController.Reconcile(ctx, ingress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to reconcile targetGroups due to %v", err)
+	// targetGroups replacing others are reconciled again until they are healthy, without holding up the listeners
+	tgGroup, tgErr := controller.tgGroupController.Reconcile(ctx, ingress)
+	if _, ok := ingerrors.IsRequeueAfter(tgErr); tgErr != nil && !ok {
+		return nil, fmt.Errorf("failed to reconcile targetGroups due to %v", tgErr)
 	}
 	if err := controller.lsGroupController.Reconcile(ctx, lbArn, ingress, tgGroup); err != nil {
 		return nil, fmt.Errorf("failed to reconcile listeners due to %v", err)
 	}
-	if err := controller.tgGroupController.GC(ctx, tgGroup); err != nil {
-		return nil, fmt.Errorf("failed to GC targetGroups due to %v", err)
+	// targetGroups awaiting their deletion are deleted once the ingress is reconciled again
+	gcErr := controller.tgGroupController.GC(ctx, tgGroup)
+	if _, ok := ingerrors.IsRequeueAfter(gcErr); gcErr != nil && !ok {
+		return nil, fmt.Errorf("failed to GC targetGroups due to %v", gcErr)
 	}
 
 	if err := controller.sgAssociationController.Reconcile(ctx, ingKey, sgAttachment, instance, tgGroup); err != nil {
@@ -154,7 +159,7 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 	return &LoadBalancer{
 		Arn:     lbArn,
 		DNSName: aws.StringValue(instance.DNSName),
-	}, nil
+	}, ingerrors.EarliestRequeueAfter(tgErr, gcErr)
 }
 
 func (controller *defaultController) Delete(ctx context.Context, ingressKey types.NamespacedName) error {
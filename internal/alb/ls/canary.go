@@ -0,0 +1,162 @@
+package ls
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/conditions"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
+	"github.com/pkg/errors"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+const cookieHeaderName = "Cookie"
+
+const (
+	// maxRuleConditionValues is the maximum number of condition values of a listener rule
+	maxRuleConditionValues = 5
+	// maxRuleWildcards is the maximum number of wildcards in the condition values of a listener rule
+	maxRuleWildcards = 5
+)
+
+// getPathCanary returns the canary configured for the path named pathName.
+func getPathCanary(ingressAnnos *annotations.Ingress, pathName string, backend extensions.IngressBackend) (canary.Canary, bool) {
+	if ingressAnnos.Canary == nil {
+		return canary.Canary{}, false
+	}
+	return ingressAnnos.Canary.GetPathCanary(pathName, backend.ServiceName)
+}
+
+// buildCanaryRules expands a canary into one rule per header match or cookie pattern that routes matched requests to the canary service,
+// followed by a rule that splits the remaining requests between the stable backend and the canary service by weight.
+func buildCanaryRules(ctx context.Context, authCfg auth.Config, stickinessCfg *elbv2.TargetGroupStickinessConfig, backend extensions.IngressBackend, canaryCfg canary.Canary, elbConditions []*elbv2.RuleCondition, tgGroup tg.TargetGroupGroup) ([]elbv2.Rule, error) {
+	if action.Use(backend.ServicePort.String()) {
+		return nil, errors.Errorf("canary for %v requires a backend with a service, got `servicePort: %v`", backend.ServiceName, action.UseActionAnnotation)
+	}
+	stableTG, ok := tgGroup.TGByBackend[backend]
+	if !ok {
+		return nil, errors.Errorf("unable to find targetGroup for backend %v:%v",
+			backend.ServiceName, backend.ServicePort.String())
+	}
+	canaryBackend := canaryCfg.Backend()
+	canaryTG, ok := tgGroup.TGByBackend[canaryBackend]
+	if !ok {
+		return nil, errors.Errorf("unable to find targetGroup for canary backend %v:%v",
+			canaryBackend.ServiceName, canaryBackend.ServicePort.String())
+	}
+
+	var elbRules []elbv2.Rule
+	for _, matchCondition := range buildCanaryMatchConditions(canaryCfg) {
+		matchConditions := append([]*elbv2.RuleCondition{matchCondition}, elbConditions...)
+		if err := validateRuleConditionLimits(matchConditions); err != nil {
+			return nil, errors.Wrapf(err, "invalid canary for %v", backend.ServiceName)
+		}
+		elbRules = append(elbRules, elbv2.Rule{
+			Actions:    buildForwardActions(ctx, authCfg, stickinessCfg, canaryTG.TargetGroupTuples(1)),
+			Conditions: matchConditions,
+		})
+	}
+
+	canaryWeight := aws.Int64Value(canaryCfg.Weight)
+	elbRules = append(elbRules, elbv2.Rule{
+		Actions:    buildForwardActions(ctx, authCfg, stickinessCfg, append(stableTG.TargetGroupTuples(100-canaryWeight), canaryTG.TargetGroupTuples(canaryWeight)...)),
+		Conditions: elbConditions,
+	})
+	return elbRules, nil
+}
+
+// buildCanaryMatchConditions builds a http-header condition for the header match of a canary, and one for each pattern of its cookie match,
+// so that each rule stays within the limits of ALB on condition values and wildcards along with the host and path of the ingress rule.
+func buildCanaryMatchConditions(canaryCfg canary.Canary) []*elbv2.RuleCondition {
+	var elbConditions []*elbv2.RuleCondition
+	if canaryCfg.HeaderMatch != nil {
+		elbConditions = append(elbConditions, &elbv2.RuleCondition{
+			Field: aws.String(conditions.FieldHTTPHeader),
+			HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: canaryCfg.HeaderMatch.HttpHeaderName,
+				Values:         canaryCfg.HeaderMatch.Values,
+			},
+		})
+	}
+	if canaryCfg.CookieMatch != nil {
+		for _, headerValue := range canaryCfg.CookieMatch.HeaderValues() {
+			elbConditions = append(elbConditions, &elbv2.RuleCondition{
+				Field: aws.String(conditions.FieldHTTPHeader),
+				HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+					HttpHeaderName: aws.String(cookieHeaderName),
+					Values:         aws.StringSlice([]string{headerValue}),
+				},
+			})
+		}
+	}
+	return elbConditions
+}
+
+// validateRuleConditionLimits checks the conditions of a rule against the limits of ALB on the condition values
+// and the wildcards of a rule, so that rules ALB would reject fail before calling AWS.
+func validateRuleConditionLimits(elbConditions []*elbv2.RuleCondition) error {
+	values := 0
+	wildcards := 0
+	countValues := func(conditionValues ...*string) {
+		for _, value := range conditionValues {
+			values++
+			wildcards += strings.Count(aws.StringValue(value), "*") + strings.Count(aws.StringValue(value), "?")
+		}
+	}
+	for _, condition := range elbConditions {
+		countValues(condition.Values...)
+		if condition.HostHeaderConfig != nil {
+			countValues(condition.HostHeaderConfig.Values...)
+		}
+		if condition.PathPatternConfig != nil {
+			countValues(condition.PathPatternConfig.Values...)
+		}
+		if condition.HttpHeaderConfig != nil {
+			countValues(condition.HttpHeaderConfig.Values...)
+		}
+		if condition.HttpRequestMethodConfig != nil {
+			countValues(condition.HttpRequestMethodConfig.Values...)
+		}
+		if condition.SourceIpConfig != nil {
+			countValues(condition.SourceIpConfig.Values...)
+		}
+		if condition.QueryStringConfig != nil {
+			for _, keyValue := range condition.QueryStringConfig.Values {
+				// a key/value pair is one condition value
+				countValues(aws.String(aws.StringValue(keyValue.Key) + aws.StringValue(keyValue.Value)))
+			}
+		}
+	}
+	if values > maxRuleConditionValues {
+		return errors.Errorf("rule has %v condition values, exceeding the limit of %v", values, maxRuleConditionValues)
+	}
+	if wildcards > maxRuleWildcards {
+		return errors.Errorf("rule has %v wildcards, exceeding the limit of %v", wildcards, maxRuleWildcards)
+	}
+	return nil
+}
+
+// buildForwardActions will build listener rule actions that forward to targetGroups after authentication
+func buildForwardActions(ctx context.Context, authCfg auth.Config, stickinessCfg *elbv2.TargetGroupStickinessConfig, elbTGs []*elbv2.TargetGroupTuple) []*elbv2.Action {
+	var elbActions []*elbv2.Action
+	if authAction := buildAuthAction(ctx, authCfg); authAction != nil {
+		elbActions = append(elbActions, authAction)
+	}
+	elbActions = append(elbActions, &elbv2.Action{
+		Type: aws.String(elbv2.ActionTypeEnumForward),
+		ForwardConfig: &elbv2.ForwardActionConfig{
+			TargetGroups:                elbTGs,
+			TargetGroupStickinessConfig: stickinessCfg,
+		},
+	})
+	for index, elbAction := range elbActions {
+		elbAction.Order = aws.Int64(int64(index) + 1)
+	}
+	return elbActions
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	util "github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/types"
 	extensions "k8s.io/api/extensions/v1beta1"
 )
@@ -26,6 +27,7 @@ import (
 const (
 	AnnotationSSLPolicy      = "ssl-policy"
 	AnnotationCertificateARN = "certificate-arn"
+	AnnotationDefaultAction  = "default-action"
 )
 
 const (
@@ -48,11 +50,12 @@ type Controller interface {
 	Reconcile(ctx context.Context, options ReconcileOptions) error
 }
 
-func NewController(cloud aws.CloudAPI, authModule auth.Module) Controller {
-	rulesController := NewRulesController(cloud, authModule)
+func NewController(cloud aws.CloudAPI, store store.Storer, authModule auth.Module) Controller {
+	rulesController := NewRulesController(cloud, store, authModule)
 	certDiscovery := NewACMCertDiscovery(cloud)
 	return &defaultController{
 		cloud:           cloud,
+		store:           store,
 		authModule:      authModule,
 		rulesController: rulesController,
 		certDiscovery:   certDiscovery,
@@ -61,6 +64,7 @@ func NewController(cloud aws.CloudAPI, authModule auth.Module) Controller {
 
 type defaultController struct {
 	cloud           aws.CloudAPI
+	store           store.Storer
 	authModule      auth.Module
 	rulesController RulesController
 	certDiscovery   CertDiscovery
@@ -260,11 +264,47 @@ func (controller *defaultController) buildDefaultActions(ctx context.Context, op
 	if options.Ingress.Spec.Backend != nil {
 		backend = *options.Ingress.Spec.Backend
 	}
-	authCfg, err := controller.authModule.NewConfig(ctx, options.Ingress, backend, options.Port.Scheme)
+	if actionName, ok := defaultActionName(options.Ingress, options.Port.Port); ok {
+		backend = action.AnnotationBackend(actionName)
+	}
+	authCfg, err := controller.authModule.NewConfig(ctx, options.Ingress, backend, "")
+	if err != nil {
+		return nil, err
+	}
+	stickinessCfg, err := buildRuleStickinessConfig(controller.store, options.Ingress, options.IngressAnnos, backend, "")
+	if err != nil {
+		return nil, err
+	}
+	actions, err := buildActions(ctx, buildListenerAuthConfig(options.Port.Scheme, authCfg), stickinessCfg, options.IngressAnnos, backend, options.TGGroup)
 	if err != nil {
 		return nil, err
 	}
-	return buildActions(ctx, authCfg, options.IngressAnnos, backend, options.TGGroup)
+	// the implicit 404 response doesn't serve anything that requires authentication, and neither do default actions
+	// shadowed by a catch-all redirect
+	listener := &elbv2.Listener{Port: aws.Int64(options.Port.Port), Protocol: aws.String(options.Port.Scheme)}
+	if backend != action.Default404Backend() && !hasCatchAllRedirect(ctx, listener, options.Ingress, options.IngressAnnos) {
+		if err := validateListenerAuth(options.Port.Scheme, authCfg.Type, actions); err != nil {
+			return nil, errors.Wrapf(err, "invalid default backend")
+		}
+	}
+	// unlike authentication, ELBv2 rejects forwarding to targetGroups of unsupported protocol versions even if shadowed
+	if err := validateListenerProtocolVersion(options.Port.Scheme, actions, options.TGGroup); err != nil {
+		return nil, errors.Wrapf(err, "invalid default backend")
+	}
+	return actions, nil
+}
+
+// defaultActionName returns the name of the annotation configured action that should be used as default action for listener on port.
+// The port specific "default-action.${port}" annotation takes precedence over the "default-action" annotation.
+func defaultActionName(ingress *extensions.Ingress, port int64) (string, bool) {
+	var actionName string
+	if annotations.LoadStringAnnotation(fmt.Sprintf("%v.%v", AnnotationDefaultAction, port), &actionName, ingress.Annotations) {
+		return actionName, true
+	}
+	if annotations.LoadStringAnnotation(AnnotationDefaultAction, &actionName, ingress.Annotations) {
+		return actionName, true
+	}
+	return "", false
 }
 
 // inferCertARNs retrieves a set of certificates from ACM that matches the ingress' hosts list
@@ -10,9 +10,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -25,7 +28,7 @@ type GroupController interface {
 }
 
 func NewGroupController(store store.Storer, cloud aws.CloudAPI, authModule auth.Module) GroupController {
-	lsController := NewController(cloud, authModule)
+	lsController := NewController(cloud, store, authModule)
 	return &defaultGroupController{
 		cloud:        cloud,
 		store:        store,
@@ -45,6 +48,10 @@ func (controller *defaultGroupController) Reconcile(ctx context.Context, lbArn s
 	if err != nil {
 		return err
 	}
+	ingressAnnos, err = controller.resolveActionConfigMaps(ingress, ingressAnnos)
+	if err != nil {
+		return err
+	}
 	instancesByPort, err := controller.loadListenerInstances(ctx, lbArn)
 	if err != nil {
 		return err
@@ -90,6 +97,22 @@ func (controller *defaultGroupController) Delete(ctx context.Context, lbArn stri
 	return nil
 }
 
+// resolveActionConfigMaps returns a copy of ingressAnnos, where the annotation configured actions are populated from the ConfigMaps they reference.
+func (controller *defaultGroupController) resolveActionConfigMaps(ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) (*annotations.Ingress, error) {
+	if ingressAnnos.Action == nil || len(ingressAnnos.Action.ConfigMapNames()) == 0 {
+		return ingressAnnos, nil
+	}
+	actionConfig, err := ingressAnnos.Action.ResolveConfigMapRefs(func(name string) (*corev1.ConfigMap, error) {
+		return controller.store.GetConfigMap(types.NamespacedName{Namespace: ingress.Namespace, Name: name}.String())
+	})
+	if err != nil {
+		return nil, err
+	}
+	resolvedAnnos := *ingressAnnos
+	resolvedAnnos.Action = actionConfig
+	return &resolvedAnnos, nil
+}
+
 func (controller *defaultGroupController) loadListenerInstances(ctx context.Context, lbArn string) (map[int64]*elbv2.Listener, error) {
 	instances, err := controller.cloud.ListListenersByLoadBalancer(ctx, lbArn)
 	if err != nil {
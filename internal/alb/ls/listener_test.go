@@ -193,6 +193,114 @@ func TestDefaultController_Reconcile(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Reconcile succeed by creating http listener for default action by annotation",
+			Ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "namespace",
+					Annotations: map[string]string{
+						"alb.ingress.kubernetes.io/default-action":    "maintenance",
+						"alb.ingress.kubernetes.io/default-action.80": "not-found",
+					},
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromInt(8080),
+					},
+				},
+			},
+			IngressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"maintenance": {
+							Type: aws.String(elbv2.ActionTypeEnumFixedResponse),
+							FixedResponseConfig: &action.FixedResponseActionConfig{
+								ContentType: aws.String("text/html"),
+								MessageBody: aws.String("<h1>Under maintenance</h1>"),
+								StatusCode:  aws.String("503"),
+							},
+						},
+						"not-found": {
+							Type: aws.String(elbv2.ActionTypeEnumFixedResponse),
+							FixedResponseConfig: &action.FixedResponseActionConfig{
+								ContentType: aws.String("application/json"),
+								MessageBody: aws.String(`{"error":"not found"}`),
+								StatusCode:  aws.String("404"),
+							},
+						},
+					},
+				},
+			},
+			Port: loadbalancer.PortData{
+				Port:   80,
+				Scheme: elbv2.ProtocolEnumHttp,
+			},
+			TGGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{},
+			},
+			AuthConfig: auth.Config{
+				Type: auth.TypeNone,
+			},
+
+			CreateListenerCall: &CreateListenerCall{
+				Input: elbv2.CreateListenerInput{
+					LoadBalancerArn: aws.String(LBArn),
+					Certificates:    nil,
+					SslPolicy:       nil,
+					Protocol:        aws.String(elbv2.ProtocolEnumHttp),
+					Port:            aws.Int64(80),
+					DefaultActions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							FixedResponseConfig: &elbv2.FixedResponseActionConfig{
+								ContentType: aws.String("application/json"),
+								MessageBody: aws.String(`{"error":"not found"}`),
+								StatusCode:  aws.String("404"),
+							},
+							Type: aws.String(elbv2.ActionTypeEnumFixedResponse),
+						},
+					},
+				},
+				Instance: &elbv2.Listener{
+					ListenerArn: aws.String("lsArn"),
+				},
+			},
+
+			RulesReconcileCall: &RulesReconcileCall{
+				Instance: &elbv2.Listener{
+					ListenerArn: aws.String("lsArn"),
+				},
+			},
+		},
+		{
+			Name: "Reconcile failed when default action by annotation is not set",
+			Ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "namespace",
+					Annotations: map[string]string{
+						"alb.ingress.kubernetes.io/default-action": "maintenance",
+					},
+				},
+				Spec: extensions.IngressSpec{},
+			},
+			IngressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+			},
+			Port: loadbalancer.PortData{
+				Port:   80,
+				Scheme: elbv2.ProtocolEnumHttp,
+			},
+			TGGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{},
+			},
+			AuthConfig: auth.Config{
+				Type: auth.TypeNone,
+			},
+			ExpectedError: errors.New("failed to build listener config due to backend with `servicePort: use-annotation` was configured with `serviceName: maintenance` but an action annotation for maintenance is not set"),
+		},
 		{
 			Name: "Reconcile succeed by creating https listener for default backend",
 			Ingress: extensions.Ingress{
@@ -848,6 +956,42 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			},
 			ExpectedError: errors.New("failed to build listener config due to unable to find targetGroup for backend service:8080"),
 		},
+		{
+			Name: "Reconcile failed when default backend uses GRPC on http listener",
+			Ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "namespace",
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromInt(8080),
+					},
+				},
+			},
+			IngressAnnos: annotations.Ingress{},
+			Port: loadbalancer.PortData{
+				Port:   80,
+				Scheme: elbv2.ProtocolEnumHttp,
+			},
+			TGGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{
+						ServiceName: "service",
+						ServicePort: intstr.FromInt(8080),
+					}: {
+						Arn:             "tgArn",
+						TargetType:      elbv2.TargetTypeEnumInstance,
+						ProtocolVersion: "GRPC",
+					},
+				},
+			},
+			AuthConfig: auth.Config{
+				Type: auth.TypeNone,
+			},
+			ExpectedError: errors.New("failed to build listener config due to invalid default backend: backend service:8080 uses protocol version GRPC, which isn't supported on HTTP listeners, only on HTTPS listeners"),
+		},
 		{
 			Name: "Reconcile failed when creating ingress",
 			Ingress: extensions.Ingress{
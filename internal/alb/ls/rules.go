@@ -17,10 +17,14 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -31,15 +35,17 @@ type RulesController interface {
 }
 
 // NewRulesController constructs RulesController
-func NewRulesController(cloud aws.CloudAPI, authModule auth.Module) RulesController {
+func NewRulesController(cloud aws.CloudAPI, store store.Storer, authModule auth.Module) RulesController {
 	return &rulesController{
 		cloud:      cloud,
+		store:      store,
 		authModule: authModule,
 	}
 }
 
 type rulesController struct {
 	cloud      aws.CloudAPI
+	store      store.Storer
 	authModule auth.Module
 }
 
@@ -124,7 +130,7 @@ func (c *rulesController) getDesiredRules(ctx context.Context, listener *elbv2.L
 	var output []elbv2.Rule
 
 	nextPriority := 1
-	for _, ingressRule := range ingress.Spec.Rules {
+	for ruleIndex, ingressRule := range ingress.Spec.Rules {
 		// Ingress spec allows empty HTTP, and we will 'route all traffic to the default backend'(which relies on default action of listeners)
 		if ingressRule.HTTP == nil {
 			continue
@@ -137,28 +143,57 @@ func (c *rulesController) getDesiredRules(ctx context.Context, listener *elbv2.L
 				// Ignore rules that follow a unconditional redirect, they are moot
 				continue
 			}
-			authCfg, err := c.authModule.NewConfig(ctx, ingress, path.Backend, aws.StringValue(listener.Protocol))
+			pathName, err := parser.GetPathName(ingress, ingressRule.Host, path.Path)
 			if err != nil {
 				return nil, err
 			}
-			elbActions, err := buildActions(ctx, authCfg, ingressAnnos, path.Backend, tgGroup)
+			authCfg, err := c.authModule.NewConfig(ctx, ingress, path.Backend, pathName)
 			if err != nil {
 				return nil, err
 			}
-			elbConditions := buildConditions(ctx, ingressAnnos, ingressRule, path)
-			elbRule := elbv2.Rule{
-				IsDefault:  aws.Bool(false),
-				Priority:   aws.String(strconv.Itoa(nextPriority)),
-				Actions:    elbActions,
-				Conditions: elbConditions,
+			requiredAuthType := authCfg.Type
+			authCfg = buildListenerAuthConfig(aws.StringValue(listener.Protocol), authCfg)
+			backend := ingressAnnos.Action.GetPathBackend(pathName, path)
+			annotationConditions := ingressAnnos.Conditions.GetPathConditions(pathName, path.Backend.ServiceName)
+			elbConditions := buildConditions(ctx, annotationConditions, ingressRule, path)
+			stickinessCfg, err := buildRuleStickinessConfig(c.store, ingress, ingressAnnos, backend, pathName)
+			if err != nil {
+				return nil, err
 			}
-			if createsRedirectLoop(listener, elbRule) {
-				continue
-			} else if isUnconditionalRedirect(listener, elbRule, ingressRule.Host) {
-				seenUnconditionalRedirect = true
+
+			var elbRules []elbv2.Rule
+			if canaryCfg, ok := getPathCanary(ingressAnnos, pathName, backend); ok {
+				if elbRules, err = buildCanaryRules(ctx, authCfg, stickinessCfg, backend, canaryCfg, elbConditions, tgGroup); err != nil {
+					return nil, err
+				}
+			} else {
+				elbActions, err := buildActions(ctx, authCfg, stickinessCfg, ingressAnnos, backend, tgGroup)
+				if err != nil {
+					return nil, err
+				}
+				elbRules = append(elbRules, elbv2.Rule{
+					Actions:    elbActions,
+					Conditions: elbConditions,
+				})
+			}
+
+			for _, elbRule := range elbRules {
+				elbRule.IsDefault = aws.Bool(false)
+				elbRule.Priority = aws.String(strconv.Itoa(nextPriority))
+				if createsRedirectLoop(listener, elbRule) {
+					continue
+				} else if isUnconditionalRedirect(listener, elbRule, ingressRule.Host) {
+					seenUnconditionalRedirect = true
+				}
+				if err := validateListenerAuth(aws.StringValue(listener.Protocol), requiredAuthType, elbRule.Actions); err != nil {
+					return nil, errors.Wrapf(err, "invalid path %v of rule %v", path.Path, ruleIndex)
+				}
+				if err := validateListenerProtocolVersion(aws.StringValue(listener.Protocol), elbRule.Actions, tgGroup); err != nil {
+					return nil, errors.Wrapf(err, "invalid path %v of rule %v", path.Path, ruleIndex)
+				}
+				output = append(output, elbRule)
+				nextPriority++
 			}
-			output = append(output, elbRule)
-			nextPriority++
 		}
 	}
 	return output, nil
@@ -182,8 +217,8 @@ func (c *rulesController) getCurrentRules(ctx context.Context, listenerArn strin
 	return output, nil
 }
 
-// buildActions will build listener rule actions for specific authCfg and backend
-func buildActions(ctx context.Context, authCfg auth.Config, ingressAnnos *annotations.Ingress, backend extensions.IngressBackend, tgGroup tg.TargetGroupGroup) ([]*elbv2.Action, error) {
+// buildActions will build listener rule actions for specific authCfg and backend, forwarding with stickinessCfg unless the action annotation configures it
+func buildActions(ctx context.Context, authCfg auth.Config, stickinessCfg *elbv2.TargetGroupStickinessConfig, ingressAnnos *annotations.Ingress, backend extensions.IngressBackend, tgGroup tg.TargetGroupGroup) ([]*elbv2.Action, error) {
 	var elbActions []*elbv2.Action
 
 	// Handle auth actions
@@ -199,7 +234,7 @@ func buildActions(ctx context.Context, authCfg auth.Config, ingressAnnos *annota
 		if err != nil {
 			return nil, err
 		}
-		annotationELBAction, err := buildAnnotationAction(ctx, annotationAction, tgGroup)
+		annotationELBAction, err := buildAnnotationAction(ctx, annotationAction, stickinessCfg, tgGroup)
 		if err != nil {
 			return nil, err
 		}
@@ -214,15 +249,8 @@ func buildActions(ctx context.Context, authCfg auth.Config, ingressAnnos *annota
 		backendAction := elbv2.Action{
 			Type: aws.String(elbv2.ActionTypeEnumForward),
 			ForwardConfig: &elbv2.ForwardActionConfig{
-				TargetGroups: []*elbv2.TargetGroupTuple{
-					{
-						TargetGroupArn: aws.String(targetGroup.Arn),
-						Weight:         aws.Int64(1),
-					},
-				},
-				TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
-					Enabled: aws.Bool(false),
-				},
+				TargetGroups:                targetGroup.TargetGroupTuples(1),
+				TargetGroupStickinessConfig: stickinessCfg,
 			},
 		}
 		elbActions = append(elbActions, &backendAction)
@@ -234,8 +262,8 @@ func buildActions(ctx context.Context, authCfg auth.Config, ingressAnnos *annota
 	return elbActions, nil
 }
 
-// buildConditions will build listener rule conditions for specific ingressRule
-func buildConditions(ctx context.Context, ingressAnnos *annotations.Ingress, rule extensions.IngressRule, path extensions.HTTPIngressPath) []*elbv2.RuleCondition {
+// buildConditions will build listener rule conditions for specific ingressRule and annotation configured conditions
+func buildConditions(ctx context.Context, annotationConditions []conditions.RuleCondition, rule extensions.IngressRule, path extensions.HTTPIngressPath) []*elbv2.RuleCondition {
 	var elbConditions []*elbv2.RuleCondition
 
 	hostHeaderConfig := &elbv2.HostHeaderConditionConfig{
@@ -250,7 +278,6 @@ func buildConditions(ctx context.Context, ingressAnnos *annotations.Ingress, rul
 	if path.Path != "" {
 		pathPatternConfig.Values = append(pathPatternConfig.Values, aws.String(path.Path))
 	}
-	annotationConditions := ingressAnnos.Conditions.GetConditions(path.Backend.ServiceName)
 	for _, condition := range annotationConditions {
 		switch aws.StringValue(condition.Field) {
 		case conditions.FieldHostHeader:
@@ -319,6 +346,53 @@ func buildConditions(ctx context.Context, ingressAnnos *annotations.Ingress, rul
 	return elbConditions
 }
 
+// buildListenerAuthConfig returns the authCfg to apply on a listener of protocol.
+// ALB supports authentication on HTTPS listeners only.
+func buildListenerAuthConfig(protocol string, authCfg auth.Config) auth.Config {
+	if protocol != elbv2.ProtocolEnumHttps {
+		return auth.Config{Type: auth.TypeNone}
+	}
+	return authCfg
+}
+
+// validateListenerAuth ensures the actions on a listener of protocol don't serve requests without the authentication of
+// requiredAuthType. Listeners that don't support authentication can only redirect such requests, e.g. to HTTPS.
+func validateListenerAuth(protocol string, requiredAuthType auth.Type, actions []*elbv2.Action) error {
+	if requiredAuthType == auth.TypeNone || protocol == elbv2.ProtocolEnumHttps {
+		return nil
+	}
+	if len(actions) != 0 && aws.StringValue(actions[len(actions)-1].Type) == elbv2.ActionTypeEnumRedirect {
+		return nil
+	}
+	return errors.Errorf("%v authentication isn't supported on %v listeners, redirect the requests to %v or set %v to %v",
+		requiredAuthType, protocol, elbv2.ProtocolEnumHttps, parser.GetAnnotationWithPrefix(auth.AnnotationAuthType), auth.TypeNone)
+}
+
+// validateListenerProtocolVersion ensures the actions on a listener of protocol only forward to targetGroups of protocol
+// versions supported by the listener. ELBv2 only supports the HTTP2 and GRPC protocol versions behind HTTPS listeners.
+func validateListenerProtocolVersion(protocol string, actions []*elbv2.Action, tgGroup tg.TargetGroupGroup) error {
+	if protocol == elbv2.ProtocolEnumHttps {
+		return nil
+	}
+	for _, elbAction := range actions {
+		if aws.StringValue(elbAction.Type) != elbv2.ActionTypeEnumForward || elbAction.ForwardConfig == nil {
+			continue
+		}
+		for _, tgTuple := range elbAction.ForwardConfig.TargetGroups {
+			for backend, targetGroup := range tgGroup.TGByBackend {
+				if targetGroup.Arn != aws.StringValue(tgTuple.TargetGroupArn) {
+					continue
+				}
+				if targetGroup.ProtocolVersion == targetgroup.ProtocolVersionHTTP2 || targetGroup.ProtocolVersion == targetgroup.ProtocolVersionGRPC {
+					return errors.Errorf("backend %v:%v uses protocol version %v, which isn't supported on %v listeners, only on %v listeners",
+						backend.ServiceName, backend.ServicePort.String(), targetGroup.ProtocolVersion, protocol, elbv2.ProtocolEnumHttps)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // buildAuthAction builds ELB action for specific authCfg.
 // null will be returned if no auth is required.
 func buildAuthAction(ctx context.Context, authCfg auth.Config) *elbv2.Action {
@@ -364,7 +438,7 @@ func buildAuthAction(ctx context.Context, authCfg auth.Config) *elbv2.Action {
 	return nil
 }
 
-func buildAnnotationAction(ctx context.Context, action action.Action, tgGroup tg.TargetGroupGroup) (*elbv2.Action, error) {
+func buildAnnotationAction(ctx context.Context, action action.Action, stickinessCfg *elbv2.TargetGroupStickinessConfig, tgGroup tg.TargetGroupGroup) (*elbv2.Action, error) {
 	switch aws.StringValue(action.Type) {
 	case elbv2.ActionTypeEnumFixedResponse:
 		return &elbv2.Action{
@@ -388,12 +462,14 @@ func buildAnnotationAction(ctx context.Context, action action.Action, tgGroup tg
 			},
 		}, nil
 	case elbv2.ActionTypeEnumForward:
-		return buildAnnotationForwardAction(ctx, action, tgGroup)
+		return buildAnnotationForwardAction(ctx, action, stickinessCfg, tgGroup)
 	}
 	return nil, errors.Errorf("unknown action type: %v", aws.StringValue(action.Type))
 }
 
-func buildAnnotationForwardAction(ctx context.Context, action action.Action, tgGroup tg.TargetGroupGroup) (*elbv2.Action, error) {
+// buildAnnotationForwardAction will build a forward action configured by annotation.
+// stickinessCfg is used when the annotation doesn't specify TargetGroupStickinessConfig.
+func buildAnnotationForwardAction(ctx context.Context, action action.Action, stickinessCfg *elbv2.TargetGroupStickinessConfig, tgGroup tg.TargetGroupGroup) (*elbv2.Action, error) {
 	var elbTGs []*elbv2.TargetGroupTuple
 	for _, tgt := range action.ForwardConfig.TargetGroups {
 		normalizedWeight := tgt.Weight
@@ -405,6 +481,16 @@ func buildAnnotationForwardAction(ctx context.Context, action action.Action, tgG
 				TargetGroupArn: tgt.TargetGroupArn,
 				Weight:         normalizedWeight,
 			})
+		} else if tgt.LambdaFunctionArn != nil {
+			targetGroup, ok := tgGroup.TGByLambdaFunction[aws.StringValue(tgt.LambdaFunctionArn)]
+			if !ok {
+				return nil, errors.Errorf("unable to find targetGroup for lambda function %v",
+					aws.StringValue(tgt.LambdaFunctionArn))
+			}
+			elbTGs = append(elbTGs, &elbv2.TargetGroupTuple{
+				TargetGroupArn: aws.String(targetGroup.Arn),
+				Weight:         normalizedWeight,
+			})
 		} else {
 			backend := extensions.IngressBackend{
 				ServiceName: aws.StringValue(tgt.ServiceName),
@@ -415,10 +501,7 @@ func buildAnnotationForwardAction(ctx context.Context, action action.Action, tgG
 				return nil, errors.Errorf("unable to find targetGroup for backend %v:%v",
 					backend.ServiceName, backend.ServicePort.String())
 			}
-			elbTGs = append(elbTGs, &elbv2.TargetGroupTuple{
-				TargetGroupArn: aws.String(targetGroup.Arn),
-				Weight:         normalizedWeight,
-			})
+			elbTGs = append(elbTGs, targetGroup.TargetGroupTuples(aws.Int64Value(normalizedWeight))...)
 		}
 	}
 	elbAction := &elbv2.Action{
@@ -434,11 +517,46 @@ func buildAnnotationForwardAction(ctx context.Context, action action.Action, tgG
 			Enabled:         action.ForwardConfig.TargetGroupStickinessConfig.Enabled,
 		}
 	} else {
-		elbAction.ForwardConfig.TargetGroupStickinessConfig = &elbv2.TargetGroupStickinessConfig{
+		elbAction.ForwardConfig.TargetGroupStickinessConfig = stickinessCfg
+	}
+	return elbAction, nil
+}
+
+// buildRuleStickinessConfig builds the stickiness between targetGroups of the forward actions of the path named pathName.
+// It's configured by the rule-stickiness-duration-seconds annotation bound to the path, or else on the service of backend, or else on the ingress.
+// The default actions of listeners have no pathName.
+func buildRuleStickinessConfig(store store.Storer, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress, backend extensions.IngressBackend, pathName string) (*elbv2.TargetGroupStickinessConfig, error) {
+	if ingressAnnos.TargetGroup == nil {
+		return buildTargetGroupStickinessConfig(nil), nil
+	}
+	if durationSeconds, ok := ingressAnnos.TargetGroup.PathRuleStickinessDurationSeconds[pathName]; ok {
+		return buildTargetGroupStickinessConfig(aws.Int64(durationSeconds)), nil
+	}
+	if action.Use(backend.ServicePort.String()) {
+		return buildTargetGroupStickinessConfig(ingressAnnos.TargetGroup.RuleStickinessDurationSeconds), nil
+	}
+	serviceKey := types.NamespacedName{
+		Namespace: ingress.Namespace,
+		Name:      backend.ServiceName,
+	}.String()
+	serviceAnnos, err := store.GetServiceAnnotations(serviceKey, ingressAnnos)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load serviceAnnotation for %v", serviceKey)
+	}
+	return buildTargetGroupStickinessConfig(serviceAnnos.TargetGroup.RuleStickinessDurationSeconds), nil
+}
+
+// buildTargetGroupStickinessConfig builds the stickiness between targetGroups of a forward action, enabled for durationSeconds when set
+func buildTargetGroupStickinessConfig(durationSeconds *int64) *elbv2.TargetGroupStickinessConfig {
+	if durationSeconds == nil {
+		return &elbv2.TargetGroupStickinessConfig{
 			Enabled: aws.Bool(false),
 		}
 	}
-	return elbAction, nil
+	return &elbv2.TargetGroupStickinessConfig{
+		DurationSeconds: durationSeconds,
+		Enabled:         aws.Bool(true),
+	}
 }
 
 // rulesChangeSets compares desired to current, returning a list of rules to add, modify and remove from current to match desired
@@ -540,6 +658,43 @@ func createsRedirectLoop(listener *elbv2.Listener, r elbv2.Rule) bool {
 	return false
 }
 
+// hasCatchAllRedirect checks whether a rule of the ingress without host redirects all requests on listener,
+// such as an ssl-redirect action on `/*`, so the default actions of listener are never served.
+func hasCatchAllRedirect(ctx context.Context, listener *elbv2.Listener, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) bool {
+	for _, ingressRule := range ingress.Spec.Rules {
+		if ingressRule.Host != "" || ingressRule.HTTP == nil {
+			continue
+		}
+		for _, path := range ingressRule.HTTP.Paths {
+			pathName, err := parser.GetPathName(ingress, ingressRule.Host, path.Path)
+			if err != nil {
+				continue
+			}
+			backend := ingressAnnos.Action.GetPathBackend(pathName, path)
+			if !action.Use(backend.ServicePort.String()) {
+				continue
+			}
+			annotationAction, err := ingressAnnos.Action.GetAction(backend.ServiceName)
+			if err != nil || aws.StringValue(annotationAction.Type) != elbv2.ActionTypeEnumRedirect {
+				continue
+			}
+			redirectAction, err := buildAnnotationAction(ctx, annotationAction, nil, tg.TargetGroupGroup{})
+			if err != nil {
+				continue
+			}
+			annotationConditions := ingressAnnos.Conditions.GetPathConditions(pathName, path.Backend.ServiceName)
+			elbRule := elbv2.Rule{
+				Actions:    []*elbv2.Action{redirectAction},
+				Conditions: buildConditions(ctx, annotationConditions, ingressRule, path),
+			}
+			if !createsRedirectLoop(listener, elbRule) && isUnconditionalRedirect(listener, elbRule, ingressRule.Host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // isUnconditionalRedirect checks whether specified rule always redirects
 // We consider the rule is a unconditional redirect if
 // 1) The Path condition is nil, or at least one Path condition is /*
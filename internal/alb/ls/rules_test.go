@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/conditions"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
 	"github.com/pkg/errors"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -14,10 +16,12 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	mock_auth "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/aws-alb-ingress-controller/ingress/auth"
 	"github.com/stretchr/testify/assert"
 	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -38,9 +42,11 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 
 	for _, tc := range []struct {
 		name               string
+		listener           elbv2.Listener
 		ingress            extensions.Ingress
 		ingressAnnos       annotations.Ingress
 		tgGroup            tg.TargetGroupGroup
+		serviceAnnos       map[string]*annotations.Service
 		authNewConfigCalls []AuthNewConfigCall
 		expected           []elbv2.Rule
 		expectedError      error
@@ -489,7 +495,8 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 			},
 		},
 		{
-			name: "two path with an service backend(different auth)",
+			name:     "two path with an service backend(different auth)",
+			listener: elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttps), Port: aws.Int64(443)},
 			ingress: extensions.Ingress{
 				Spec: extensions.IngressSpec{
 					Rules: []extensions.IngressRule{
@@ -677,6 +684,259 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "two path with an ssl redirect and an auth protected service backend on HTTP listener",
+			listener: elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/*",
+											Backend: extensions.IngressBackend{
+												ServiceName: "ssl-redirect",
+												ServicePort: intstr.FromString("use-annotation"),
+											},
+										},
+										{
+											Path: "/path1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service1",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"ssl-redirect": {
+							Type: aws.String(elbv2.ActionTypeEnumRedirect),
+							RedirectConfig: &action.RedirectActionConfig{
+								Port:       aws.String("443"),
+								Protocol:   aws.String(elbv2.ProtocolEnumHttps),
+								StatusCode: aws.String("HTTP_301"),
+							},
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "ssl-redirect",
+						ServicePort: intstr.FromString("use-annotation"),
+					},
+					authCfg: auth.Config{
+						Type: auth.TypeCognito,
+						IDPCognito: auth.IDPCognito{
+							UserPoolArn:      "UserPoolArn",
+							UserPoolClientId: "UserPoolClientId",
+							UserPoolDomain:   "UserPoolDomain",
+						},
+					},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/*"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumRedirect),
+							RedirectConfig: &elbv2.RedirectActionConfig{
+								Port:       aws.String("443"),
+								Protocol:   aws.String(elbv2.ProtocolEnumHttps),
+								StatusCode: aws.String("HTTP_301"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "one path with an auth protected service backend on HTTP listener",
+			listener: elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service1",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action:     &action.Config{},
+				Conditions: &conditions.Config{},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service1", ServicePort: intstr.FromString("http")}: {Arn: "tgArn1"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service1",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{
+						Type: auth.TypeOIDC,
+						IDPOIDC: auth.IDPOIDC{
+							Issuer:   "Issuer",
+							ClientId: "clientId",
+						},
+					},
+				},
+			},
+			expectedError: errors.New("invalid path /path1 of rule 0: oidc authentication isn't supported on HTTP listeners, redirect the requests to HTTPS or set alb.ingress.kubernetes.io/auth-type to none"),
+		},
+		{
+			name:     "one path with a GRPC service backend on HTTP listener",
+			listener: elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service1",
+												ServicePort: intstr.FromString("grpc"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action:     &action.Config{},
+				Conditions: &conditions.Config{},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service1", ServicePort: intstr.FromString("grpc")}: {Arn: "tgArn1", ProtocolVersion: "GRPC"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service1",
+						ServicePort: intstr.FromString("grpc"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expectedError: errors.New("invalid path /path1 of rule 0: backend service1:grpc uses protocol version GRPC, which isn't supported on HTTP listeners, only on HTTPS listeners"),
+		},
+		{
+			name: "one path with a service backend whose targetGroup replaces another",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action:     &action.Config{},
+				Conditions: &conditions.Config{},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}: {Arn: "tgArn2", ReplacedArns: []string{"tgArn1"}},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/path"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String("forward"),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{TargetGroupArn: aws.String("tgArn1"), Weight: aws.Int64(1)},
+									{TargetGroupArn: aws.String("tgArn2"), Weight: aws.Int64(0)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "one path with host/path condition",
 			ingress: extensions.Ingress{
@@ -1106,7 +1366,7 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 			},
 		},
 		{
-			name: "one path without host/path and with annotation path condition",
+			name: "one path with an annotation lambda function backend",
 			ingress: extensions.Ingress{
 				Spec: extensions.IngressSpec{
 					Rules: []extensions.IngressRule{
@@ -1115,8 +1375,9 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 								HTTP: &extensions.HTTPIngressRuleValue{
 									Paths: []extensions.HTTPIngressPath{
 										{
+											Path: "/lambda",
 											Backend: extensions.IngressBackend{
-												ServiceName: "anno-svc",
+												ServiceName: "anno-lambda",
 												ServicePort: intstr.FromString("use-annotation"),
 											},
 										},
@@ -1130,14 +1391,12 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 			ingressAnnos: annotations.Ingress{
 				Action: &action.Config{
 					Actions: map[string]action.Action{
-						"anno-svc": {
+						"anno-lambda": {
 							Type: aws.String(elbv2.ActionTypeEnumForward),
 							ForwardConfig: &action.ForwardActionConfig{
 								TargetGroups: []*action.TargetGroupTuple{
 									{
-										ServiceName: aws.String("service"),
-										ServicePort: aws.String("http"),
-										Weight:      aws.Int64(1),
+										LambdaFunctionArn: aws.String("arn:aws:lambda:us-west-2:123456789012:function:my-function"),
 									},
 								},
 							},
@@ -1145,27 +1404,18 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 					},
 				},
 				Conditions: &conditions.Config{
-					Conditions: map[string][]conditions.RuleCondition{
-						"anno-svc": {
-							{
-								Field: aws.String(conditions.FieldPathPattern),
-								PathPatternConfig: &conditions.PathPatternConditionConfig{
-									Values: aws.StringSlice([]string{"/anno"}),
-								},
-							},
-						},
-					},
+					Conditions: nil,
 				},
 			},
 			tgGroup: tg.TargetGroupGroup{
-				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
-					{ServiceName: "service", ServicePort: intstr.FromString("http")}: {Arn: "tgArn"},
+				TGByLambdaFunction: map[string]tg.TargetGroup{
+					"arn:aws:lambda:us-west-2:123456789012:function:my-function": {Arn: "lambdaTGArn"},
 				},
 			},
 			authNewConfigCalls: []AuthNewConfigCall{
 				{
 					backend: extensions.IngressBackend{
-						ServiceName: "anno-svc",
+						ServiceName: "anno-lambda",
 						ServicePort: intstr.FromString("use-annotation"),
 					},
 					authCfg: auth.Config{Type: auth.TypeNone},
@@ -1179,20 +1429,20 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 						{
 							Field: aws.String(conditions.FieldPathPattern),
 							PathPatternConfig: &elbv2.PathPatternConditionConfig{
-								Values: aws.StringSlice([]string{"/anno"}),
+								Values: aws.StringSlice([]string{"/lambda"}),
 							},
 						},
 					},
 					Actions: []*elbv2.Action{
 						{
 							Order: aws.Int64(1),
-							Type:  aws.String("forward"),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
 							ForwardConfig: &elbv2.ForwardActionConfig{
 								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
 									Enabled: aws.Bool(false),
 								},
 								TargetGroups: []*elbv2.TargetGroupTuple{
-									{TargetGroupArn: aws.String("tgArn"),
+									{TargetGroupArn: aws.String("lambdaTGArn"),
 										Weight: aws.Int64(1),
 									},
 								},
@@ -1203,19 +1453,18 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 			},
 		},
 		{
-			name: "one path with host/path and with annotation path condition",
+			name: "one path with an annotation lambda function backend(refers to missing targetGroup)",
 			ingress: extensions.Ingress{
 				Spec: extensions.IngressSpec{
 					Rules: []extensions.IngressRule{
 						{
-							Host: "www.example.com",
 							IngressRuleValue: extensions.IngressRuleValue{
 								HTTP: &extensions.HTTPIngressRuleValue{
 									Paths: []extensions.HTTPIngressPath{
 										{
-											Path: "/path",
+											Path: "/lambda",
 											Backend: extensions.IngressBackend{
-												ServiceName: "anno-svc",
+												ServiceName: "anno-lambda",
 												ServicePort: intstr.FromString("use-annotation"),
 											},
 										},
@@ -1229,14 +1478,12 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 			ingressAnnos: annotations.Ingress{
 				Action: &action.Config{
 					Actions: map[string]action.Action{
-						"anno-svc": {
+						"anno-lambda": {
 							Type: aws.String(elbv2.ActionTypeEnumForward),
 							ForwardConfig: &action.ForwardActionConfig{
 								TargetGroups: []*action.TargetGroupTuple{
 									{
-										ServiceName: aws.String("service"),
-										ServicePort: aws.String("http"),
-										Weight:      aws.Int64(1),
+										LambdaFunctionArn: aws.String("arn:aws:lambda:us-west-2:123456789012:function:my-function"),
 									},
 								},
 							},
@@ -1244,15 +1491,168 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 					},
 				},
 				Conditions: &conditions.Config{
-					Conditions: map[string][]conditions.RuleCondition{
-						"anno-svc": {
-							{
-								Field: aws.String(conditions.FieldHostHeader),
-								HostHeaderConfig: &conditions.HostHeaderConditionConfig{
-									Values: aws.StringSlice([]string{"anno.example.com"}),
-								},
-							},
-							{
+					Conditions: nil,
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "anno-lambda",
+						ServicePort: intstr.FromString("use-annotation"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expectedError: errors.New("unable to find targetGroup for lambda function arn:aws:lambda:us-west-2:123456789012:function:my-function"),
+		},
+		{
+			name: "one path without host/path and with annotation path condition",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Backend: extensions.IngressBackend{
+												ServiceName: "anno-svc",
+												ServicePort: intstr.FromString("use-annotation"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"anno-svc": {
+							Type: aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &action.ForwardActionConfig{
+								TargetGroups: []*action.TargetGroupTuple{
+									{
+										ServiceName: aws.String("service"),
+										ServicePort: aws.String("http"),
+										Weight:      aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				Conditions: &conditions.Config{
+					Conditions: map[string][]conditions.RuleCondition{
+						"anno-svc": {
+							{
+								Field: aws.String(conditions.FieldPathPattern),
+								PathPatternConfig: &conditions.PathPatternConditionConfig{
+									Values: aws.StringSlice([]string{"/anno"}),
+								},
+							},
+						},
+					},
+				},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}: {Arn: "tgArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "anno-svc",
+						ServicePort: intstr.FromString("use-annotation"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/anno"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String("forward"),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{TargetGroupArn: aws.String("tgArn"),
+										Weight: aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with host/path and with annotation path condition",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							Host: "www.example.com",
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path",
+											Backend: extensions.IngressBackend{
+												ServiceName: "anno-svc",
+												ServicePort: intstr.FromString("use-annotation"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"anno-svc": {
+							Type: aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &action.ForwardActionConfig{
+								TargetGroups: []*action.TargetGroupTuple{
+									{
+										ServiceName: aws.String("service"),
+										ServicePort: aws.String("http"),
+										Weight:      aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				Conditions: &conditions.Config{
+					Conditions: map[string][]conditions.RuleCondition{
+						"anno-svc": {
+							{
+								Field: aws.String(conditions.FieldHostHeader),
+								HostHeaderConfig: &conditions.HostHeaderConditionConfig{
+									Values: aws.StringSlice([]string{"anno.example.com"}),
+								},
+							},
+							{
 								Field: aws.String(conditions.FieldPathPattern),
 								PathPatternConfig: &conditions.PathPatternConditionConfig{
 									Values: aws.StringSlice([]string{"/anno"}),
@@ -1697,14 +2097,860 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 					Actions: []*elbv2.Action{
 						{
 							Order: aws.Int64(1),
-							Type:  aws.String("forward"),
+							Type:  aws.String("forward"),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{TargetGroupArn: aws.String("tgArn"),
+										Weight: aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "paths with actions and conditions bound by path name",
+			ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"alb.ingress.kubernetes.io/path-names": `{"v2": "/v2", "maintenance": "/v3"}`,
+					},
+				},
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/v1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+										{
+											Path: "/v2",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+										{
+											Path: "/v3",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"maintenance": fixedResponseAction,
+					},
+				},
+				Conditions: &conditions.Config{
+					Conditions: map[string][]conditions.RuleCondition{
+						"service": {
+							{
+								Field: aws.String(conditions.FieldHTTPHeader),
+								HttpHeaderConfig: &conditions.HttpHeaderConditionConfig{
+									HttpHeaderName: aws.String("HeaderName"),
+									Values:         aws.StringSlice([]string{"service"}),
+								},
+							},
+						},
+						"v2": {
+							{
+								Field: aws.String(conditions.FieldHTTPHeader),
+								HttpHeaderConfig: &conditions.HttpHeaderConditionConfig{
+									HttpHeaderName: aws.String("HeaderName"),
+									Values:         aws.StringSlice([]string{"path-1"}),
+								},
+							},
+						},
+					},
+				},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}: {Arn: "tgArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("HeaderName"),
+								Values:         aws.StringSlice([]string{"service"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/v1"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("tgArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("2"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("HeaderName"),
+								Values:         aws.StringSlice([]string{"path-1"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/v2"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("tgArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("3"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("HeaderName"),
+								Values:         aws.StringSlice([]string{"service"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/v3"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumFixedResponse),
+							FixedResponseConfig: &elbv2.FixedResponseActionConfig{
+								ContentType: aws.String("text/plain"),
+								StatusCode:  aws.String("503"),
+								MessageBody: aws.String("message body"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with a canary",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"service": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+							Weight:      aws.Int64(10),
+							HeaderMatch: &canary.HeaderMatch{
+								HttpHeaderName: aws.String("X-Canary"),
+								Values:         aws.StringSlice([]string{"true"}),
+							},
+							CookieMatch: &canary.CookieMatch{
+								Name:  aws.String("canary"),
+								Value: aws.String("always"),
+							},
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}:    {Arn: "stableTGArn"},
+					{ServiceName: "service-v2", ServicePort: intstr.FromString("http")}: {Arn: "canaryTGArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("X-Canary"),
+								Values:         aws.StringSlice([]string{"true"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("2"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("Cookie"),
+								Values:         aws.StringSlice([]string{"canary=always"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("3"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("Cookie"),
+								Values:         aws.StringSlice([]string{"canary=always;*"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("4"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("Cookie"),
+								Values:         aws.StringSlice([]string{"*; canary=always"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("5"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("Cookie"),
+								Values:         aws.StringSlice([]string{"*; canary=always;*"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(1),
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("6"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									Enabled: aws.Bool(false),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("stableTGArn"),
+										Weight:         aws.Int64(90),
+									},
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(10),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with wildcards of a wildcard host and a canary exceeding the wildcard limits",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							Host: "*.example.com",
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app/*/*/*",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"service": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+							Weight:      aws.Int64(10),
+							CookieMatch: &canary.CookieMatch{
+								Name:  aws.String("canary"),
+								Value: aws.String("always"),
+							},
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}:    {Arn: "stableTGArn"},
+					{ServiceName: "service-v2", ServicePort: intstr.FromString("http")}: {Arn: "canaryTGArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expectedError: errors.New("invalid canary for service: rule has 6 wildcards, exceeding the limit of 5"),
+		},
+		{
+			name: "one path with a weighted canary and rule stickiness",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"service": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+							Weight:      aws.Int64(20),
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+				TargetGroup: &targetgroup.Config{
+					RuleStickinessDurationSeconds: aws.Int64(300),
+				},
+			},
+			serviceAnnos: map[string]*annotations.Service{
+				"/service": {TargetGroup: &targetgroup.Config{RuleStickinessDurationSeconds: aws.Int64(300)}},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}:    {Arn: "stableTGArn"},
+					{ServiceName: "service-v2", ServicePort: intstr.FromString("http")}: {Arn: "canaryTGArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									DurationSeconds: aws.Int64(300),
+									Enabled:         aws.Bool(true),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("stableTGArn"),
+										Weight:         aws.Int64(80),
+									},
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(20),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with a weighted canary and rule stickiness bound to the path",
+			ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"alb.ingress.kubernetes.io/path-names": `{"app": "/app"}`,
+					},
+				},
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"service": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+							Weight:      aws.Int64(20),
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+				TargetGroup: &targetgroup.Config{
+					RuleStickinessDurationSeconds:     aws.Int64(300),
+					PathRuleStickinessDurationSeconds: map[string]int64{"app": 60},
+				},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}:    {Arn: "stableTGArn"},
+					{ServiceName: "service-v2", ServicePort: intstr.FromString("http")}: {Arn: "canaryTGArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
+							ForwardConfig: &elbv2.ForwardActionConfig{
+								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+									DurationSeconds: aws.Int64(60),
+									Enabled:         aws.Bool(true),
+								},
+								TargetGroups: []*elbv2.TargetGroupTuple{
+									{
+										TargetGroupArn: aws.String("stableTGArn"),
+										Weight:         aws.Int64(80),
+									},
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(20),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with a weighted canary and rule stickiness of the service",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"service": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+							Weight:      aws.Int64(20),
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+				TargetGroup: &targetgroup.Config{
+					RuleStickinessDurationSeconds: aws.Int64(300),
+				},
+			},
+			serviceAnnos: map[string]*annotations.Service{
+				"/service": {TargetGroup: &targetgroup.Config{RuleStickinessDurationSeconds: aws.Int64(600)}},
+			},
+			tgGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service", ServicePort: intstr.FromString("http")}:    {Arn: "stableTGArn"},
+					{ServiceName: "service-v2", ServicePort: intstr.FromString("http")}: {Arn: "canaryTGArn"},
+				},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expected: []elbv2.Rule{
+				{
+					IsDefault: aws.Bool(false),
+					Priority:  aws.String("1"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{
+						{
+							Order: aws.Int64(1),
+							Type:  aws.String(elbv2.ActionTypeEnumForward),
 							ForwardConfig: &elbv2.ForwardActionConfig{
 								TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
-									Enabled: aws.Bool(false),
+									DurationSeconds: aws.Int64(600),
+									Enabled:         aws.Bool(true),
 								},
 								TargetGroups: []*elbv2.TargetGroupTuple{
-									{TargetGroupArn: aws.String("tgArn"),
-										Weight: aws.Int64(1),
+									{
+										TargetGroupArn: aws.String("stableTGArn"),
+										Weight:         aws.Int64(80),
+									},
+									{
+										TargetGroupArn: aws.String("canaryTGArn"),
+										Weight:         aws.Int64(20),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one path with a canary for an annotation backend",
+			ingress: extensions.Ingress{
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/app",
+											Backend: extensions.IngressBackend{
+												ServiceName: "fixed-response-action",
+												ServicePort: intstr.FromString("use-annotation"),
+											},
+										},
 									},
 								},
 							},
@@ -1712,6 +2958,32 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 					},
 				},
 			},
+			ingressAnnos: annotations.Ingress{
+				Action: &action.Config{
+					Actions: map[string]action.Action{
+						"fixed-response-action": fixedResponseAction,
+					},
+				},
+				Canary: &canary.Config{
+					Canaries: map[string]canary.Canary{
+						"fixed-response-action": {
+							ServiceName: aws.String("service-v2"),
+							ServicePort: aws.String("http"),
+						},
+					},
+				},
+				Conditions: &conditions.Config{},
+			},
+			authNewConfigCalls: []AuthNewConfigCall{
+				{
+					backend: extensions.IngressBackend{
+						ServiceName: "fixed-response-action",
+						ServicePort: intstr.FromString("use-annotation"),
+					},
+					authCfg: auth.Config{Type: auth.TypeNone},
+				},
+			},
+			expectedError: errors.New("canary for fixed-response-action requires a backend with a service, got `servicePort: use-annotation`"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1724,12 +2996,18 @@ func Test_rulesController_getDesiredRules(t *testing.T) {
 				mockAuthModule.EXPECT().NewConfig(gomock.Any(), &tc.ingress, call.backend, gomock.Any()).Return(call.authCfg, nil)
 			}
 
+			mockStore := &store.MockStorer{}
+			for key, serviceAnnos := range tc.serviceAnnos {
+				mockStore.On("GetServiceAnnotations", key, &tc.ingressAnnos).Return(serviceAnnos, nil)
+			}
+
 			c := &rulesController{
 				cloud:      cloud,
+				store:      mockStore,
 				authModule: mockAuthModule,
 			}
 
-			got, err := c.getDesiredRules(context.Background(), &elbv2.Listener{}, &tc.ingress, &tc.ingressAnnos, tc.tgGroup)
+			got, err := c.getDesiredRules(context.Background(), &tc.listener, &tc.ingress, &tc.ingressAnnos, tc.tgGroup)
 			assert.Equal(t, tc.expected, got)
 			if tc.expectedError == nil {
 				assert.NoError(t, err)
@@ -2176,6 +3454,173 @@ func Test_reconcileRules(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Shift canary weight modifies the weighted rule only",
+			current: []elbv2.Rule{
+				{
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("X-Canary"),
+								Values:         aws.StringSlice([]string{"true"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{{
+						Order: aws.Int64(1),
+						Type:  aws.String(elbv2.ActionTypeEnumForward),
+						ForwardConfig: &elbv2.ForwardActionConfig{
+							TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+								Enabled: aws.Bool(false),
+							},
+							TargetGroups: []*elbv2.TargetGroupTuple{
+								{
+									TargetGroupArn: aws.String("canaryTGArn"),
+									Weight:         aws.Int64(1),
+								},
+							},
+						},
+					}},
+					RuleArn:  aws.String("ruleArn1"),
+					Priority: aws.String("1"),
+				},
+				{
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{{
+						Order: aws.Int64(1),
+						Type:  aws.String(elbv2.ActionTypeEnumForward),
+						ForwardConfig: &elbv2.ForwardActionConfig{
+							TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+								Enabled: aws.Bool(false),
+							},
+							TargetGroups: []*elbv2.TargetGroupTuple{
+								{
+									TargetGroupArn: aws.String("stableTGArn"),
+									Weight:         aws.Int64(90),
+								},
+								{
+									TargetGroupArn: aws.String("canaryTGArn"),
+									Weight:         aws.Int64(10),
+								},
+							},
+						},
+					}},
+					RuleArn:  aws.String("ruleArn2"),
+					Priority: aws.String("2"),
+				},
+			},
+			desired: []elbv2.Rule{
+				{
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldHTTPHeader),
+							HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+								HttpHeaderName: aws.String("X-Canary"),
+								Values:         aws.StringSlice([]string{"true"}),
+							},
+						},
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{{
+						Order: aws.Int64(1),
+						Type:  aws.String(elbv2.ActionTypeEnumForward),
+						ForwardConfig: &elbv2.ForwardActionConfig{
+							TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+								Enabled: aws.Bool(false),
+							},
+							TargetGroups: []*elbv2.TargetGroupTuple{
+								{
+									TargetGroupArn: aws.String("canaryTGArn"),
+									Weight:         aws.Int64(1),
+								},
+							},
+						},
+					}},
+					Priority: aws.String("1"),
+				},
+				{
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{{
+						Order: aws.Int64(1),
+						Type:  aws.String(elbv2.ActionTypeEnumForward),
+						ForwardConfig: &elbv2.ForwardActionConfig{
+							TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+								Enabled: aws.Bool(false),
+							},
+							TargetGroups: []*elbv2.TargetGroupTuple{
+								{
+									TargetGroupArn: aws.String("stableTGArn"),
+									Weight:         aws.Int64(80),
+								},
+								{
+									TargetGroupArn: aws.String("canaryTGArn"),
+									Weight:         aws.Int64(20),
+								},
+							},
+						},
+					}},
+					Priority: aws.String("2"),
+				},
+			},
+			modifyRuleCall: &ModifyRuleCall{
+				Input: &elbv2.ModifyRuleInput{
+					RuleArn: aws.String("ruleArn2"),
+					Conditions: []*elbv2.RuleCondition{
+						{
+							Field: aws.String(conditions.FieldPathPattern),
+							PathPatternConfig: &elbv2.PathPatternConditionConfig{
+								Values: aws.StringSlice([]string{"/app"}),
+							},
+						},
+					},
+					Actions: []*elbv2.Action{{
+						Order: aws.Int64(1),
+						Type:  aws.String(elbv2.ActionTypeEnumForward),
+						ForwardConfig: &elbv2.ForwardActionConfig{
+							TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{
+								Enabled: aws.Bool(false),
+							},
+							TargetGroups: []*elbv2.TargetGroupTuple{
+								{
+									TargetGroupArn: aws.String("stableTGArn"),
+									Weight:         aws.Int64(80),
+								},
+								{
+									TargetGroupArn: aws.String("canaryTGArn"),
+									Weight:         aws.Int64(20),
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
 		{
 			name:    "CreateRule error",
 			current: []elbv2.Rule{},
@@ -2878,6 +4323,79 @@ func Test_createsRedirectLoop(t *testing.T) {
 	}
 }
 
+func Test_hasCatchAllRedirect(t *testing.T) {
+	sslRedirectAnnos := annotations.Ingress{
+		Action: &action.Config{
+			Actions: map[string]action.Action{
+				"ssl-redirect": {
+					Type: aws.String(elbv2.ActionTypeEnumRedirect),
+					RedirectConfig: &action.RedirectActionConfig{
+						Port:       aws.String("443"),
+						Protocol:   aws.String(elbv2.ProtocolEnumHttps),
+						StatusCode: aws.String("HTTP_301"),
+					},
+				},
+			},
+		},
+		Conditions: &conditions.Config{},
+	}
+	ingressRule := func(host string, path string, serviceName string, servicePort intstr.IntOrString) extensions.IngressRule {
+		return extensions.IngressRule{
+			Host: host,
+			IngressRuleValue: extensions.IngressRuleValue{
+				HTTP: &extensions.HTTPIngressRuleValue{
+					Paths: []extensions.HTTPIngressPath{
+						{
+							Path: path,
+							Backend: extensions.IngressBackend{
+								ServiceName: serviceName,
+								ServicePort: servicePort,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		listener     elbv2.Listener
+		ingressRules []extensions.IngressRule
+		expected     bool
+	}{
+		{
+			name:         "ssl-redirect on /* without host",
+			listener:     elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingressRules: []extensions.IngressRule{ingressRule("", "/*", "ssl-redirect", intstr.FromString("use-annotation"))},
+			expected:     true,
+		},
+		{
+			name:         "ssl-redirect on /* with host",
+			listener:     elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingressRules: []extensions.IngressRule{ingressRule("example.com", "/*", "ssl-redirect", intstr.FromString("use-annotation"))},
+			expected:     false,
+		},
+		{
+			name:         "ssl-redirect on a path",
+			listener:     elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingressRules: []extensions.IngressRule{ingressRule("", "/path1", "ssl-redirect", intstr.FromString("use-annotation"))},
+			expected:     false,
+		},
+		{
+			name:         "service backend on /*",
+			listener:     elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttp), Port: aws.Int64(80)},
+			ingressRules: []extensions.IngressRule{ingressRule("", "/*", "service", intstr.FromInt(80))},
+			expected:     false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := &extensions.Ingress{Spec: extensions.IngressSpec{Rules: tc.ingressRules}}
+			assert.Equal(t, tc.expected, hasCatchAllRedirect(context.Background(), &tc.listener, ingress, &sslRedirectAnnos))
+		})
+	}
+}
+
 func Test_isUnconditionalRedirect(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
@@ -3201,3 +4719,61 @@ func Test_redactActions(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateRuleConditionLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		conditions    []*elbv2.RuleCondition
+		expectedError error
+	}{
+		{
+			name: "cookie pattern on a host and a wildcard path",
+			conditions: []*elbv2.RuleCondition{
+				{
+					Field: aws.String(conditions.FieldHTTPHeader),
+					HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+						HttpHeaderName: aws.String("Cookie"),
+						Values:         aws.StringSlice([]string{"*; canary=always;*"}),
+					},
+				},
+				{
+					Field:            aws.String(conditions.FieldHostHeader),
+					HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: aws.StringSlice([]string{"www.example.com"})},
+				},
+				{
+					Field:             aws.String(conditions.FieldPathPattern),
+					PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{"/*"})},
+				},
+			},
+		},
+		{
+			name: "too many wildcards",
+			conditions: []*elbv2.RuleCondition{
+				{
+					Field:            aws.String(conditions.FieldHostHeader),
+					HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: aws.StringSlice([]string{"*.example.com"})},
+				},
+				{
+					Field: aws.String(conditions.FieldQueryString),
+					QueryStringConfig: &elbv2.QueryStringConditionConfig{
+						Values: []*elbv2.QueryStringKeyValuePair{{Key: aws.String("version"), Value: aws.String("v?")}},
+					},
+				},
+				{
+					Field:             aws.String(conditions.FieldPathPattern),
+					PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{"/*/app/*/*/*"})},
+				},
+			},
+			expectedError: errors.New("rule has 6 wildcards, exceeding the limit of 5"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRuleConditionLimits(tc.conditions)
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError.Error())
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
@@ -13,19 +14,23 @@ import (
 )
 
 const (
-	DeregistrationDelayTimeoutSecondsKey = "deregistration_delay.timeout_seconds"
-	SlowStartDurationSecondsKey          = "slow_start.duration_seconds"
-	StickinessEnabledKey                 = "stickiness.enabled"
-	StickinessTypeKey                    = "stickiness.type"
-	StickinessLbCookieDurationSecondsKey = "stickiness.lb_cookie.duration_seconds"
-	LoadBalancingAlgorithmTypeKey        = "load_balancing.algorithm.type"
-
-	DeregistrationDelayTimeoutSeconds = 300
-	SlowStartDurationSeconds          = 0
-	StickinessEnabled                 = false
-	StickinessType                    = "lb_cookie"
-	StickinessLbCookieDurationSeconds = 86400
-	LoadBalancingAlgorithmType        = "round_robin"
+	DeregistrationDelayTimeoutSecondsKey  = "deregistration_delay.timeout_seconds"
+	SlowStartDurationSecondsKey           = "slow_start.duration_seconds"
+	StickinessEnabledKey                  = "stickiness.enabled"
+	StickinessTypeKey                     = "stickiness.type"
+	StickinessLbCookieDurationSecondsKey  = "stickiness.lb_cookie.duration_seconds"
+	StickinessAppCookieCookieNameKey      = "stickiness.app_cookie.cookie_name"
+	StickinessAppCookieDurationSecondsKey = "stickiness.app_cookie.duration_seconds"
+	LoadBalancingAlgorithmTypeKey         = "load_balancing.algorithm.type"
+
+	DeregistrationDelayTimeoutSeconds  = 300
+	SlowStartDurationSeconds           = 0
+	StickinessEnabled                  = false
+	StickinessType                     = "lb_cookie"
+	StickinessLbCookieDurationSeconds  = 86400
+	StickinessAppCookieCookieName      = ""
+	StickinessAppCookieDurationSeconds = 86400
+	LoadBalancingAlgorithmType         = "round_robin"
 )
 
 // Attributes represents the desired state of attributes for a target group.
@@ -47,8 +52,8 @@ type Attributes struct {
 	// The value is true or false. The default is false.
 	StickinessEnabled bool
 
-	// StickinessType: stickiness.type - The type of sticky sessions. The possible value is
-	// lb_cookie.
+	// StickinessType: stickiness.type - The type of sticky sessions. The possible values are
+	// lb_cookie and app_cookie.
 	StickinessType string
 
 	// StickinessLbCookieDurationSeconds: stickiness.lb_cookie.duration_seconds - The time period, in seconds,
@@ -58,20 +63,36 @@ type Attributes struct {
 	// default value is 1 day (86400 seconds).
 	StickinessLbCookieDurationSeconds int64
 
+	// StickinessAppCookieCookieName: stickiness.app_cookie.cookie_name - The name of the application-based
+	// cookie. It is required when stickiness.type is app_cookie.
+	StickinessAppCookieCookieName string
+
+	// StickinessAppCookieDurationSeconds: stickiness.app_cookie.duration_seconds - The time period, in seconds,
+	// during which requests from a client should be routed to the same target.
+	// After this time period expires, the application-based cookie is considered
+	// stale. The range is 1 second to 1 week (604800 seconds). The default value
+	// is 1 day (86400 seconds).
+	StickinessAppCookieDurationSeconds int64
+
 	// LoadBalancingAlgorithmType: load_balancing.algorithm.type - The load balancing algorithm determines
 	// how the load balancer selects targets when routing requests. The value is round_robin or
 	// least_outstanding_requests. The default is round_robin.
 	LoadBalancingAlgorithmType string
 }
 
+// reservedCookieNamePrefixes are cookie name prefixes reserved for use by the load balancer
+var reservedCookieNamePrefixes = []string{"AWSALB", "AWSALBAPP", "AWSALBTG"}
+
 func NewAttributes(attrs []*elbv2.TargetGroupAttribute) (a *Attributes, err error) {
 	a = &Attributes{
-		DeregistrationDelayTimeoutSeconds: DeregistrationDelayTimeoutSeconds,
-		SlowStartDurationSeconds:          SlowStartDurationSeconds,
-		StickinessEnabled:                 StickinessEnabled,
-		StickinessType:                    StickinessType,
-		StickinessLbCookieDurationSeconds: StickinessLbCookieDurationSeconds,
-		LoadBalancingAlgorithmType:        LoadBalancingAlgorithmType,
+		DeregistrationDelayTimeoutSeconds:  DeregistrationDelayTimeoutSeconds,
+		SlowStartDurationSeconds:           SlowStartDurationSeconds,
+		StickinessEnabled:                  StickinessEnabled,
+		StickinessType:                     StickinessType,
+		StickinessLbCookieDurationSeconds:  StickinessLbCookieDurationSeconds,
+		StickinessAppCookieCookieName:      StickinessAppCookieCookieName,
+		StickinessAppCookieDurationSeconds: StickinessAppCookieDurationSeconds,
+		LoadBalancingAlgorithmType:         LoadBalancingAlgorithmType,
 	}
 	var e error
 	for _, attr := range attrs {
@@ -100,7 +121,7 @@ func NewAttributes(attrs []*elbv2.TargetGroupAttribute) (a *Attributes, err erro
 			}
 		case StickinessTypeKey:
 			a.StickinessType = attrValue
-			if attrValue != "lb_cookie" {
+			if attrValue != "lb_cookie" && attrValue != "app_cookie" {
 				return a, fmt.Errorf("invalid target group attribute value %s=%s", attrKey, attrValue)
 			}
 		case StickinessLbCookieDurationSecondsKey:
@@ -111,6 +132,21 @@ func NewAttributes(attrs []*elbv2.TargetGroupAttribute) (a *Attributes, err erro
 			if a.StickinessLbCookieDurationSeconds < 1 || a.StickinessLbCookieDurationSeconds > 604800 {
 				return a, fmt.Errorf("%s must be within 1-604800 seconds, not %v", attrKey, attrValue)
 			}
+		case StickinessAppCookieCookieNameKey:
+			a.StickinessAppCookieCookieName = attrValue
+			for _, prefix := range reservedCookieNamePrefixes {
+				if strings.HasPrefix(attrValue, prefix) {
+					return a, fmt.Errorf("%s must not start with %v, not %v", attrKey, prefix, attrValue)
+				}
+			}
+		case StickinessAppCookieDurationSecondsKey:
+			a.StickinessAppCookieDurationSeconds, err = strconv.ParseInt(attrValue, 10, 64)
+			if err != nil {
+				return a, fmt.Errorf("invalid target group attribute value %s=%s", attrKey, attrValue)
+			}
+			if a.StickinessAppCookieDurationSeconds < 1 || a.StickinessAppCookieDurationSeconds > 604800 {
+				return a, fmt.Errorf("%s must be within 1-604800 seconds, not %v", attrKey, attrValue)
+			}
 		case LoadBalancingAlgorithmTypeKey:
 			a.LoadBalancingAlgorithmType = attrValue
 			if attrValue != "round_robin" && attrValue != "least_outstanding_requests" {
@@ -120,6 +156,9 @@ func NewAttributes(attrs []*elbv2.TargetGroupAttribute) (a *Attributes, err erro
 			e = NewInvalidAttribute(attrKey)
 		}
 	}
+	if a.StickinessEnabled && a.StickinessType == "app_cookie" && a.StickinessAppCookieCookieName == "" {
+		return a, fmt.Errorf("%s is required when %s=app_cookie", StickinessAppCookieCookieNameKey, StickinessTypeKey)
+	}
 	return a, e
 }
 
@@ -193,6 +232,15 @@ func attributesChangeSet(a, b *Attributes) (changeSet []*elbv2.TargetGroupAttrib
 		changeSet = append(changeSet, tgAttribute(StickinessLbCookieDurationSecondsKey, fmt.Sprintf("%v", b.StickinessLbCookieDurationSeconds)))
 	}
 
+	// the cookie name can't be reset, and is only required while stickiness.type is app_cookie
+	if a.StickinessAppCookieCookieName != b.StickinessAppCookieCookieName && b.StickinessAppCookieCookieName != "" {
+		changeSet = append(changeSet, tgAttribute(StickinessAppCookieCookieNameKey, b.StickinessAppCookieCookieName))
+	}
+
+	if a.StickinessAppCookieDurationSeconds != b.StickinessAppCookieDurationSeconds {
+		changeSet = append(changeSet, tgAttribute(StickinessAppCookieDurationSecondsKey, fmt.Sprintf("%v", b.StickinessAppCookieDurationSeconds)))
+	}
+
 	if a.LoadBalancingAlgorithmType != b.LoadBalancingAlgorithmType {
 		changeSet = append(changeSet, tgAttribute(LoadBalancingAlgorithmTypeKey, b.LoadBalancingAlgorithmType))
 	}
@@ -105,10 +105,65 @@ func Test_NewAttributes(t *testing.T) {
 			output:     MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessTypeKey, "lb_cookie")}),
 		},
 		{
-			name:       "StickinessTypeKey is not lb_cookie",
+			name:       "StickinessTypeKey is not lb_cookie or app_cookie",
 			ok:         false,
 			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessTypeKey, "not lb_cookie")},
 		},
+		{
+			name: "StickinessTypeKey is app_cookie",
+			ok:   true,
+			attributes: []*elbv2.TargetGroupAttribute{
+				tgAttribute(StickinessEnabledKey, "true"),
+				tgAttribute(StickinessTypeKey, "app_cookie"),
+				tgAttribute(StickinessAppCookieCookieNameKey, "session"),
+			},
+			output: &Attributes{
+				DeregistrationDelayTimeoutSeconds:  DeregistrationDelayTimeoutSeconds,
+				SlowStartDurationSeconds:           SlowStartDurationSeconds,
+				StickinessEnabled:                  true,
+				StickinessType:                     "app_cookie",
+				StickinessLbCookieDurationSeconds:  StickinessLbCookieDurationSeconds,
+				StickinessAppCookieCookieName:      "session",
+				StickinessAppCookieDurationSeconds: StickinessAppCookieDurationSeconds,
+				LoadBalancingAlgorithmType:         LoadBalancingAlgorithmType,
+			},
+		},
+		{
+			name: "StickinessTypeKey is app_cookie without cookie name",
+			ok:   false,
+			attributes: []*elbv2.TargetGroupAttribute{
+				tgAttribute(StickinessEnabledKey, "true"),
+				tgAttribute(StickinessTypeKey, "app_cookie"),
+			},
+		},
+
+		{
+			name:       "StickinessAppCookieCookieNameKey is reserved",
+			ok:         false,
+			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieCookieNameKey, "AWSALBAPP-0")},
+		},
+
+		{
+			name:       "StickinessAppCookieDurationSecondsKey is default",
+			ok:         true,
+			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "86400")},
+			output:     MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "86400")}),
+		},
+		{
+			name:       "StickinessAppCookieDurationSecondsKey is > 604800",
+			ok:         false,
+			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "604801")},
+		},
+		{
+			name:       "StickinessAppCookieDurationSecondsKey is < 1",
+			ok:         false,
+			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "0")},
+		},
+		{
+			name:       "StickinessAppCookieDurationSecondsKey is not a number",
+			ok:         false,
+			attributes: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "error")},
+		},
 
 		{
 			name:       "StickinessLbCookieDurationSecondsKey is default",
@@ -291,6 +346,24 @@ func Test_attributesChangeSet(t *testing.T) {
 			b:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessLbCookieDurationSecondsKey, "500")}),
 			changeSet: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessLbCookieDurationSecondsKey, "500")},
 		},
+		{
+			name:      "StickinessAppCookieCookieName: a=default b=nondefault",
+			a:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieCookieNameKey, "")}),
+			b:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieCookieNameKey, "session")}),
+			changeSet: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieCookieNameKey, "session")},
+		},
+		{
+			name:      "StickinessAppCookieCookieName: a=nondefault b=default",
+			a:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessTypeKey, "app_cookie"), tgAttribute(StickinessAppCookieCookieNameKey, "session")}),
+			b:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessTypeKey, "lb_cookie")}),
+			changeSet: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessTypeKey, "lb_cookie")},
+		},
+		{
+			name:      "StickinessAppCookieDurationSeconds: a=default b=nondefault",
+			a:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "86400")}),
+			b:         MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "500")}),
+			changeSet: []*elbv2.TargetGroupAttribute{tgAttribute(StickinessAppCookieDurationSecondsKey, "500")},
+		},
 		{
 			name: "StickinessLbCookieDurationSeconds: a=nondefault b=nondefault a=b",
 			a:    MustNewAttributes([]*elbv2.TargetGroupAttribute{tgAttribute(StickinessLbCookieDurationSecondsKey, "500")}),
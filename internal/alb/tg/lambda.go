@@ -0,0 +1,222 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// TagKeyLambdaFunction is the tag of targetGroups of target-type lambda with the ARN of their lambda function
+const TagKeyLambdaFunction = "ingress.k8s.aws/lambda-function"
+
+// LambdaController manages the targetGroups of lambda functions forwarded to by an ingress.
+type LambdaController interface {
+	// Reconcile ensures an targetGroup of target-type lambda exists for the lambda function, with the function registered
+	// and permitted to be invoked by the targetGroup.
+	Reconcile(ctx context.Context, ingress *extensions.Ingress, functionArn string) (TargetGroup, error)
+
+	// RevokePermission revokes the permission to invoke its lambda function from an targetGroup about to be deleted,
+	// when it's of target-type lambda.
+	RevokePermission(ctx context.Context, tgArn string) error
+}
+
+func NewLambdaController(cloud aws.CloudAPI, store store.Storer, nameTagGen NameTagGenerator, tagsController tags.Controller) LambdaController {
+	return &defaultLambdaController{
+		cloud:          cloud,
+		store:          store,
+		nameTagGen:     nameTagGen,
+		tagsController: tagsController,
+	}
+}
+
+var _ LambdaController = (*defaultLambdaController)(nil)
+
+type defaultLambdaController struct {
+	cloud      aws.CloudAPI
+	store      store.Storer
+	nameTagGen NameTagGenerator
+
+	tagsController tags.Controller
+}
+
+func (controller *defaultLambdaController) Reconcile(ctx context.Context, ingress *extensions.Ingress, functionArn string) (TargetGroup, error) {
+	if err := controller.validateFunctionArn(functionArn); err != nil {
+		return TargetGroup{}, err
+	}
+	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	if err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to load ingressAnnotation due to %v", err)
+	}
+
+	// the function ARN stands in for the service, lambda targetGroups have neither port nor protocol.
+	tgName := controller.nameTagGen.NameTG(ingress.Namespace, ingress.Name, functionArn, "", elbv2.TargetTypeEnumLambda, "", "")
+	tgInstance, err := controller.cloud.GetTargetGroupByName(ctx, tgName)
+	if err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to find existing targetGroup due to %v", err)
+	}
+	if tgInstance == nil {
+		albctx.GetLogger(ctx).Infof("creating target group %v for lambda function %v", tgName, functionArn)
+		resp, err := controller.cloud.CreateTargetGroupWithContext(ctx, &elbv2.CreateTargetGroupInput{
+			Name:       aws.String(tgName),
+			TargetType: aws.String(elbv2.TargetTypeEnumLambda),
+		})
+		if err != nil {
+			return TargetGroup{}, fmt.Errorf("failed to create targetGroup due to %v", err)
+		}
+		tgInstance = resp.TargetGroups[0]
+		albctx.GetLogger(ctx).Infof("target group %v created: %v", tgName, aws.StringValue(tgInstance.TargetGroupArn))
+	}
+
+	tgArn := aws.StringValue(tgInstance.TargetGroupArn)
+	tgTags := make(map[string]string)
+	for k, v := range controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name) {
+		tgTags[k] = v
+	}
+	for k, v := range ingressAnnos.Tags.LoadBalancer {
+		tgTags[k] = v
+	}
+	tgTags[TagKeyLambdaFunction] = functionArn
+	if err := controller.tagsController.ReconcileELB(ctx, tgArn, tgTags); err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to reconcile targetGroup tags due to %v", err)
+	}
+
+	// the targetGroup must be permitted to invoke the function before it's registered.
+	if err := controller.cloud.AddLambdaInvokePermission(ctx, functionArn, tgName, tgArn); err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to add permission to invoke lambda function %v due to %v", functionArn, err)
+	}
+	targets := []*elbv2.TargetDescription{{Id: aws.String(functionArn)}}
+	if err := controller.reconcileTargets(ctx, tgArn, targets); err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to reconcile targetGroup targets due to %v", err)
+	}
+
+	return TargetGroup{
+		Arn:        tgArn,
+		TargetType: elbv2.TargetTypeEnumLambda,
+		Targets:    targets,
+	}, nil
+}
+
+// reconcileTargets registers the lambda function, and deregisters functions registered before.
+func (controller *defaultLambdaController) reconcileTargets(ctx context.Context, tgArn string, targets []*elbv2.TargetDescription) error {
+	current, err := controller.registeredFunctionArns(ctx, tgArn)
+	if err != nil {
+		return err
+	}
+	functionArn := aws.StringValue(targets[0].Id)
+	var deregistrations []*elbv2.TargetDescription
+	registered := false
+	for _, arn := range current {
+		if arn == functionArn {
+			registered = true
+			continue
+		}
+		deregistrations = append(deregistrations, &elbv2.TargetDescription{Id: aws.String(arn)})
+	}
+	if !registered {
+		albctx.GetLogger(ctx).Infof("Adding targets to %v: %v", tgArn, functionArn)
+		if _, err := controller.cloud.RegisterTargetsWithContext(ctx, &elbv2.RegisterTargetsInput{
+			TargetGroupArn: aws.String(tgArn),
+			Targets:        targets,
+		}); err != nil {
+			return err
+		}
+	}
+	if len(deregistrations) > 0 {
+		albctx.GetLogger(ctx).Infof("Removing targets from %v: %v", tgArn, tdsString(deregistrations))
+		if _, err := controller.cloud.DeregisterTargetsWithContext(ctx, &elbv2.DeregisterTargetsInput{
+			TargetGroupArn: aws.String(tgArn),
+			Targets:        deregistrations,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (controller *defaultLambdaController) RevokePermission(ctx context.Context, tgArn string) error {
+	tgInstance, err := controller.cloud.GetTargetGroupByArn(ctx, tgArn)
+	if err != nil {
+		return err
+	}
+	if tgInstance == nil || aws.StringValue(tgInstance.TargetType) != elbv2.TargetTypeEnumLambda {
+		return nil
+	}
+	functionArns, err := controller.registeredFunctionArns(ctx, tgArn)
+	if err != nil {
+		return err
+	}
+	for _, functionArn := range functionArns {
+		// the controller isn't permitted to change the permissions of functions outside the name prefix
+		if err := controller.validateFunctionArn(functionArn); err != nil {
+			albctx.GetLogger(ctx).Infof("not removing permission of target group %v to invoke lambda function %v: %v", tgArn, functionArn, err)
+			continue
+		}
+		albctx.GetLogger(ctx).Infof("removing permission of target group %v to invoke lambda function %v", tgArn, functionArn)
+		if err := controller.cloud.RemoveLambdaInvokePermission(ctx, functionArn, aws.StringValue(tgInstance.TargetGroupName)); err != nil {
+			return fmt.Errorf("failed to remove permission to invoke lambda function %v due to %v", functionArn, err)
+		}
+	}
+	return nil
+}
+
+func (controller *defaultLambdaController) registeredFunctionArns(ctx context.Context, tgArn string) ([]string, error) {
+	resp, err := controller.cloud.DescribeTargetHealthWithContext(ctx, &elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(tgArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var functionArns []string
+	for _, desc := range resp.TargetHealthDescriptions {
+		functionArns = append(functionArns, aws.StringValue(desc.Target.Id))
+	}
+	return functionArns, nil
+}
+
+// validateFunctionArn checks the name of the lambda function against the allowed name prefix,
+// so that ingresses can't make the controller grant invoking arbitrary functions the IAM role has access to.
+func (controller *defaultLambdaController) validateFunctionArn(functionArn string) error {
+	parsedArn, err := arn.Parse(functionArn)
+	if err != nil || parsedArn.Service != lambda.ServiceName || !strings.HasPrefix(parsedArn.Resource, "function:") {
+		return fmt.Errorf("invalid lambda function ARN %v", functionArn)
+	}
+	// the resource is function:name, with an optional version or alias qualifier
+	name := strings.SplitN(strings.TrimPrefix(parsedArn.Resource, "function:"), ":", 2)[0]
+	namePrefix := controller.store.GetConfig().LambdaFunctionNamePrefix
+	if !strings.HasPrefix(name, namePrefix) {
+		return fmt.Errorf("invalid lambda function ARN %v: name %v must start with %v", functionArn, name, namePrefix)
+	}
+	return nil
+}
+
+// ExtractLambdaFunctionArns returns the lambda functions forwarded to by actions of the ingress.
+func ExtractLambdaFunctionArns(ingress *extensions.Ingress) ([]string, error) {
+	raw, err := action.NewParser().Parse(ingress)
+	if err != nil {
+		return nil, err
+	}
+
+	var functionArns []string
+	for _, action := range raw.(*action.Config).Actions {
+		if aws.StringValue(action.Type) != elbv2.ActionTypeEnumForward {
+			continue
+		}
+		for _, tgt := range action.ForwardConfig.TargetGroups {
+			if tgt.LambdaFunctionArn != nil {
+				functionArns = append(functionArns, aws.StringValue(tgt.LambdaFunctionArn))
+			}
+		}
+	}
+	return functionArns, nil
+}
@@ -0,0 +1,323 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	annoTags "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testFunctionNamePrefix = "my-"
+
+func functionTargetHealthDescriptions(functionArns ...string) *elbv2.DescribeTargetHealthOutput {
+	output := &elbv2.DescribeTargetHealthOutput{}
+	for _, functionArn := range functionArns {
+		output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			Target: &elbv2.TargetDescription{Id: aws.String(functionArn)},
+		})
+	}
+	return output
+}
+
+func TestDefaultLambdaController_Reconcile(t *testing.T) {
+	functionArn := "arn:aws:lambda:us-west-2:123456789012:function:my-function"
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+
+	for _, tc := range []struct {
+		name                 string
+		functionArn          string
+		existingTG           *elbv2.TargetGroup
+		createTGErr          error
+		registeredFunctions  []string
+		addPermissionErr     error
+		expectRegistration   bool
+		expectedDeregistered []string
+		expectedTG           TargetGroup
+		expectedErr          error
+	}{
+		{
+			name:               "targetGroup is created and the function registered",
+			expectRegistration: true,
+			expectedTG: TargetGroup{
+				Arn:        "tgArn",
+				TargetType: elbv2.TargetTypeEnumLambda,
+				Targets:    []*elbv2.TargetDescription{{Id: aws.String(functionArn)}},
+			},
+		},
+		{
+			name:                "existing targetGroup with the function registered",
+			existingTG:          &elbv2.TargetGroup{TargetGroupArn: aws.String("tgArn")},
+			registeredFunctions: []string{functionArn},
+			expectedTG: TargetGroup{
+				Arn:        "tgArn",
+				TargetType: elbv2.TargetTypeEnumLambda,
+				Targets:    []*elbv2.TargetDescription{{Id: aws.String(functionArn)}},
+			},
+		},
+		{
+			name:                 "existing targetGroup with another function registered",
+			existingTG:           &elbv2.TargetGroup{TargetGroupArn: aws.String("tgArn")},
+			registeredFunctions:  []string{"arn:aws:lambda:us-west-2:123456789012:function:other-function"},
+			expectRegistration:   true,
+			expectedDeregistered: []string{"arn:aws:lambda:us-west-2:123456789012:function:other-function"},
+			expectedTG: TargetGroup{
+				Arn:        "tgArn",
+				TargetType: elbv2.TargetTypeEnumLambda,
+				Targets:    []*elbv2.TargetDescription{{Id: aws.String(functionArn)}},
+			},
+		},
+		{
+			name:        "function outside the name prefix",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:other-function",
+			expectedErr: errors.New("invalid lambda function ARN arn:aws:lambda:us-west-2:123456789012:function:other-function: name other-function must start with my-"),
+		},
+		{
+			name:        "creating targetGroup fails",
+			createTGErr: errors.New("CreateTargetGroupWithContext"),
+			expectedErr: errors.New("failed to create targetGroup due to CreateTargetGroupWithContext"),
+		},
+		{
+			name:             "adding permission fails",
+			existingTG:       &elbv2.TargetGroup{TargetGroupArn: aws.String("tgArn")},
+			addPermissionErr: errors.New("AddLambdaInvokePermission"),
+			expectedErr:      errors.New("failed to add permission to invoke lambda function " + functionArn + " due to AddLambdaInvokePermission"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{LambdaFunctionNamePrefix: testFunctionNamePrefix})
+			cloud := &mocks.CloudAPI{}
+			mockNameTagGen := &MockNameTagGenerator{}
+			mockTagsController := &tags.MockController{}
+			if tc.functionArn != "" {
+				controller := NewLambdaController(cloud, mockStore, mockNameTagGen, mockTagsController)
+				tg, err := controller.Reconcile(ctx, ingress, tc.functionArn)
+				assert.Equal(t, tc.expectedTG, tg)
+				assert.Equal(t, tc.expectedErr, err)
+				cloud.AssertExpectations(t)
+				return
+			}
+			cloud.On("GetTargetGroupByName", ctx, "tgName").Return(tc.existingTG, nil)
+			if tc.existingTG == nil {
+				var output *elbv2.CreateTargetGroupOutput
+				if tc.createTGErr == nil {
+					output = &elbv2.CreateTargetGroupOutput{TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String("tgArn")}}}
+				}
+				cloud.On("CreateTargetGroupWithContext", ctx, &elbv2.CreateTargetGroupInput{
+					Name:       aws.String("tgName"),
+					TargetType: aws.String(elbv2.TargetTypeEnumLambda),
+				}).Return(output, tc.createTGErr)
+			}
+			if tc.createTGErr == nil {
+				mockTagsController.On("ReconcileELB", ctx, "tgArn", map[string]string{
+					"group-tag":          "group-tag-value",
+					"ingress-tag":        "ingress-tag-value",
+					TagKeyLambdaFunction: functionArn,
+				}).Return(nil)
+				cloud.On("AddLambdaInvokePermission", ctx, functionArn, "tgName", "tgArn").Return(tc.addPermissionErr)
+			}
+			if tc.createTGErr == nil && tc.addPermissionErr == nil {
+				cloud.On("DescribeTargetHealthWithContext", ctx, &elbv2.DescribeTargetHealthInput{
+					TargetGroupArn: aws.String("tgArn"),
+				}).Return(functionTargetHealthDescriptions(tc.registeredFunctions...), nil)
+			}
+			if tc.expectRegistration {
+				cloud.On("RegisterTargetsWithContext", ctx, &elbv2.RegisterTargetsInput{
+					TargetGroupArn: aws.String("tgArn"),
+					Targets:        []*elbv2.TargetDescription{{Id: aws.String(functionArn)}},
+				}).Return(nil, nil)
+			}
+			if len(tc.expectedDeregistered) != 0 {
+				var deregistrations []*elbv2.TargetDescription
+				for _, arn := range tc.expectedDeregistered {
+					deregistrations = append(deregistrations, &elbv2.TargetDescription{Id: aws.String(arn)})
+				}
+				cloud.On("DeregisterTargetsWithContext", ctx, &elbv2.DeregisterTargetsInput{
+					TargetGroupArn: aws.String("tgArn"),
+					Targets:        deregistrations,
+				}).Return(nil, nil)
+			}
+
+			mockNameTagGen.On("NameTG", "namespace", "ingress", functionArn, "", elbv2.TargetTypeEnumLambda, "", "").Return("tgName")
+			if tc.createTGErr == nil {
+				mockNameTagGen.On("TagTGGroup", "namespace", "ingress").Return(map[string]string{"group-tag": "group-tag-value"})
+			}
+			mockStore.On("GetIngressAnnotations", "namespace/ingress").Return(&annotations.Ingress{
+				Tags: &annoTags.Config{LoadBalancer: map[string]string{"ingress-tag": "ingress-tag-value"}},
+			}, nil)
+
+			controller := NewLambdaController(cloud, mockStore, mockNameTagGen, mockTagsController)
+			tg, err := controller.Reconcile(ctx, ingress, functionArn)
+			assert.Equal(t, tc.expectedTG, tg)
+			assert.Equal(t, tc.expectedErr, err)
+			cloud.AssertExpectations(t)
+			mockNameTagGen.AssertExpectations(t)
+			mockTagsController.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultLambdaController_RevokePermission(t *testing.T) {
+	functionArn := "arn:aws:lambda:us-west-2:123456789012:function:my-function"
+
+	for _, tc := range []struct {
+		name                string
+		tgInstance          *elbv2.TargetGroup
+		registeredFunctions []string
+		removedFunctions    []string
+		removePermissionErr error
+		expectedErr         error
+	}{
+		{
+			name: "targetGroup doesn't exist",
+		},
+		{
+			name: "targetGroup isn't of target-type lambda",
+			tgInstance: &elbv2.TargetGroup{
+				TargetGroupArn:  aws.String("tgArn"),
+				TargetGroupName: aws.String("tgName"),
+				TargetType:      aws.String(elbv2.TargetTypeEnumIp),
+			},
+		},
+		{
+			name: "permission of registered function is removed",
+			tgInstance: &elbv2.TargetGroup{
+				TargetGroupArn:  aws.String("tgArn"),
+				TargetGroupName: aws.String("tgName"),
+				TargetType:      aws.String(elbv2.TargetTypeEnumLambda),
+			},
+			registeredFunctions: []string{functionArn},
+			removedFunctions:    []string{functionArn},
+		},
+		{
+			name: "permission of registered function outside the name prefix is kept",
+			tgInstance: &elbv2.TargetGroup{
+				TargetGroupArn:  aws.String("tgArn"),
+				TargetGroupName: aws.String("tgName"),
+				TargetType:      aws.String(elbv2.TargetTypeEnumLambda),
+			},
+			registeredFunctions: []string{"arn:aws:lambda:us-west-2:123456789012:function:other-function", functionArn},
+			removedFunctions:    []string{functionArn},
+		},
+		{
+			name: "removing permission fails",
+			tgInstance: &elbv2.TargetGroup{
+				TargetGroupArn:  aws.String("tgArn"),
+				TargetGroupName: aws.String("tgName"),
+				TargetType:      aws.String(elbv2.TargetTypeEnumLambda),
+			},
+			registeredFunctions: []string{functionArn},
+			removedFunctions:    []string{functionArn},
+			removePermissionErr: errors.New("RemoveLambdaInvokePermission"),
+			expectedErr:         errors.New("failed to remove permission to invoke lambda function " + functionArn + " due to RemoveLambdaInvokePermission"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetTargetGroupByArn", ctx, "tgArn").Return(tc.tgInstance, nil)
+			if tc.registeredFunctions != nil {
+				cloud.On("DescribeTargetHealthWithContext", ctx, &elbv2.DescribeTargetHealthInput{
+					TargetGroupArn: aws.String("tgArn"),
+				}).Return(functionTargetHealthDescriptions(tc.registeredFunctions...), nil)
+			}
+			for _, arn := range tc.removedFunctions {
+				cloud.On("RemoveLambdaInvokePermission", ctx, arn, "tgName").Return(tc.removePermissionErr)
+			}
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{LambdaFunctionNamePrefix: testFunctionNamePrefix})
+
+			controller := NewLambdaController(cloud, mockStore, &MockNameTagGenerator{}, &tags.MockController{})
+			err := controller.RevokePermission(ctx, "tgArn")
+			assert.Equal(t, tc.expectedErr, err)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultLambdaController_validateFunctionArn(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		namePrefix  string
+		functionArn string
+		expectedErr string
+	}{
+		{
+			name:        "function with the name prefix",
+			namePrefix:  "alb-ingress-",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:alb-ingress-function",
+		},
+		{
+			name:        "qualified function with the name prefix",
+			namePrefix:  "alb-ingress-",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:alb-ingress-function:live",
+		},
+		{
+			name:        "any function without name prefix",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:function",
+		},
+		{
+			name:        "function without the name prefix",
+			namePrefix:  "alb-ingress-",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:function",
+			expectedErr: "invalid lambda function ARN arn:aws:lambda:us-west-2:123456789012:function:function: name function must start with alb-ingress-",
+		},
+		{
+			name:        "qualifier doesn't count towards the name",
+			namePrefix:  "alb-ingress-",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:function:function:alb-ingress-live",
+			expectedErr: "invalid lambda function ARN arn:aws:lambda:us-west-2:123456789012:function:function:alb-ingress-live: name function must start with alb-ingress-",
+		},
+		{
+			name:        "not a lambda function",
+			functionArn: "arn:aws:lambda:us-west-2:123456789012:layer:alb-ingress-layer",
+			expectedErr: "invalid lambda function ARN arn:aws:lambda:us-west-2:123456789012:layer:alb-ingress-layer",
+		},
+		{
+			name:        "not an ARN",
+			functionArn: "alb-ingress-function",
+			expectedErr: "invalid lambda function ARN alb-ingress-function",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{LambdaFunctionNamePrefix: tc.namePrefix})
+			controller := &defaultLambdaController{store: mockStore}
+
+			err := controller.validateFunctionArn(tc.functionArn)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExtractLambdaFunctionArns(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "namespace",
+			Name:      "ingress",
+			Annotations: map[string]string{
+				"alb.ingress.kubernetes.io/actions.lambda":   `{"Type":"forward","ForwardConfig":{"TargetGroups":[{"Weight":1,"ServiceName":"service1","ServicePort":"80"},{"Weight":1,"LambdaFunctionArn":"arn:aws:lambda:us-west-2:123456789012:function:my-function"}]}}`,
+				"alb.ingress.kubernetes.io/actions.redirect": `{"Type":"redirect","RedirectConfig":{"Protocol":"HTTPS","Port":"443","StatusCode":"HTTP_301"}}`,
+			},
+		},
+	}
+	functionArns, err := ExtractLambdaFunctionArns(ingress)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:lambda:us-west-2:123456789012:function:my-function"}, functionArns)
+}
@@ -0,0 +1,50 @@
+// Code generated by mockery v1.0.0. DO NOT EDIT.
+
+package tg
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+)
+
+// MockLambdaController is an autogenerated mock type for the LambdaController type
+type MockLambdaController struct {
+	mock.Mock
+}
+
+// Reconcile provides a mock function with given fields: ctx, ingress, functionArn
+func (_m *MockLambdaController) Reconcile(ctx context.Context, ingress *v1beta1.Ingress, functionArn string) (TargetGroup, error) {
+	ret := _m.Called(ctx, ingress, functionArn)
+
+	var r0 TargetGroup
+	if rf, ok := ret.Get(0).(func(context.Context, *v1beta1.Ingress, string) TargetGroup); ok {
+		r0 = rf(ctx, ingress, functionArn)
+	} else {
+		r0 = ret.Get(0).(TargetGroup)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1beta1.Ingress, string) error); ok {
+		r1 = rf(ctx, ingress, functionArn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokePermission provides a mock function with given fields: ctx, tgArn
+func (_m *MockLambdaController) RevokePermission(ctx context.Context, tgArn string) error {
+	ret := _m.Called(ctx, tgArn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, tgArn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
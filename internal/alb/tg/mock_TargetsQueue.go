@@ -0,0 +1,63 @@
+// Code generated by mockery v1.0.0. DO NOT EDIT.
+
+package tg
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// MockTargetsQueue is an autogenerated mock type for the TargetsQueue type
+type MockTargetsQueue struct {
+	mock.Mock
+}
+
+// EnqueueTargetGroups provides a mock function with given fields: ingressKey, serviceName
+func (_m *MockTargetsQueue) EnqueueTargetGroups(ingressKey types.NamespacedName, serviceName string) bool {
+	ret := _m.Called(ingressKey, serviceName)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(types.NamespacedName, string) bool); ok {
+		r0 = rf(ingressKey, serviceName)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Reconcile provides a mock function with given fields: _a0, _a1
+func (_m *MockTargetsQueue) Reconcile(_a0 context.Context, _a1 *Targets) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Targets) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: _a0
+func (_m *MockTargetsQueue) Start(_a0 <-chan struct{}) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(<-chan struct{}) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StopReconcilingPodConditionStatus provides a mock function with given fields: _a0
+func (_m *MockTargetsQueue) StopReconcilingPodConditionStatus(_a0 string) {
+	_m.Called(_a0)
+}
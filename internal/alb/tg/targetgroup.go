@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
@@ -11,15 +12,19 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	util "github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/types"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // The port used when creating targetGroup serves as a default value for targets registered without port specified.
@@ -29,17 +34,19 @@ import (
 // see https://docs.aws.amazon.com/sdk-for-go/api/service/elbv2/#CreateTargetGroupInput
 const targetGroupDefaultPort = 1
 
+// kubeProxyHealthCheckPath is the path kube-proxy serves the health of a node for a service on its healthCheckNodePort.
+const kubeProxyHealthCheckPath = "/healthz"
+
 // Controller manages a single targetGroup for specific ingress & ingressBackend.
 type Controller interface {
 	// Reconcile ensures an targetGroup exists for specified backend of ingress.
+	// The targetGroup may come with a RequeueAfter error, when it must be reconciled again later.
 	Reconcile(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend) (TargetGroup, error)
 	StopReconcilingPodConditionStatus(tgArn string)
 }
 
-func NewController(cloud aws.CloudAPI, store store.Storer, nameTagGen NameTagGenerator, tagsController tags.Controller, endpointResolver backend.EndpointResolver, client client.Client) Controller {
+func NewController(cloud aws.CloudAPI, store store.Storer, nameTagGen NameTagGenerator, tagsController tags.Controller, targetsController TargetsController, endpointResolver backend.EndpointResolver, targetHealthCache TargetHealthCache) Controller {
 	attrsController := NewAttributesController(cloud)
-	targetHealthController := NewTargetHealthController(cloud, store, endpointResolver, client)
-	targetsController := NewTargetsController(cloud, endpointResolver, targetHealthController)
 	return &defaultController{
 		cloud:             cloud,
 		store:             store,
@@ -47,6 +54,8 @@ func NewController(cloud aws.CloudAPI, store store.Storer, nameTagGen NameTagGen
 		tagsController:    tagsController,
 		attrsController:   attrsController,
 		targetsController: targetsController,
+		endpointResolver:  endpointResolver,
+		targetHealthCache: targetHealthCache,
 	}
 }
 
@@ -60,6 +69,13 @@ type defaultController struct {
 	tagsController    tags.Controller
 	attrsController   AttributesController
 	targetsController TargetsController
+	endpointResolver  backend.EndpointResolver
+	targetHealthCache TargetHealthCache
+
+	// probeDisagreements are the ingress backends whose pods currently disagree on their readinessProbe
+	probeDisagreements sync.Map
+	// replacements are the targetGroupReplacements of targetGroups replacing others, by ARN
+	replacements sync.Map
 }
 
 func (controller *defaultController) Reconcile(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend) (TargetGroup, error) {
@@ -74,19 +90,40 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 	}
 
 	protocol := aws.StringValue(serviceAnnos.TargetGroup.BackendProtocol)
+	protocolVersion := aws.StringValue(serviceAnnos.TargetGroup.ProtocolVersion)
 	targetType := aws.StringValue(serviceAnnos.TargetGroup.TargetType)
 
+	tgName := controller.nameTagGen.NameTG(ingress.Namespace, ingress.Name, backend.ServiceName, backend.ServicePort.String(), targetType, protocol, protocolVersion)
+	tgInstance, err := controller.findExistingTGInstance(ctx, tgName)
+	if err != nil {
+		return TargetGroup{}, fmt.Errorf("failed to find existing targetGroup due to %v", err)
+	}
+
+	if controller.store.GetConfig().FeatureGate.Enabled(config.ReadinessProbeHealthCheck) {
+		healthCheck, err := controller.inferHealthCheck(ctx, ingress, &backend, targetType, serviceAnnos.HealthCheck, tgInstance)
+		if err != nil {
+			return TargetGroup{}, fmt.Errorf("failed to infer healthcheck from readinessProbe due to %v", err)
+		}
+		inferredServiceAnnos := *serviceAnnos
+		inferredServiceAnnos.HealthCheck = healthCheck
+		serviceAnnos = &inferredServiceAnnos
+	}
+
 	healthCheckPort, err := controller.resolveServiceHealthCheckPort(ingress.Namespace, backend.ServiceName, intstr.Parse(*serviceAnnos.HealthCheck.Port), targetType)
 
 	if err != nil {
 		return TargetGroup{}, fmt.Errorf("failed to resolve healthcheck port due to %v", err)
 	}
-
-	tgName := controller.nameTagGen.NameTG(ingress.Namespace, ingress.Name, backend.ServiceName, backend.ServicePort.String(), targetType, protocol)
-	tgInstance, err := controller.findExistingTGInstance(ctx, tgName)
+	healthCheck, err := controller.resolveKubeProxyHealthCheck(ingress, backend.ServiceName, serviceAnnos.HealthCheck, targetType)
 	if err != nil {
-		return TargetGroup{}, fmt.Errorf("failed to find existing targetGroup due to %v", err)
+		return TargetGroup{}, fmt.Errorf("failed to resolve healthcheck due to %v", err)
 	}
+	if healthCheck != serviceAnnos.HealthCheck {
+		resolvedServiceAnnos := *serviceAnnos
+		resolvedServiceAnnos.HealthCheck = healthCheck
+		serviceAnnos = &resolvedServiceAnnos
+	}
+
 	if tgInstance == nil {
 		if tgInstance, err = controller.newTGInstance(ctx, tgName, serviceAnnos, healthCheckPort); err != nil {
 			return TargetGroup{}, fmt.Errorf("failed to create targetGroup due to %v", err)
@@ -110,15 +147,28 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 	if err = controller.targetsController.Reconcile(ctx, tgTargets); err != nil {
 		return TargetGroup{}, fmt.Errorf("failed to reconcile targetGroup targets due to %v", err)
 	}
+	var replacedTGArns []string
+	var requeueErr error
+	if controller.store.GetConfig().FeatureGate.Enabled(config.TargetGroupReplacement) {
+		if replacedTGArns, err = controller.awaitReplacement(ctx, ingress, backend, tgArn); err != nil {
+			if _, ok := ingerrors.IsRequeueAfter(err); !ok {
+				return TargetGroup{}, fmt.Errorf("failed to replace targetGroup due to %v", err)
+			}
+			requeueErr = err
+		}
+	}
 
 	return TargetGroup{
-		Arn:        tgArn,
-		TargetType: targetType,
-		Targets:    tgTargets.Targets,
-	}, nil
+		Arn:             tgArn,
+		TargetType:      targetType,
+		Targets:         tgTargets.Targets,
+		ProtocolVersion: protocolVersion,
+		ReplacedArns:    replacedTGArns,
+	}, requeueErr
 }
 
 func (controller *defaultController) StopReconcilingPodConditionStatus(tgArn string) {
+	controller.replacements.Delete(tgArn)
 	controller.targetsController.StopReconcilingPodConditionStatus(tgArn)
 }
 
@@ -133,7 +183,8 @@ func (controller *defaultController) newTGInstance(ctx context.Context, name str
 		HealthCheckTimeoutSeconds:  serviceAnnos.HealthCheck.TimeoutSeconds,
 		TargetType:                 serviceAnnos.TargetGroup.TargetType,
 		Protocol:                   serviceAnnos.TargetGroup.BackendProtocol,
-		Matcher:                    &elbv2.Matcher{HttpCode: serviceAnnos.TargetGroup.SuccessCodes},
+		ProtocolVersion:            serviceAnnos.TargetGroup.ProtocolVersion,
+		Matcher:                    buildMatcher(serviceAnnos),
 		HealthyThresholdCount:      serviceAnnos.TargetGroup.HealthyThresholdCount,
 		UnhealthyThresholdCount:    serviceAnnos.TargetGroup.UnhealthyThresholdCount,
 		Port:                       aws.Int64(targetGroupDefaultPort),
@@ -147,7 +198,7 @@ func (controller *defaultController) newTGInstance(ctx context.Context, name str
 }
 
 func (controller *defaultController) reconcileTGInstance(ctx context.Context, instance *elbv2.TargetGroup, serviceAnnos *annotations.Service, healthCheckPort string) (*elbv2.TargetGroup, error) {
-	if controller.TGInstanceNeedsModification(ctx, instance, serviceAnnos) {
+	if controller.TGInstanceNeedsModification(ctx, instance, serviceAnnos, healthCheckPort) {
 		albctx.GetLogger(ctx).Infof("modify target group %v", aws.StringValue(instance.TargetGroupArn))
 
 		output, err := controller.cloud.ModifyTargetGroupWithContext(ctx, &elbv2.ModifyTargetGroupInput{
@@ -157,7 +208,7 @@ func (controller *defaultController) reconcileTGInstance(ctx context.Context, in
 			HealthCheckPort:            aws.String(healthCheckPort),
 			HealthCheckProtocol:        serviceAnnos.HealthCheck.Protocol,
 			HealthCheckTimeoutSeconds:  serviceAnnos.HealthCheck.TimeoutSeconds,
-			Matcher:                    &elbv2.Matcher{HttpCode: serviceAnnos.TargetGroup.SuccessCodes},
+			Matcher:                    buildMatcher(serviceAnnos),
 			HealthyThresholdCount:      serviceAnnos.TargetGroup.HealthyThresholdCount,
 			UnhealthyThresholdCount:    serviceAnnos.TargetGroup.UnhealthyThresholdCount,
 		})
@@ -180,12 +231,24 @@ func (controller *defaultController) resolveServiceHealthCheckPort(namespace str
 
 	servicePort := servicePortAnnotation.String()
 
+	serviceKey := namespace + "/" + serviceName
+
 	//If the annotation uses the default port ("traffic-port"), do not try to look up a port by that name.
 	if servicePort == healthcheck.DefaultPort {
+		if targetType != elbv2.TargetTypeEnumInstance {
+			return servicePort, nil
+		}
+		service, err := controller.store.GetService(serviceKey)
+		if err != nil {
+			return servicePort, errors.Wrap(err, "failed to resolve healthcheck service name")
+		}
+		// with externalTrafficPolicy Local, kube-proxy reports whether a node hosts ready endpoints on healthCheckNodePort.
+		if usesHealthCheckNodePort(service) {
+			return strconv.Itoa(int(service.Spec.HealthCheckNodePort)), nil
+		}
 		return servicePort, nil
 	}
 
-	serviceKey := namespace + "/" + serviceName
 	service, err := controller.store.GetService(serviceKey)
 
 	if err != nil {
@@ -206,12 +269,49 @@ func (controller *defaultController) resolveServiceHealthCheckPort(namespace str
 
 }
 
-func (controller *defaultController) TGInstanceNeedsModification(ctx context.Context, instance *elbv2.TargetGroup, serviceAnnos *annotations.Service) bool {
+// resolveKubeProxyHealthCheck points the health check of instance targets at the healthz endpoint of kube-proxy when the traffic-port
+// resolves to the healthCheckNodePort of the service. kube-proxy only serves HTTP, so the protocol and path are overridden unless annotated.
+func (controller *defaultController) resolveKubeProxyHealthCheck(ingress *extensions.Ingress, serviceName string, healthCheck *healthcheck.Config, targetType string) (*healthcheck.Config, error) {
+	if targetType != elbv2.TargetTypeEnumInstance || aws.StringValue(healthCheck.Port) != healthcheck.DefaultPort {
+		return healthCheck, nil
+	}
+	service, err := controller.store.GetService(ingress.Namespace + "/" + serviceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve healthcheck service name")
+	}
+	if !usesHealthCheckNodePort(service) {
+		return healthCheck, nil
+	}
+	resolved := *healthCheck
+	if !isAnnotated("healthcheck-protocol", ingress, service) {
+		resolved.Protocol = aws.String(elbv2.ProtocolEnumHttp)
+	}
+	if !isAnnotated("healthcheck-path", ingress, service) {
+		resolved.Path = aws.String(kubeProxyHealthCheckPath)
+	}
+	return &resolved, nil
+}
+
+// usesHealthCheckNodePort checks whether the traffic-port of instance targets resolves to the healthCheckNodePort of service.
+func usesHealthCheckNodePort(service *corev1.Service) bool {
+	return service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal && service.Spec.HealthCheckNodePort != 0
+}
+
+// isAnnotated checks whether the annotation is set on the service, or on the ingress as default for its services.
+func isAnnotated(name string, ingress *extensions.Ingress, service *corev1.Service) bool {
+	if _, err := parser.GetStringAnnotation(name, service); err == nil {
+		return true
+	}
+	_, err := parser.GetStringAnnotation(name, ingress)
+	return err == nil
+}
+
+func (controller *defaultController) TGInstanceNeedsModification(ctx context.Context, instance *elbv2.TargetGroup, serviceAnnos *annotations.Service, healthCheckPort string) bool {
 	needsChange := false
 	if !util.DeepEqual(instance.HealthCheckPath, serviceAnnos.HealthCheck.Path) {
 		needsChange = true
 	}
-	if !util.DeepEqual(instance.HealthCheckPort, serviceAnnos.HealthCheck.Port) {
+	if aws.StringValue(instance.HealthCheckPort) != healthCheckPort {
 		needsChange = true
 	}
 	if !util.DeepEqual(instance.HealthCheckProtocol, serviceAnnos.HealthCheck.Protocol) {
@@ -223,7 +323,8 @@ func (controller *defaultController) TGInstanceNeedsModification(ctx context.Con
 	if !util.DeepEqual(instance.HealthCheckTimeoutSeconds, serviceAnnos.HealthCheck.TimeoutSeconds) {
 		needsChange = true
 	}
-	if !util.DeepEqual(instance.Matcher.HttpCode, serviceAnnos.TargetGroup.SuccessCodes) {
+	matcher := buildMatcher(serviceAnnos)
+	if !util.DeepEqual(instance.Matcher.HttpCode, matcher.HttpCode) || !util.DeepEqual(instance.Matcher.GrpcCode, matcher.GrpcCode) {
 		needsChange = true
 	}
 	if !util.DeepEqual(instance.HealthyThresholdCount, serviceAnnos.TargetGroup.HealthyThresholdCount) {
@@ -235,6 +336,14 @@ func (controller *defaultController) TGInstanceNeedsModification(ctx context.Con
 	return needsChange
 }
 
+// buildMatcher builds the matcher of the success codes of health checks, which are gRPC status codes when the protocol version is GRPC.
+func buildMatcher(serviceAnnos *annotations.Service) *elbv2.Matcher {
+	if aws.StringValue(serviceAnnos.TargetGroup.ProtocolVersion) == targetgroup.ProtocolVersionGRPC {
+		return &elbv2.Matcher{GrpcCode: serviceAnnos.TargetGroup.SuccessCodes}
+	}
+	return &elbv2.Matcher{HttpCode: serviceAnnos.TargetGroup.SuccessCodes}
+}
+
 func (controller *defaultController) buildTags(ingress *extensions.Ingress, backend extensions.IngressBackend, ingressAnnos *annotations.Ingress) map[string]string {
 	tgTags := make(map[string]string)
 	for k, v := range controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name) {
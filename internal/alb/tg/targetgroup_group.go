@@ -3,6 +3,7 @@ package tg
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -11,18 +12,30 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TagKeyDeletionDeadline is the tag of replaced targetGroups with the time they are deleted after, in RFC3339
+	TagKeyDeletionDeadline = "ingress.k8s.aws/deletion-deadline"
+
+	// describeTagsBatchSize is the maximum number of resources DescribeTags accepts
+	describeTagsBatchSize = 20
 )
 
 // GroupController manages all target groups for one ingress.
 type GroupController interface {
 	// Reconcile ensures AWS an targetGroup exists for each backend in ingress.
+	// The targetGroups may come with a RequeueAfter error, when they must be reconciled again later.
 	Reconcile(ctx context.Context, ingress *extensions.Ingress) (TargetGroupGroup, error)
 
 	// GC will delete unused targetGroups matched by tag selector
@@ -38,14 +51,17 @@ func NewGroupController(
 	store store.Storer,
 	nameTagGen NameTagGenerator,
 	tagsController tags.Controller,
+	targetsController TargetsController,
 	endpointResolver backend.EndpointResolver,
-	client client.Client) GroupController {
-	tgController := NewController(cloud, store, nameTagGen, tagsController, endpointResolver, client)
+	targetHealthCache TargetHealthCache) GroupController {
+	tgController := NewController(cloud, store, nameTagGen, tagsController, targetsController, endpointResolver, targetHealthCache)
+	lambdaController := NewLambdaController(cloud, store, nameTagGen, tagsController)
 	return &defaultGroupController{
-		cloud:        cloud,
-		store:        store,
-		nameTagGen:   nameTagGen,
-		tgController: tgController,
+		cloud:            cloud,
+		store:            store,
+		nameTagGen:       nameTagGen,
+		tgController:     tgController,
+		lambdaController: lambdaController,
 	}
 }
 
@@ -56,7 +72,8 @@ type defaultGroupController struct {
 	store      store.Storer
 	nameTagGen NameTagGenerator
 
-	tgController Controller
+	tgController     Controller
+	lambdaController LambdaController
 }
 
 func (controller *defaultGroupController) Reconcile(ctx context.Context, ingress *extensions.Ingress) (TargetGroupGroup, error) {
@@ -66,23 +83,50 @@ func (controller *defaultGroupController) Reconcile(ctx context.Context, ingress
 	if err != nil {
 		return TargetGroupGroup{}, err
 	}
+	var requeueErr error
 	for _, backend := range serviceBackends {
 		if _, ok := tgByBackend[backend]; ok {
 			continue
 		}
-		if tgByBackend[backend], err = controller.tgController.Reconcile(ctx, ingress, backend); err != nil {
+		targetGroup, err := controller.tgController.Reconcile(ctx, ingress, backend)
+		if _, ok := ingerrors.IsRequeueAfter(err); err != nil && !ok {
+			return TargetGroupGroup{}, err
+		}
+		requeueErr = ingerrors.EarliestRequeueAfter(requeueErr, err)
+		tgByBackend[backend] = targetGroup
+	}
+
+	tgByLambdaFunction := make(map[string]TargetGroup)
+	functionArns, err := ExtractLambdaFunctionArns(ingress)
+	if err != nil {
+		return TargetGroupGroup{}, err
+	}
+	for _, functionArn := range functionArns {
+		if _, ok := tgByLambdaFunction[functionArn]; ok {
+			continue
+		}
+		if tgByLambdaFunction[functionArn], err = controller.lambdaController.Reconcile(ctx, ingress, functionArn); err != nil {
 			return TargetGroupGroup{}, err
 		}
 	}
 	selector := controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name)
 	return TargetGroupGroup{
-		TGByBackend:    tgByBackend,
-		externalTGARNs: externalTGARNs,
-		selector:       selector,
-	}, nil
+		TGByBackend:        tgByBackend,
+		TGByLambdaFunction: tgByLambdaFunction,
+		externalTGARNs:     externalTGARNs,
+		selector:           selector,
+	}, requeueErr
 }
 
 func (controller *defaultGroupController) GC(ctx context.Context, tgGroup TargetGroupGroup) error {
+	return controller.gc(ctx, tgGroup, controller.store.GetConfig().FeatureGate.Enabled(config.TargetGroupReplacement))
+}
+
+// gc deletes the unused targetGroups, after their deregistration delay when deferDeletion is set, so that requests in flight to
+// the targets of replaced targetGroups can complete.
+// The deletion deadline is tagged on the targetGroups, which are deleted by gc once it passed, and a RequeueAfter error is returned
+// until then. The tag is removed when targetGroups are used again, which cancels their deletion.
+func (controller *defaultGroupController) gc(ctx context.Context, tgGroup TargetGroupGroup, deferDeletion bool) error {
 	tagFilters := make(map[string][]string)
 	for k, v := range tgGroup.selector {
 		tagFilters[k] = []string{v}
@@ -92,6 +136,10 @@ func (controller *defaultGroupController) GC(ctx context.Context, tgGroup Target
 	usedServiceTGARNs := sets.NewString()
 	for _, tg := range tgGroup.TGByBackend {
 		usedServiceTGARNs.Insert(tg.Arn)
+		usedServiceTGARNs.Insert(tg.ReplacedArns...)
+	}
+	for _, tg := range tgGroup.TGByLambdaFunction {
+		usedServiceTGARNs.Insert(tg.Arn)
 	}
 	arns, err := controller.cloud.GetResourcesByFilters(tagFilters, aws.ResourceTypeEnumELBTargetGroup)
 	if err != nil {
@@ -99,32 +147,122 @@ func (controller *defaultGroupController) GC(ctx context.Context, tgGroup Target
 	}
 	currentServiceTGARNs := sets.NewString(arns...)
 	unusedServiceTGARNs := currentServiceTGARNs.Difference(usedServiceTGARNs)
+
+	var deletionDeadlines map[string]time.Time
+	if deferDeletion && unusedServiceTGARNs.Len() != 0 {
+		if deletionDeadlines, err = describeDeletionDeadlines(ctx, controller.cloud, unusedServiceTGARNs.List()); err != nil {
+			return fmt.Errorf("failed to describe targetGroup tags due to %v", err)
+		}
+	}
+	var nextDeletionDeadline time.Time
 	for arn := range unusedServiceTGARNs {
 		if usedExternalTGARNs.Has(arn) {
 			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "Warning", "targetGroup created for k8s service should be referenced by serviceName and servicePort instead of TargetGroupARN: %s", arn)
 			continue
 		}
 
+		if deferDeletion {
+			deletionDeadline, ok := deletionDeadlines[arn]
+			if !ok {
+				if deletionDeadline, err = controller.deferDeletion(ctx, arn); err != nil {
+					return fmt.Errorf("failed to defer targetGroup deletion due to %v", err)
+				}
+			}
+			if time.Now().Before(deletionDeadline) {
+				if nextDeletionDeadline.IsZero() || deletionDeadline.Before(nextDeletionDeadline) {
+					nextDeletionDeadline = deletionDeadline
+				}
+				continue
+			}
+		}
+
 		albctx.GetLogger(ctx).Infof("deleting target group %v", arn)
 		controller.tgController.StopReconcilingPodConditionStatus(arn)
+		if err := controller.lambdaController.RevokePermission(ctx, arn); err != nil {
+			return fmt.Errorf("failed to revoke lambda permission of targetGroup due to %v", err)
+		}
 		if err := controller.cloud.DeleteTargetGroupByArn(ctx, arn); err != nil {
 			return fmt.Errorf("failed to delete targetGroup due to %v", err)
 		}
 	}
+	if !nextDeletionDeadline.IsZero() {
+		return ingerrors.NewRequeueAfter(time.Until(nextDeletionDeadline), "targetGroups are awaiting their deletion until %v", nextDeletionDeadline.Format(time.RFC3339))
+	}
 	return nil
 }
 
+// deferDeletion tags the targetGroup with the deadline of its deletion, once its deregistration delay has passed.
+func (controller *defaultGroupController) deferDeletion(ctx context.Context, arn string) (time.Time, error) {
+	raw, err := controller.cloud.DescribeTargetGroupAttributesWithContext(ctx, &elbv2.DescribeTargetGroupAttributesInput{
+		TargetGroupArn: aws.String(arn),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	attributes, err := NewAttributes(raw.Attributes)
+	if err != nil && !IsInvalidAttribute(err) {
+		return time.Time{}, err
+	}
+	delay := time.Duration(attributes.DeregistrationDelayTimeoutSeconds) * time.Second
+	deletionDeadline := time.Now().Add(delay).Truncate(time.Second)
+
+	albctx.GetLogger(ctx).Infof("deleting target group %v after its deregistration delay of %v", arn, delay)
+	if _, err := controller.cloud.AddELBV2TagsWithContext(ctx, &elbv2.AddTagsInput{
+		ResourceArns: aws.StringSlice([]string{arn}),
+		Tags:         tags.ConvertToELBV2(map[string]string{TagKeyDeletionDeadline: deletionDeadline.Format(time.RFC3339)}),
+	}); err != nil {
+		return time.Time{}, err
+	}
+	controller.tgController.StopReconcilingPodConditionStatus(arn)
+	return deletionDeadline, nil
+}
+
+// describeDeletionDeadlines returns the deletion deadlines tagged on the targetGroups awaiting their deletion, by ARN.
+func describeDeletionDeadlines(ctx context.Context, cloud aws.CloudAPI, arns []string) (map[string]time.Time, error) {
+	deletionDeadlines := make(map[string]time.Time)
+	for start := 0; start < len(arns); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := cloud.DescribeELBV2TagsWithContext(ctx, &elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(arns[start:end]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, tagDescription := range resp.TagDescriptions {
+			for _, tag := range tagDescription.Tags {
+				if aws.StringValue(tag.Key) != TagKeyDeletionDeadline {
+					continue
+				}
+				// targetGroups with an invalid deadline are tagged again
+				if deletionDeadline, err := time.Parse(time.RFC3339, aws.StringValue(tag.Value)); err == nil {
+					deletionDeadlines[aws.StringValue(tagDescription.ResourceArn)] = deletionDeadline
+				}
+			}
+		}
+	}
+	return deletionDeadlines, nil
+}
+
 func (controller *defaultGroupController) Delete(ctx context.Context, ingressKey types.NamespacedName) error {
 	selector := controller.nameTagGen.TagTGGroup(ingressKey.Namespace, ingressKey.Name)
 	tgGroup := TargetGroupGroup{
 		selector: selector,
 	}
-	return controller.GC(ctx, tgGroup)
+	return controller.gc(ctx, tgGroup, false)
 }
 
 // ExtractTargetGroupBackends returns backends for Ingress.
 // Backends can be either k8s service based or targetGroupArns referencing targetGroups created out side of k8s.
 func ExtractTargetGroupBackends(ingress *extensions.Ingress) ([]extensions.IngressBackend, []string, error) {
+	raw, err := action.NewParser().Parse(ingress)
+	if err != nil {
+		return nil, nil, err
+	}
+	actionCfg := raw.(*action.Config)
+
 	var rawIngBackends []extensions.IngressBackend
 	if ingress.Spec.Backend != nil {
 		rawIngBackends = append(rawIngBackends, *ingress.Spec.Backend)
@@ -134,7 +272,11 @@ func ExtractTargetGroupBackends(ingress *extensions.Ingress) ([]extensions.Ingre
 			continue
 		}
 		for _, path := range rule.HTTP.Paths {
-			rawIngBackends = append(rawIngBackends, path.Backend)
+			pathName, err := parser.GetPathName(ingress, rule.Host, path.Path)
+			if err != nil {
+				return nil, nil, err
+			}
+			rawIngBackends = append(rawIngBackends, actionCfg.GetPathBackend(pathName, path))
 		}
 	}
 
@@ -146,13 +288,8 @@ func ExtractTargetGroupBackends(ingress *extensions.Ingress) ([]extensions.Ingre
 		serviceBackends = append(serviceBackends, ingBackend)
 	}
 
-	raw, err := action.NewParser().Parse(ingress)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	var externalTGARNs []string
-	actions := raw.(*action.Config).Actions
+	actions := actionCfg.Actions
 	for _, action := range actions {
 		if aws.StringValue(action.Type) != elbv2.ActionTypeEnumForward {
 			continue
@@ -171,5 +308,13 @@ func ExtractTargetGroupBackends(ingress *extensions.Ingress) ([]extensions.Ingre
 		}
 	}
 
+	rawCanaries, err := canary.NewParser().Parse(ingress)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, canary := range rawCanaries.(*canary.Config).Canaries {
+		serviceBackends = append(serviceBackends, canary.Backend())
+	}
+
 	return serviceBackends, externalTGARNs, nil
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -25,6 +27,12 @@ type TGReconcileCall struct {
 	Err         error
 }
 
+type LambdaReconcileCall struct {
+	FunctionArn string
+	TargetGroup TargetGroup
+	Err         error
+}
+
 type GetResourcesByFiltersCall struct {
 	TagFilters   map[string][]string
 	ResourceType string
@@ -39,12 +47,13 @@ type DeleteTargetGroupByArnCall struct {
 
 func TestDefaultGroupController_Reconcile(t *testing.T) {
 	for _, tc := range []struct {
-		Name             string
-		Ingress          extensions.Ingress
-		TGReconcileCalls []TGReconcileCall
-		TagTGGroupCall   *TagTGGroupCall
-		ExpectedTGGroup  TargetGroupGroup
-		ExpectedError    error
+		Name                 string
+		Ingress              extensions.Ingress
+		TGReconcileCalls     []TGReconcileCall
+		LambdaReconcileCalls []LambdaReconcileCall
+		TagTGGroupCall       *TagTGGroupCall
+		ExpectedTGGroup      TargetGroupGroup
+		ExpectedError        error
 	}{
 		{
 			Name: "Reconcile succeeds with duplicated targetGroup",
@@ -153,7 +162,8 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 						ServicePort: intstr.FromInt(443),
 					}: {Arn: "arn3"},
 				},
-				selector: map[string]string{"key1": "value1", "key2": "value2"},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
 			},
 		},
 		{
@@ -230,7 +240,8 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 						ServicePort: intstr.FromInt(443),
 					}: {Arn: "arn2"},
 				},
-				selector: map[string]string{"key1": "value1", "key2": "value2"},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
 			},
 		},
 		{
@@ -320,7 +331,8 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 						ServicePort: intstr.FromInt(443),
 					}: {Arn: "arn3"},
 				},
-				selector: map[string]string{"key1": "value1", "key2": "value2"},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
 			},
 		},
 		{
@@ -381,7 +393,8 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 						ServicePort: intstr.FromInt(80),
 					}: {Arn: "arn1"},
 				},
-				selector: map[string]string{"key1": "value1", "key2": "value2"},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
 			},
 		},
 		{
@@ -457,9 +470,155 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 						ServicePort: intstr.FromInt(80),
 					}: {Arn: "arn2"},
 				},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
+			},
+		},
+		{
+			Name: "Reconcile succeeds with lambda function backend using annotation",
+			Ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "namespace",
+					Annotations: map[string]string{
+						"alb.ingress.kubernetes.io/actions.lambda": `{"Type":"forward","ForwardConfig":{"TargetGroups":[{"Weight":1,"ServiceName":"service1","ServicePort":"80"},{"Weight":1,"LambdaFunctionArn":"arn:aws:lambda:us-west-2:123456789012:function:my-function"}]}}`,
+					},
+				},
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "lambda",
+												ServicePort: intstr.FromString("use-annotation"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			TGReconcileCalls: []TGReconcileCall{
+				{
+					Backend: extensions.IngressBackend{
+						ServiceName: "service1",
+						ServicePort: intstr.FromInt(80),
+					},
+					TargetGroup: TargetGroup{
+						Arn: "arn1",
+					},
+				},
+			},
+			LambdaReconcileCalls: []LambdaReconcileCall{
+				{
+					FunctionArn: "arn:aws:lambda:us-west-2:123456789012:function:my-function",
+					TargetGroup: TargetGroup{
+						Arn:        "arn2",
+						TargetType: elbv2.TargetTypeEnumLambda,
+					},
+				},
+			},
+			TagTGGroupCall: &TagTGGroupCall{
+				Namespace:   "namespace",
+				IngressName: "ingress",
+				Tags:        map[string]string{"key1": "value1", "key2": "value2"},
+			},
+			ExpectedTGGroup: TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]TargetGroup{
+					{
+						ServiceName: "service1",
+						ServicePort: intstr.FromInt(80),
+					}: {Arn: "arn1"},
+				},
+				TGByLambdaFunction: map[string]TargetGroup{
+					"arn:aws:lambda:us-west-2:123456789012:function:my-function": {Arn: "arn2", TargetType: elbv2.TargetTypeEnumLambda},
+				},
 				selector: map[string]string{"key1": "value1", "key2": "value2"},
 			},
 		},
+		{
+			Name: "Reconcile succeeds while targetGroup replaces another",
+			Ingress: extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "namespace",
+				},
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{
+						{
+							IngressRuleValue: extensions.IngressRuleValue{
+								HTTP: &extensions.HTTPIngressRuleValue{
+									Paths: []extensions.HTTPIngressPath{
+										{
+											Path: "/path1",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service1",
+												ServicePort: intstr.FromInt(80),
+											},
+										},
+										{
+											Path: "/path2",
+											Backend: extensions.IngressBackend{
+												ServiceName: "service2",
+												ServicePort: intstr.FromInt(80),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			TGReconcileCalls: []TGReconcileCall{
+				{
+					Backend: extensions.IngressBackend{
+						ServiceName: "service1",
+						ServicePort: intstr.FromInt(80),
+					},
+					TargetGroup: TargetGroup{
+						Arn:          "arn1",
+						ReplacedArns: []string{"arn0"},
+					},
+					Err: ingerrors.NewRequeueAfter(5*time.Second, "target group arn1 replacing [arn0] is not healthy yet"),
+				},
+				{
+					Backend: extensions.IngressBackend{
+						ServiceName: "service2",
+						ServicePort: intstr.FromInt(80),
+					},
+					TargetGroup: TargetGroup{
+						Arn: "arn2",
+					},
+				},
+			},
+			TagTGGroupCall: &TagTGGroupCall{
+				Namespace:   "namespace",
+				IngressName: "ingress",
+				Tags:        map[string]string{"key1": "value1", "key2": "value2"},
+			},
+			ExpectedTGGroup: TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]TargetGroup{
+					{
+						ServiceName: "service1",
+						ServicePort: intstr.FromInt(80),
+					}: {Arn: "arn1", ReplacedArns: []string{"arn0"}},
+					{
+						ServiceName: "service2",
+						ServicePort: intstr.FromInt(80),
+					}: {Arn: "arn2"},
+				},
+				TGByLambdaFunction: map[string]TargetGroup{},
+				selector:           map[string]string{"key1": "value1", "key2": "value2"},
+			},
+			ExpectedError: ingerrors.NewRequeueAfter(5*time.Second, "target group arn1 replacing [arn0] is not healthy yet"),
+		},
 		{
 			Name: "Reconcile failed when reconcile targetGroup",
 			Ingress: extensions.Ingress{
@@ -510,6 +669,10 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			for _, call := range tc.TGReconcileCalls {
 				mockTGController.On("Reconcile", mock.Anything, &tc.Ingress, call.Backend).Return(call.TargetGroup, call.Err)
 			}
+			mockLambdaController := &MockLambdaController{}
+			for _, call := range tc.LambdaReconcileCalls {
+				mockLambdaController.On("Reconcile", mock.Anything, &tc.Ingress, call.FunctionArn).Return(call.TargetGroup, call.Err)
+			}
 
 			mockStore := &store.MockStorer{}
 			mockStore.On("GetConfig").Return(
@@ -518,10 +681,11 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 				}, nil)
 
 			controller := &defaultGroupController{
-				cloud:        cloud,
-				nameTagGen:   mockNameTagGen,
-				store:        mockStore,
-				tgController: mockTGController,
+				cloud:            cloud,
+				nameTagGen:       mockNameTagGen,
+				store:            mockStore,
+				tgController:     mockTGController,
+				lambdaController: mockLambdaController,
 			}
 
 			tgGroup, err := controller.Reconcile(context.Background(), &tc.Ingress)
@@ -530,6 +694,7 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			cloud.AssertExpectations(t)
 			mockNameTagGen.AssertExpectations(t)
 			mockTGController.AssertExpectations(t)
+			mockLambdaController.AssertExpectations(t)
 		})
 	}
 }
@@ -567,6 +732,31 @@ func TestDefaultGroupController_GC(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "GC succeeds without deleting targetGroups of lambda functions",
+			TGGroup: TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]TargetGroup{
+					{
+						ServiceName: "service1",
+						ServicePort: intstr.FromInt(80),
+					}: {Arn: "arn1"},
+				},
+				TGByLambdaFunction: map[string]TargetGroup{
+					"arn:aws:lambda:us-west-2:123456789012:function:my-function": {Arn: "arn3"},
+				},
+				selector: map[string]string{"key1": "value1", "key2": "value2"},
+			},
+			GetResourcesByFiltersCall: &GetResourcesByFiltersCall{
+				TagFilters:   map[string][]string{"key1": {"value1"}, "key2": {"value2"}},
+				ResourceType: aws.ResourceTypeEnumELBTargetGroup,
+				Arns:         []string{"arn1", "arn2", "arn3"},
+			},
+			DeleteTargetGroupByArnCalls: []DeleteTargetGroupByArnCall{
+				{
+					Arn: "arn2",
+				},
+			},
+		},
 		{
 			Name: "GC succeeds without deleting externalTargetArn even it's created by controller",
 			TGGroup: TargetGroupGroup{
@@ -646,11 +836,20 @@ func TestDefaultGroupController_GC(t *testing.T) {
 		for _, call := range tc.DeleteTargetGroupByArnCalls {
 			mockTGController.On("StopReconcilingPodConditionStatus", call.Arn).Return()
 		}
+		mockLambdaController := &MockLambdaController{}
+		for _, call := range tc.DeleteTargetGroupByArnCalls {
+			mockLambdaController.On("RevokePermission", ctx, call.Arn).Return(nil)
+		}
+
+		mockStore := &store.MockStorer{}
+		mockStore.On("GetConfig").Return(&config.Configuration{FeatureGate: config.NewFeatureGate()})
 
 		controller := &defaultGroupController{
-			cloud:        cloud,
-			nameTagGen:   mockNameTagGen,
-			tgController: mockTGController,
+			cloud:            cloud,
+			nameTagGen:       mockNameTagGen,
+			store:            mockStore,
+			tgController:     mockTGController,
+			lambdaController: mockLambdaController,
 		}
 
 		err := controller.GC(context.Background(), tc.TGGroup)
@@ -658,7 +857,152 @@ func TestDefaultGroupController_GC(t *testing.T) {
 		cloud.AssertExpectations(t)
 		mockNameTagGen.AssertExpectations(t)
 		mockTGController.AssertExpectations(t)
+		mockLambdaController.AssertExpectations(t)
+	}
+}
+
+func deletionDeadlineTags(arn string, deletionDeadline *time.Time) *elbv2.DescribeTagsOutput {
+	tagDescription := &elbv2.TagDescription{ResourceArn: aws.String(arn)}
+	if deletionDeadline != nil {
+		tagDescription.Tags = []*elbv2.Tag{{Key: aws.String(TagKeyDeletionDeadline), Value: aws.String(deletionDeadline.Format(time.RFC3339))}}
 	}
+	return &elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{tagDescription}}
+}
+
+func TestDefaultGroupController_GC_TargetGroupReplacement(t *testing.T) {
+	tgGroup := TargetGroupGroup{
+		TGByBackend: map[extensions.IngressBackend]TargetGroup{
+			{
+				ServiceName: "service1",
+				ServicePort: intstr.FromInt(80),
+			}: {Arn: "arn1"},
+		},
+		selector: map[string]string{"key1": "value1"},
+	}
+	featureGate := config.NewFeatureGate()
+	featureGate.Enable(config.TargetGroupReplacement)
+	pastDeadline := time.Now().Add(-time.Minute)
+	futureDeadline := time.Now().Add(time.Minute)
+
+	for _, tc := range []struct {
+		name                        string
+		deletionDeadline            *time.Time
+		deregistrationDelay         string
+		deleteTargetGroupByArnErr   error
+		expectDeletionDeadlineTag   bool
+		expectDeletion              bool
+		expectedRequeueAfterAtLeast time.Duration
+		expectedErr                 string
+	}{
+		{
+			name:                        "replaced targetGroup is tagged with its deletion deadline",
+			deregistrationDelay:         "300",
+			expectDeletionDeadlineTag:   true,
+			expectedRequeueAfterAtLeast: 299 * time.Second,
+		},
+		{
+			name:                      "replaced targetGroup without deregistration delay is deleted right away",
+			deregistrationDelay:       "0",
+			expectDeletionDeadlineTag: true,
+			expectDeletion:            true,
+		},
+		{
+			name:                        "replaced targetGroup isn't deleted before its deletion deadline",
+			deletionDeadline:            &futureDeadline,
+			expectedRequeueAfterAtLeast: 58 * time.Second,
+		},
+		{
+			name:             "replaced targetGroup is deleted past its deletion deadline",
+			deletionDeadline: &pastDeadline,
+			expectDeletion:   true,
+		},
+		{
+			name:                      "replaced targetGroup that failed to be deleted keeps its deletion deadline",
+			deletionDeadline:          &pastDeadline,
+			deleteTargetGroupByArnErr: errors.New("DeleteTargetGroupByArn"),
+			expectDeletion:            true,
+			expectedErr:               "failed to delete targetGroup due to DeleteTargetGroupByArn",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetResourcesByFilters", map[string][]string{"key1": {"value1"}}, aws.ResourceTypeEnumELBTargetGroup).Return([]string{"arn1", "arn2"}, nil)
+			cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"arn2"})}).Return(deletionDeadlineTags("arn2", tc.deletionDeadline), nil)
+			mockTGController := &MockController{}
+			mockLambdaController := &MockLambdaController{}
+			if tc.expectDeletionDeadlineTag {
+				cloud.On("DescribeTargetGroupAttributesWithContext", ctx, &elbv2.DescribeTargetGroupAttributesInput{TargetGroupArn: aws.String("arn2")}).Return(&elbv2.DescribeTargetGroupAttributesOutput{
+					Attributes: []*elbv2.TargetGroupAttribute{tgAttribute(DeregistrationDelayTimeoutSecondsKey, tc.deregistrationDelay)},
+				}, nil)
+				cloud.On("AddELBV2TagsWithContext", ctx, mock.MatchedBy(func(input *elbv2.AddTagsInput) bool {
+					return aws.StringValue(input.ResourceArns[0]) == "arn2" && aws.StringValue(input.Tags[0].Key) == TagKeyDeletionDeadline
+				})).Return(&elbv2.AddTagsOutput{}, nil)
+				mockTGController.On("StopReconcilingPodConditionStatus", "arn2").Return()
+			}
+			if tc.expectDeletion {
+				mockTGController.On("StopReconcilingPodConditionStatus", "arn2").Return()
+				mockLambdaController.On("RevokePermission", ctx, "arn2").Return(nil)
+				cloud.On("DeleteTargetGroupByArn", ctx, "arn2").Return(tc.deleteTargetGroupByArnErr)
+			}
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{FeatureGate: featureGate})
+
+			controller := &defaultGroupController{
+				cloud:            cloud,
+				store:            mockStore,
+				tgController:     mockTGController,
+				lambdaController: mockLambdaController,
+			}
+
+			err := controller.GC(ctx, tgGroup)
+			requeueAfter, requeue := ingerrors.IsRequeueAfter(err)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else if tc.expectedRequeueAfterAtLeast != 0 {
+				assert.True(t, requeue)
+				assert.True(t, requeueAfter.Duration >= tc.expectedRequeueAfterAtLeast, "requeue after %v", requeueAfter.Duration)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tc.expectDeletion {
+				cloud.AssertNotCalled(t, "DeleteTargetGroupByArn", ctx, "arn2")
+			}
+			cloud.AssertExpectations(t)
+			mockTGController.AssertExpectations(t)
+			mockLambdaController.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultGroupController_GC_TargetGroupBeingReplaced(t *testing.T) {
+	ctx := context.Background()
+	tgGroup := TargetGroupGroup{
+		TGByBackend: map[extensions.IngressBackend]TargetGroup{
+			{
+				ServiceName: "service1",
+				ServicePort: intstr.FromInt(80),
+			}: {Arn: "arn2", ReplacedArns: []string{"arn1"}},
+		},
+		selector: map[string]string{"key1": "value1"},
+	}
+	featureGate := config.NewFeatureGate()
+	featureGate.Enable(config.TargetGroupReplacement)
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetResourcesByFilters", map[string][]string{"key1": {"value1"}}, aws.ResourceTypeEnumELBTargetGroup).Return([]string{"arn1", "arn2"}, nil)
+	mockStore := &store.MockStorer{}
+	mockStore.On("GetConfig").Return(&config.Configuration{FeatureGate: featureGate})
+
+	controller := &defaultGroupController{
+		cloud:            cloud,
+		store:            mockStore,
+		tgController:     &MockController{},
+		lambdaController: &MockLambdaController{},
+	}
+
+	// the replaced targetGroup keeps serving until the targetGroup replacing it is healthy
+	assert.NoError(t, controller.GC(ctx, tgGroup))
+	cloud.AssertExpectations(t)
 }
 
 func TestDefaultGroupController_Delete(t *testing.T) {
@@ -757,11 +1101,16 @@ func TestDefaultGroupController_Delete(t *testing.T) {
 		for _, call := range tc.DeleteTargetGroupByArnCalls {
 			mockTGController.On("StopReconcilingPodConditionStatus", call.Arn).Return()
 		}
+		mockLambdaController := &MockLambdaController{}
+		for _, call := range tc.DeleteTargetGroupByArnCalls {
+			mockLambdaController.On("RevokePermission", ctx, call.Arn).Return(nil)
+		}
 
 		controller := &defaultGroupController{
-			cloud:        cloud,
-			nameTagGen:   mockNameTagGen,
-			tgController: mockTGController,
+			cloud:            cloud,
+			nameTagGen:       mockNameTagGen,
+			tgController:     mockTGController,
+			lambdaController: mockLambdaController,
 		}
 
 		err := controller.Delete(context.Background(), tc.IngressKey)
@@ -769,5 +1118,43 @@ func TestDefaultGroupController_Delete(t *testing.T) {
 		cloud.AssertExpectations(t)
 		mockNameTagGen.AssertExpectations(t)
 		mockTGController.AssertExpectations(t)
+		mockLambdaController.AssertExpectations(t)
 	}
 }
+
+func TestExtractTargetGroupBackends(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"alb.ingress.kubernetes.io/path-names":          `{"maintenance": "d1.example.com/v2"}`,
+				"alb.ingress.kubernetes.io/actions.maintenance": `{"Type":"fixed-response","FixedResponseConfig":{"ContentType":"text/plain","StatusCode":"503"}}`,
+			},
+		},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{
+				{
+					Host: "d1.example.com",
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{
+									Path:    "/v2",
+									Backend: extensions.IngressBackend{ServiceName: "service-v2", ServicePort: intstr.FromInt(80)},
+								},
+								{
+									Path:    "/v1",
+									Backend: extensions.IngressBackend{ServiceName: "service-v1", ServicePort: intstr.FromInt(80)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	backends, externalTGARNs, err := ExtractTargetGroupBackends(ingress)
+	assert.NoError(t, err)
+	assert.Equal(t, []extensions.IngressBackend{{ServiceName: "service-v1", ServicePort: intstr.FromInt(80)}}, backends)
+	assert.Empty(t, externalTGARNs)
+}
@@ -0,0 +1,244 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// bounds of the health check settings supported by ALB target groups
+const (
+	minHealthCheckIntervalSeconds = 5
+	maxHealthCheckIntervalSeconds = 300
+	minHealthCheckTimeoutSeconds  = 2
+	maxHealthCheckTimeoutSeconds  = 120
+)
+
+// defaults of readinessProbe settings that are left empty
+const (
+	defaultProbePeriodSeconds  = 10
+	defaultProbeTimeoutSeconds = 1
+)
+
+// probeHealthCheck is the health check inferred from the HTTP readinessProbe of a pod.
+type probeHealthCheck struct {
+	path     string
+	port     string
+	protocol string
+	interval int64
+	timeout  int64
+}
+
+func (hc probeHealthCheck) String() string {
+	return fmt.Sprintf("%s %s:%s interval %ds timeout %ds", hc.protocol, hc.port, hc.path, hc.interval, hc.timeout)
+}
+
+// inferHealthCheck returns the health check of the ingress backend, with the settings that aren't annotated on the ingress or service inferred from the readinessProbe of the backend pods.
+// If the readinessProbes of the pods disagree, e.g. during a rolling update changing them, the current health check of tgInstance is kept while it matches
+// the readinessProbe of some pods, so that the pods still serving traffic keep passing it. Otherwise the health check is inferred from the most common one.
+func (controller *defaultController) inferHealthCheck(ctx context.Context, ingress *extensions.Ingress, backend *extensions.IngressBackend, targetType string, healthCheck *healthcheck.Config, tgInstance *elbv2.TargetGroup) (*healthcheck.Config, error) {
+	serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: backend.ServiceName}
+	service, err := controller.store.GetService(serviceKey.String())
+	if err != nil {
+		return nil, err
+	}
+	servicePort, err := k8s.LookupServicePort(service, backend.ServicePort)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := controller.endpointResolver.ResolvePods(ingress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	podCounts := make(map[probeHealthCheck]int)
+	for _, pod := range pods {
+		if hc, ok := probeHealthCheckFromPod(pod, servicePort, targetType); ok {
+			podCounts[hc]++
+		}
+	}
+	backendKey := fmt.Sprintf("%v/%v:%v", k8s.MetaNamespaceKey(ingress), backend.ServiceName, backend.ServicePort.String())
+	if len(podCounts) <= 1 {
+		if _, disagreed := controller.probeDisagreements.Load(backendKey); disagreed {
+			controller.probeDisagreements.Delete(backendKey)
+			albctx.GetLogger(ctx).Infof("readinessProbes of pods of service %v agree again", serviceKey)
+		}
+	}
+	if len(podCounts) == 0 {
+		return healthCheck, nil
+	}
+
+	isAnnotated := func(name string) bool {
+		for _, obj := range []parser.AnnotationInterface{ingress, service} {
+			if _, err := parser.GetStringAnnotation(name, obj); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	infer := func(inferred probeHealthCheck) *healthcheck.Config {
+		result := *healthCheck
+		if !isAnnotated("healthcheck-path") {
+			result.Path = aws.String(inferred.path)
+		}
+		if !isAnnotated("healthcheck-port") {
+			result.Port = aws.String(inferred.port)
+		}
+		if !isAnnotated("healthcheck-protocol") {
+			result.Protocol = aws.String(inferred.protocol)
+		}
+		if !isAnnotated("healthcheck-interval-seconds") {
+			result.IntervalSeconds = aws.Int64(inferred.interval)
+		}
+		if !isAnnotated("healthcheck-timeout-seconds") {
+			result.TimeoutSeconds = aws.Int64(inferred.timeout)
+		}
+		// the timeout must be less than the interval, which annotated settings are validated for
+		if aws.Int64Value(result.TimeoutSeconds) >= aws.Int64Value(result.IntervalSeconds) {
+			result.IntervalSeconds = healthCheck.IntervalSeconds
+			result.TimeoutSeconds = healthCheck.TimeoutSeconds
+		}
+		return &result
+	}
+
+	inferred := mostCommonProbeHealthCheck(podCounts)
+	if len(podCounts) == 1 {
+		return infer(inferred), nil
+	}
+	if tgInstance != nil {
+		for hc := range podCounts {
+			// the traffic-port and annotated ports can resolve to other ports, so only explicit inferred ports are compared
+			portMatches := hc.port == healthcheck.DefaultPort || isAnnotated("healthcheck-port") || hc.port == aws.StringValue(tgInstance.HealthCheckPort)
+			if portMatches && healthCheckMatchesTargetGroup(infer(hc), tgInstance) {
+				inferred = hc
+				break
+			}
+		}
+	}
+	// the disagreement is only reported once it starts, not on every reconcile until the pods agree again
+	if _, disagreed := controller.probeDisagreements.LoadOrStore(backendKey, true); !disagreed {
+		var disagreements []string
+		for hc, count := range podCounts {
+			disagreements = append(disagreements, fmt.Sprintf("%d pods probe %v", count, hc))
+		}
+		sort.Strings(disagreements)
+		albctx.GetLogger(ctx).Warnf("readinessProbes of pods of service %v disagree: %v", serviceKey, strings.Join(disagreements, ", "))
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "HEALTHCHECK", "readinessProbes of pods of service %s disagree, healthcheck is inferred from %v until they agree: %s", serviceKey, inferred, strings.Join(disagreements, ", "))
+	}
+	return infer(inferred), nil
+}
+
+// healthCheckMatchesTargetGroup checks whether the target group currently performs the health check, except for its port.
+func healthCheckMatchesTargetGroup(healthCheck *healthcheck.Config, tgInstance *elbv2.TargetGroup) bool {
+	return aws.StringValue(healthCheck.Path) == aws.StringValue(tgInstance.HealthCheckPath) &&
+		aws.StringValue(healthCheck.Protocol) == aws.StringValue(tgInstance.HealthCheckProtocol) &&
+		aws.Int64Value(healthCheck.IntervalSeconds) == aws.Int64Value(tgInstance.HealthCheckIntervalSeconds) &&
+		aws.Int64Value(healthCheck.TimeoutSeconds) == aws.Int64Value(tgInstance.HealthCheckTimeoutSeconds)
+}
+
+// probeHealthCheckFromPod infers the health check from the HTTP readinessProbe of the pod's container serving servicePort.
+// With target type == instance, the health check is sent to the nodes, so it's only inferred from readinessProbes on the traffic port.
+func probeHealthCheckFromPod(pod *corev1.Pod, servicePort *corev1.ServicePort, targetType string) (probeHealthCheck, bool) {
+	container, trafficPort, ok := findServingContainer(pod, servicePort)
+	if !ok || container.ReadinessProbe == nil || container.ReadinessProbe.HTTPGet == nil {
+		return probeHealthCheck{}, false
+	}
+	probe := container.ReadinessProbe
+	probePort, ok := lookupContainerPort(container, probe.HTTPGet.Port)
+	if !ok {
+		return probeHealthCheck{}, false
+	}
+
+	hc := probeHealthCheck{
+		path:     probe.HTTPGet.Path,
+		port:     healthcheck.DefaultPort,
+		protocol: string(probe.HTTPGet.Scheme),
+		interval: clampInt64(int64(probe.PeriodSeconds), defaultProbePeriodSeconds, minHealthCheckIntervalSeconds, maxHealthCheckIntervalSeconds),
+		timeout:  clampInt64(int64(probe.TimeoutSeconds), defaultProbeTimeoutSeconds, minHealthCheckTimeoutSeconds, maxHealthCheckTimeoutSeconds),
+	}
+	if hc.path == "" {
+		hc.path = healthcheck.DefaultPath
+	}
+	if hc.protocol == "" {
+		hc.protocol = string(corev1.URISchemeHTTP)
+	}
+	if probePort != trafficPort {
+		if targetType == elbv2.TargetTypeEnumInstance {
+			return probeHealthCheck{}, false
+		}
+		hc.port = strconv.Itoa(int(probePort))
+	}
+	return hc, true
+}
+
+// findServingContainer returns the container of the pod serving the target port of servicePort, and the number of that port.
+// Numeric target ports don't need to be declared by the container of single container pods.
+func findServingContainer(pod *corev1.Pod, servicePort *corev1.ServicePort) (*corev1.Container, int32, bool) {
+	targetPort := servicePort.TargetPort
+	if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+		targetPort = intstr.FromInt(int(servicePort.Port))
+	}
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		for _, containerPort := range container.Ports {
+			if (targetPort.Type == intstr.String && containerPort.Name == targetPort.StrVal) || (targetPort.Type == intstr.Int && containerPort.ContainerPort == targetPort.IntVal) {
+				return container, containerPort.ContainerPort, true
+			}
+		}
+	}
+	if targetPort.Type == intstr.Int && len(pod.Spec.Containers) == 1 {
+		return &pod.Spec.Containers[0], targetPort.IntVal, true
+	}
+	return nil, 0, false
+}
+
+// lookupContainerPort returns the number of the port of the container, looking up named ports.
+func lookupContainerPort(container *corev1.Container, port intstr.IntOrString) (int32, bool) {
+	if port.Type == intstr.Int {
+		return port.IntVal, true
+	}
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return containerPort.ContainerPort, true
+		}
+	}
+	return 0, false
+}
+
+// mostCommonProbeHealthCheck returns the health check inferred from the most pods, breaking ties by the lowest health check.
+func mostCommonProbeHealthCheck(podCounts map[probeHealthCheck]int) probeHealthCheck {
+	var result probeHealthCheck
+	resultCount := 0
+	for hc, count := range podCounts {
+		if count > resultCount || (count == resultCount && hc.String() < result.String()) {
+			result, resultCount = hc, count
+		}
+	}
+	return result
+}
+
+func clampInt64(value int64, defaultValue int64, min int64, max int64) int64 {
+	if value == 0 {
+		value = defaultValue
+	}
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,298 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newProbedPod(probe *corev1.Probe) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "sidecar",
+				},
+				{
+					Name:           "app",
+					Ports:          []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "admin", ContainerPort: 8081}},
+					ReadinessProbe: probe,
+				},
+			},
+		},
+	}
+}
+
+func newHTTPGetProbe(path string, port intstr.IntOrString, periodSeconds int32, timeoutSeconds int32) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{Path: path, Port: port},
+		},
+		PeriodSeconds:  periodSeconds,
+		TimeoutSeconds: timeoutSeconds,
+	}
+}
+
+func TestDefaultController_inferHealthCheck(t *testing.T) {
+	annotatedHealthCheck := &healthcheck.Config{
+		Path:            aws.String(healthcheck.DefaultPath),
+		Port:            aws.String(healthcheck.DefaultPort),
+		Protocol:        aws.String("HTTP"),
+		IntervalSeconds: aws.Int64(healthcheck.DefaultIntervalSeconds),
+		TimeoutSeconds:  aws.Int64(healthcheck.DefaultTimeoutSeconds),
+	}
+
+	for _, tc := range []struct {
+		name                string
+		targetType          string
+		serviceAnnotations  map[string]string
+		pods                []*corev1.Pod
+		tgInstance          *elbv2.TargetGroup
+		expectedHealthCheck *healthcheck.Config
+		expectedEvents      []string
+	}{
+		{
+			name:       "health check is inferred from the readinessProbe on the traffic port",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromString("http"), 0, 0)),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/healthz"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(10),
+				TimeoutSeconds:  aws.Int64(2),
+			},
+		},
+		{
+			name:       "health check is inferred from the readinessProbe on another port with target type ip",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromString("admin"), 30, 10)),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/ready"),
+				Port:            aws.String("8081"),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(30),
+				TimeoutSeconds:  aws.Int64(10),
+			},
+		},
+		{
+			name:       "health check isn't inferred from the readinessProbe on another port with target type instance",
+			targetType: elbv2.TargetTypeEnumInstance,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8081), 30, 10)),
+			},
+			expectedHealthCheck: annotatedHealthCheck,
+		},
+		{
+			name:       "annotated health check settings win",
+			targetType: elbv2.TargetTypeEnumIp,
+			serviceAnnotations: map[string]string{
+				"alb.ingress.kubernetes.io/healthcheck-path":             "/",
+				"alb.ingress.kubernetes.io/healthcheck-interval-seconds": "15",
+			},
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 20, 3)),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(15),
+				TimeoutSeconds:  aws.Int64(3),
+			},
+		},
+		{
+			name:       "interval and timeout aren't inferred if the timeout isn't less than the interval",
+			targetType: elbv2.TargetTypeEnumIp,
+			serviceAnnotations: map[string]string{
+				"alb.ingress.kubernetes.io/healthcheck-interval-seconds": "15",
+			},
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 20, 30)),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/healthz"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(healthcheck.DefaultIntervalSeconds),
+				TimeoutSeconds:  aws.Int64(healthcheck.DefaultTimeoutSeconds),
+			},
+		},
+		{
+			name:       "health check is inferred from the most common readinessProbe if pods disagree",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(nil),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/ready"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(10),
+				TimeoutSeconds:  aws.Int64(2),
+			},
+			expectedEvents: []string{
+				"Warning HEALTHCHECK readinessProbes of pods of service namespace/service disagree, healthcheck is inferred from HTTP traffic-port:/ready interval 10s timeout 2s until they agree: 1 pods probe HTTP traffic-port:/healthz interval 10s timeout 2s, 2 pods probe HTTP traffic-port:/ready interval 10s timeout 2s",
+			},
+		},
+		{
+			name:       "current health check is kept while pods disagree",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+			},
+			tgInstance: &elbv2.TargetGroup{
+				HealthCheckPath:            aws.String("/healthz"),
+				HealthCheckPort:            aws.String(healthcheck.DefaultPort),
+				HealthCheckProtocol:        aws.String("HTTP"),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+				HealthCheckTimeoutSeconds:  aws.Int64(2),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/healthz"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(10),
+				TimeoutSeconds:  aws.Int64(2),
+			},
+			expectedEvents: []string{
+				"Warning HEALTHCHECK readinessProbes of pods of service namespace/service disagree, healthcheck is inferred from HTTP traffic-port:/healthz interval 10s timeout 2s until they agree: 1 pods probe HTTP traffic-port:/healthz interval 10s timeout 2s, 2 pods probe HTTP traffic-port:/ready interval 10s timeout 2s",
+			},
+		},
+		{
+			name:       "current health check matching no readinessProbe isn't kept while pods disagree",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+				newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+			},
+			tgInstance: &elbv2.TargetGroup{
+				HealthCheckPath:            aws.String("/"),
+				HealthCheckPort:            aws.String(healthcheck.DefaultPort),
+				HealthCheckProtocol:        aws.String("HTTP"),
+				HealthCheckIntervalSeconds: aws.Int64(15),
+				HealthCheckTimeoutSeconds:  aws.Int64(5),
+			},
+			expectedHealthCheck: &healthcheck.Config{
+				Path:            aws.String("/ready"),
+				Port:            aws.String(healthcheck.DefaultPort),
+				Protocol:        aws.String("HTTP"),
+				IntervalSeconds: aws.Int64(10),
+				TimeoutSeconds:  aws.Int64(2),
+			},
+			expectedEvents: []string{
+				"Warning HEALTHCHECK readinessProbes of pods of service namespace/service disagree, healthcheck is inferred from HTTP traffic-port:/ready interval 10s timeout 2s until they agree: 1 pods probe HTTP traffic-port:/healthz interval 10s timeout 2s, 2 pods probe HTTP traffic-port:/ready interval 10s timeout 2s",
+			},
+		},
+		{
+			name:       "health check isn't inferred without HTTP readinessProbes",
+			targetType: elbv2.TargetTypeEnumIp,
+			pods: []*corev1.Pod{
+				newProbedPod(nil),
+				newProbedPod(&corev1.Probe{Handler: corev1.Handler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)}}}),
+			},
+			expectedHealthCheck: annotatedHealthCheck,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+			backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)}
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "service", Annotations: tc.serviceAnnotations},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http"), NodePort: 30080}},
+				},
+			}
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetService", "namespace/service").Return(service, nil)
+			endpointResolver := &mocks.EndpointResolver{}
+			endpointResolver.On("ResolvePods", ingress, backend).Return(tc.pods, nil)
+			var events []string
+			ctx := albctx.SetEventf(context.Background(), func(eventType string, reason string, messageFmt string, args ...interface{}) {
+				events = append(events, fmt.Sprintf("%v %v %v", eventType, reason, fmt.Sprintf(messageFmt, args...)))
+			})
+
+			controller := &defaultController{
+				store:            mockStore,
+				endpointResolver: endpointResolver,
+			}
+			healthCheck, err := controller.inferHealthCheck(ctx, ingress, backend, tc.targetType, annotatedHealthCheck, tc.tgInstance)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHealthCheck, healthCheck)
+			assert.Equal(t, tc.expectedEvents, events)
+		})
+	}
+}
+
+func TestDefaultController_inferHealthCheck_disagreementEvents(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "service"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+	disagreeingPods := []*corev1.Pod{
+		newProbedPod(newHTTPGetProbe("/healthz", intstr.FromInt(8080), 10, 2)),
+		newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+	}
+	agreeingPods := []*corev1.Pod{
+		newProbedPod(newHTTPGetProbe("/ready", intstr.FromInt(8080), 10, 2)),
+	}
+	mockStore := &store.MockStorer{}
+	mockStore.On("GetService", "namespace/service").Return(service, nil)
+	endpointResolver := &mocks.EndpointResolver{}
+	events := 0
+	ctx := albctx.SetEventf(context.Background(), func(eventType string, reason string, messageFmt string, args ...interface{}) {
+		events++
+	})
+	controller := &defaultController{
+		store:            mockStore,
+		endpointResolver: endpointResolver,
+	}
+	healthCheck := &healthcheck.Config{
+		Path:            aws.String(healthcheck.DefaultPath),
+		Port:            aws.String(healthcheck.DefaultPort),
+		Protocol:        aws.String("HTTP"),
+		IntervalSeconds: aws.Int64(healthcheck.DefaultIntervalSeconds),
+		TimeoutSeconds:  aws.Int64(healthcheck.DefaultTimeoutSeconds),
+	}
+
+	for _, step := range []struct {
+		pods           []*corev1.Pod
+		expectedEvents int
+	}{
+		{pods: disagreeingPods, expectedEvents: 1},
+		{pods: disagreeingPods, expectedEvents: 1},
+		{pods: agreeingPods, expectedEvents: 1},
+		{pods: disagreeingPods, expectedEvents: 2},
+	} {
+		endpointResolver.On("ResolvePods", ingress, backend).Return(step.pods, nil).Once()
+		_, err := controller.inferHealthCheck(ctx, ingress, backend, elbv2.TargetTypeEnumIp, healthCheck, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, step.expectedEvents, events)
+	}
+}
@@ -0,0 +1,125 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// targetGroupReplacementRequeueAfter is the interval to check the target health of a targetGroup replacing another again
+var targetGroupReplacementRequeueAfter = 5 * time.Second
+
+// targetGroupReplacement is the state of a targetGroup replacing others while it isn't healthy yet
+type targetGroupReplacement struct {
+	// since is the time the targetGroup started waiting to become healthy
+	since time.Time
+	// abandoned is set once the targetGroup didn't become healthy within the timeout
+	abandoned bool
+}
+
+// awaitReplacement returns the ARNs of the other targetGroups of the backend the targetGroup replaces after an immutable setting
+// changed, as long as the configured fraction of its targets isn't healthy, so that they keep serving the requests of the backend.
+// Meanwhile the targetGroup is attached with a weight of 0, since ELBV2 only health checks targets of targetGroups in use,
+// and a RequeueAfter error is returned along with the replaced ARNs, so that the rules switch over once it's healthy.
+// If that doesn't happen within the timeout, the replacement is abandoned: the replaced targetGroups keep serving and the
+// reconciliation is no longer retried for it, though later reconciliations still switch over once the targetGroup is healthy.
+func (controller *defaultController) awaitReplacement(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, tgArn string) ([]string, error) {
+	replacedTGArns, err := controller.findReplacedTGArns(ctx, ingress, backend, tgArn)
+	if err != nil {
+		return nil, err
+	}
+	if len(replacedTGArns) == 0 {
+		controller.replacements.Delete(tgArn)
+		return nil, nil
+	}
+
+	targetHealthDescriptions, err := controller.targetHealthCache.Get(ctx, tgArn)
+	if err != nil {
+		return nil, err
+	}
+	cfg := controller.store.GetConfig()
+	fraction := healthyTargetFraction(targetHealthDescriptions)
+	if fraction >= cfg.TargetGroupReplacementHealthyFraction {
+		controller.replacements.Delete(tgArn)
+		albctx.GetLogger(ctx).Infof("target group %v replacing %v became healthy", tgArn, replacedTGArns)
+		return nil, nil
+	}
+
+	raw, awaiting := controller.replacements.LoadOrStore(tgArn, targetGroupReplacement{since: time.Now()})
+	replacement := raw.(targetGroupReplacement)
+	if !awaiting {
+		albctx.GetLogger(ctx).Infof("waiting for target group %v replacing %v to become healthy", tgArn, replacedTGArns)
+	} else if replacement.abandoned {
+		return replacedTGArns, nil
+	} else if time.Since(replacement.since) > cfg.TargetGroupReplacementTimeout {
+		controller.replacements.Store(tgArn, targetGroupReplacement{since: replacement.since, abandoned: true})
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "REPLACEMENT", "target group %s replacing %v for backend %s:%s didn't become healthy within %v, %.0f%% of targets are healthy, keeping the replaced target groups", tgArn, replacedTGArns, backend.ServiceName, backend.ServicePort.String(), cfg.TargetGroupReplacementTimeout, fraction*100)
+		return replacedTGArns, nil
+	}
+	return replacedTGArns, ingerrors.NewRequeueAfter(targetGroupReplacementRequeueAfter, "target group %v replacing %v is not healthy yet, %.0f%% of targets are healthy", tgArn, replacedTGArns, fraction*100)
+}
+
+// findReplacedTGArns returns the ARNs of the other targetGroups created for the backend, leaving out the ones awaiting their deletion,
+// and the ones still waiting to replace others themselves, which don't serve requests.
+func (controller *defaultController) findReplacedTGArns(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, tgArn string) ([]string, error) {
+	tagFilters := make(map[string][]string)
+	for k, v := range controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name) {
+		tagFilters[k] = []string{v}
+	}
+	for k, v := range controller.nameTagGen.TagTG(ingress.Namespace, ingress.Name, backend.ServiceName, backend.ServicePort.String()) {
+		tagFilters[k] = []string{v}
+	}
+	arns, err := controller.cloud.GetResourcesByFilters(tagFilters, aws.ResourceTypeEnumELBTargetGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get targetGroups due to %v", err)
+	}
+	var otherTGArns []string
+	for _, arn := range arns {
+		if _, replacing := controller.replacements.Load(arn); arn != tgArn && !replacing {
+			otherTGArns = append(otherTGArns, arn)
+		}
+	}
+	if len(otherTGArns) == 0 {
+		return nil, nil
+	}
+	deletionDeadlines, err := describeDeletionDeadlines(ctx, controller.cloud, otherTGArns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe targetGroup tags due to %v", err)
+	}
+	var replacedTGArns []string
+	for _, arn := range otherTGArns {
+		if _, ok := deletionDeadlines[arn]; !ok {
+			replacedTGArns = append(replacedTGArns, arn)
+		}
+	}
+	return replacedTGArns, nil
+}
+
+// healthyTargetFraction returns the fraction of targets that are healthy, leaving out draining targets.
+// Without targets there is nothing to wait for, so the fraction is 1.
+func healthyTargetFraction(targetHealthDescriptions []*elbv2.TargetHealthDescription) float64 {
+	total, healthy := 0, 0
+	for _, desc := range targetHealthDescriptions {
+		if desc.TargetHealth == nil {
+			continue
+		}
+		switch aws.StringValue(desc.TargetHealth.State) {
+		case elbv2.TargetHealthStateEnumDraining:
+			continue
+		case elbv2.TargetHealthStateEnumHealthy:
+			healthy++
+		}
+		total++
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(healthy) / float64(total)
+}
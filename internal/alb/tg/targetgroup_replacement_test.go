@@ -0,0 +1,205 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func targetHealthDescriptions(states ...string) *elbv2.DescribeTargetHealthOutput {
+	output := &elbv2.DescribeTargetHealthOutput{}
+	for _, state := range states {
+		output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		})
+	}
+	return output
+}
+
+func TestDefaultController_awaitReplacement(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)}
+	tagFilters := map[string][]string{"group-tag": {"group-tag-value"}, "tg-tag": {"tg-tag-value"}}
+	deletionDeadline := time.Now().Add(time.Minute)
+	// targets of targetGroups not used by the load balancer yet aren't health checked
+	unused := &elbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+			{TargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumUnused), Reason: aws.String(elbv2.TargetHealthReasonEnumTargetNotInUse)}},
+			{TargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumUnused), Reason: aws.String(elbv2.TargetHealthReasonEnumTargetNotInUse)}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name                   string
+		tgArns                 []string
+		deletionDeadline       *time.Time
+		replacements           map[string]targetGroupReplacement
+		targetHealthOutput     *elbv2.DescribeTargetHealthOutput
+		describeTargetHealth   error
+		expectedReplacedTGArns []string
+		expectedErr            error
+		expectedEvent          bool
+		expectedAbandoned      bool
+	}{
+		{
+			name:   "targetGroup doesn't replace another",
+			tgArns: []string{"arn2"},
+		},
+		{
+			name:             "targetGroup doesn't replace another awaiting its deletion",
+			tgArns:           []string{"arn1", "arn2"},
+			deletionDeadline: &deletionDeadline,
+		},
+		{
+			name:         "targetGroup doesn't replace another still waiting to replace others",
+			tgArns:       []string{"arn1", "arn2"},
+			replacements: map[string]targetGroupReplacement{"arn1": {since: time.Now()}},
+		},
+		{
+			name:               "targetGroup replacing another is healthy",
+			tgArns:             []string{"arn1", "arn2"},
+			targetHealthOutput: targetHealthDescriptions(elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumHealthy),
+		},
+		{
+			name:                   "targetGroup replacing another isn't used by the load balancer yet",
+			tgArns:                 []string{"arn1", "arn2"},
+			targetHealthOutput:     unused,
+			expectedReplacedTGArns: []string{"arn1"},
+			expectedErr:            ingerrors.NewRequeueAfter(5*time.Second, "target group arn2 replacing [arn1] is not healthy yet, %.0f%% of targets are healthy", 0.0),
+		},
+		{
+			name:                   "targetGroup replacing another isn't healthy yet",
+			tgArns:                 []string{"arn1", "arn2"},
+			targetHealthOutput:     targetHealthDescriptions(elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumUnhealthy),
+			expectedReplacedTGArns: []string{"arn1"},
+			expectedErr:            ingerrors.NewRequeueAfter(5*time.Second, "target group arn2 replacing [arn1] is not healthy yet, %.0f%% of targets are healthy", 50.0),
+		},
+		{
+			name:                   "targetGroup replacing another isn't healthy within the timeout",
+			tgArns:                 []string{"arn1", "arn2"},
+			replacements:           map[string]targetGroupReplacement{"arn2": {since: time.Now().Add(-time.Minute)}},
+			targetHealthOutput:     targetHealthDescriptions(elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumUnhealthy),
+			expectedReplacedTGArns: []string{"arn1"},
+			expectedEvent:          true,
+			expectedAbandoned:      true,
+		},
+		{
+			name:                   "abandoned targetGroup replacing another isn't healthy",
+			tgArns:                 []string{"arn1", "arn2"},
+			replacements:           map[string]targetGroupReplacement{"arn2": {since: time.Now().Add(-time.Hour), abandoned: true}},
+			targetHealthOutput:     targetHealthDescriptions(elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumUnhealthy),
+			expectedReplacedTGArns: []string{"arn1"},
+			expectedAbandoned:      true,
+		},
+		{
+			name:               "abandoned targetGroup replacing another became healthy",
+			tgArns:             []string{"arn1", "arn2"},
+			replacements:       map[string]targetGroupReplacement{"arn2": {since: time.Now().Add(-time.Hour), abandoned: true}},
+			targetHealthOutput: targetHealthDescriptions(elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumHealthy),
+		},
+		{
+			name:                 "describing target health fails",
+			tgArns:               []string{"arn1", "arn2"},
+			describeTargetHealth: errors.New("DescribeTargetHealthWithContext"),
+			expectedErr:          errors.New("DescribeTargetHealthWithContext"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			ctx := albctx.SetEventf(context.Background(), func(eventType string, reason string, messageFmt string, args ...interface{}) {
+				events = append(events, reason)
+			})
+			cloud := &mocks.CloudAPI{}
+			mockNameTagGen := &MockNameTagGenerator{}
+			mockNameTagGen.On("TagTGGroup", "namespace", "ingress").Return(map[string]string{"group-tag": "group-tag-value"})
+			mockNameTagGen.On("TagTG", "namespace", "ingress", "service", "80").Return(map[string]string{"tg-tag": "tg-tag-value"})
+			cloud.On("GetResourcesByFilters", tagFilters, aws.ResourceTypeEnumELBTargetGroup).Return(tc.tgArns, nil)
+			if _, replacing := tc.replacements["arn1"]; len(tc.tgArns) > 1 && !replacing {
+				cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"arn1"})}).Return(deletionDeadlineTags("arn1", tc.deletionDeadline), nil)
+			}
+			describeTargetHealthInput := &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String("arn2")}
+			if tc.targetHealthOutput != nil {
+				cloud.On("DescribeTargetHealthWithContext", ctx, describeTargetHealthInput).Return(tc.targetHealthOutput, nil).Once()
+			}
+			if tc.describeTargetHealth != nil {
+				cloud.On("DescribeTargetHealthWithContext", ctx, describeTargetHealthInput).Return(nil, tc.describeTargetHealth)
+			}
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{
+				TargetGroupReplacementHealthyFraction: 1,
+				TargetGroupReplacementTimeout:         20 * time.Second,
+			})
+
+			controller := &defaultController{
+				cloud:             cloud,
+				store:             mockStore,
+				nameTagGen:        mockNameTagGen,
+				targetHealthCache: NewTargetHealthCache(cloud, metric.DummyCollector{}),
+			}
+			for arn, replacement := range tc.replacements {
+				controller.replacements.Store(arn, replacement)
+			}
+			replacedTGArns, err := controller.awaitReplacement(ctx, ingress, backend, "arn2")
+			assert.Equal(t, tc.expectedReplacedTGArns, replacedTGArns)
+			assert.Equal(t, tc.expectedErr, err)
+			if tc.expectedEvent {
+				assert.Equal(t, []string{"REPLACEMENT"}, events)
+			} else {
+				assert.Empty(t, events)
+			}
+			raw, awaiting := controller.replacements.Load("arn2")
+			assert.Equal(t, tc.expectedReplacedTGArns != nil, awaiting)
+			if awaiting {
+				assert.Equal(t, tc.expectedAbandoned, raw.(targetGroupReplacement).abandoned)
+			}
+			cloud.AssertExpectations(t)
+			mockNameTagGen.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_healthyTargetFraction(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		states   []string
+		expected float64
+	}{
+		{
+			name:     "no targets",
+			expected: 1,
+		},
+		{
+			name:     "all targets healthy",
+			states:   []string{elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumHealthy},
+			expected: 1,
+		},
+		{
+			name:     "draining targets are left out",
+			states:   []string{elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumDraining},
+			expected: 1,
+		},
+		{
+			name:     "some targets healthy",
+			states:   []string{elbv2.TargetHealthStateEnumHealthy, elbv2.TargetHealthStateEnumInitial, elbv2.TargetHealthStateEnumUnhealthy, elbv2.TargetHealthStateEnumHealthy},
+			expected: 0.5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, healthyTargetFraction(targetHealthDescriptions(tc.states...).TargetHealthDescriptions))
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
 	annoTags "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +48,7 @@ type NameTGCall struct {
 	ServicePort string
 	TargetType  string
 	Protocol    string
+	Version     string
 	TGName      string
 }
 
@@ -252,6 +254,135 @@ func TestDefaultController_Reconcile(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:    "Reconcile succeeds when creating a GRPC targetGroup",
+			Ingress: ingress,
+			Backend: ingressBackend,
+			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
+				Key:          "namespace/ingress",
+				IngressAnnos: &annotations.Ingress{Tags: &annoTags.Config{}},
+			},
+			GetServiceAnnotationsCall: &GetServiceAnnotationsCall{
+				Key:          "namespace/service",
+				IngressAnnos: &annotations.Ingress{Tags: &annoTags.Config{}},
+				ServiceAnnos: &annotations.Service{
+					HealthCheck: &healthcheck.Config{
+						Path:            aws.String("/ping"),
+						Port:            aws.String("8080"),
+						Protocol:        aws.String("HTTP"),
+						IntervalSeconds: aws.Int64(10),
+						TimeoutSeconds:  aws.Int64(60),
+					},
+					TargetGroup: &targetgroup.Config{
+						BackendProtocol:         aws.String("HTTP"),
+						TargetType:              aws.String("ip"),
+						ProtocolVersion:         aws.String("GRPC"),
+						SuccessCodes:            aws.String("12"),
+						HealthyThresholdCount:   aws.Int64(8),
+						UnhealthyThresholdCount: aws.Int64(5),
+						Attributes: []*elbv2.TargetGroupAttribute{
+							{
+								Key:   aws.String("stickiness.enabled"),
+								Value: aws.String("true"),
+							},
+						},
+					},
+				},
+			},
+			NameTGCall: &NameTGCall{
+				Namespace:   "namespace",
+				IngressName: "ingress",
+				ServiceName: "service",
+				ServicePort: "443",
+				TargetType:  "ip",
+				Protocol:    "HTTP",
+				Version:     "GRPC",
+				TGName:      "k8s-tgName",
+			},
+			TagTGCall: &TagTGCall{
+				ServiceName: "service",
+				ServicePort: "443",
+				Tags:        map[string]string{"tg-tag": "tg-tag-value"},
+			},
+			TagTGGroupCall: &TagTGGroupCall{
+				Namespace:   "namespace",
+				IngressName: "ingress",
+				Tags:        map[string]string{"group-tag": "group-tag-value"},
+			},
+			GetTargetGroupByNameCall: &GetTargetGroupByNameCall{
+				TGName:   "k8s-tgName",
+				Instance: nil,
+			},
+			CreateTargetGroupCall: &CreateTargetGroupCall{
+				Input: &elbv2.CreateTargetGroupInput{
+					Name:                       aws.String("k8s-tgName"),
+					HealthCheckPath:            aws.String("/ping"),
+					HealthCheckPort:            aws.String("8080"),
+					HealthCheckProtocol:        aws.String("HTTP"),
+					HealthCheckIntervalSeconds: aws.Int64(10),
+					HealthCheckTimeoutSeconds:  aws.Int64(60),
+					Protocol:                   aws.String("HTTP"),
+					ProtocolVersion:            aws.String("GRPC"),
+					TargetType:                 aws.String("ip"),
+					Matcher:                    &elbv2.Matcher{GrpcCode: aws.String("12")},
+					HealthyThresholdCount:      aws.Int64(8),
+					UnhealthyThresholdCount:    aws.Int64(5),
+					Port:                       aws.Int64(targetGroupDefaultPort),
+				},
+				Instance: &elbv2.TargetGroup{
+					TargetGroupArn:             aws.String("MyTargetGroupArn"),
+					HealthCheckPath:            aws.String("/ping"),
+					HealthCheckPort:            aws.String("8080"),
+					HealthCheckProtocol:        aws.String("HTTP"),
+					HealthCheckIntervalSeconds: aws.Int64(10),
+					HealthCheckTimeoutSeconds:  aws.Int64(60),
+					Protocol:                   aws.String("HTTP"),
+					ProtocolVersion:            aws.String("GRPC"),
+					TargetType:                 aws.String("ip"),
+					Matcher:                    &elbv2.Matcher{GrpcCode: aws.String("12")},
+					HealthyThresholdCount:      aws.Int64(8),
+					UnhealthyThresholdCount:    aws.Int64(5),
+				},
+			},
+			TagsReconcileCall: &TagsReconcileCall{
+				Arn:  "MyTargetGroupArn",
+				Tags: map[string]string{"tg-tag": "tg-tag-value", "group-tag": "group-tag-value"},
+			},
+			AttributesReconcileCall: &AttributesReconcileCall{
+				TGArn: "MyTargetGroupArn",
+				Attributes: []*elbv2.TargetGroupAttribute{
+					{
+						Key:   aws.String("stickiness.enabled"),
+						Value: aws.String("true"),
+					},
+				},
+			},
+			TargetsReconcileCall: &TargetsReconcileCall{
+				Targets: &Targets{
+					TgArn:      "MyTargetGroupArn",
+					TargetType: "ip",
+					Ingress:    &ingress,
+					Backend:    &ingressBackend,
+				},
+				ResultTargets: []*elbv2.TargetDescription{
+					{
+						Id:   aws.String("instance-id"),
+						Port: aws.Int64(8888),
+					},
+				},
+			},
+			ExpectedTG: TargetGroup{
+				Arn:        "MyTargetGroupArn",
+				TargetType: "ip",
+				Targets: []*elbv2.TargetDescription{
+					{
+						Id:   aws.String("instance-id"),
+						Port: aws.Int64(8888),
+					},
+				},
+				ProtocolVersion: "GRPC",
+			},
+		},
 		{
 			Name:    "Reconcile succeeds when looking up a service port by name for target-type=instance. ",
 			Ingress: ingress,
@@ -1261,6 +1392,7 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			}
 
 			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Maybe().Return(&config.Configuration{FeatureGate: config.NewFeatureGate()})
 			if tc.GetIngressAnnotationsCall != nil {
 				mockStore.On("GetIngressAnnotations", tc.GetIngressAnnotationsCall.Key).Return(tc.GetIngressAnnotationsCall.IngressAnnos, tc.GetIngressAnnotationsCall.Err)
 			}
@@ -1274,7 +1406,7 @@ func TestDefaultController_Reconcile(t *testing.T) {
 
 			mockNameTagGen := &MockNameTagGenerator{}
 			if tc.NameTGCall != nil {
-				mockNameTagGen.On("NameTG", tc.NameTGCall.Namespace, tc.NameTGCall.IngressName, tc.NameTGCall.ServiceName, tc.NameTGCall.ServicePort, tc.NameTGCall.TargetType, tc.NameTGCall.Protocol).Return(tc.NameTGCall.TGName)
+				mockNameTagGen.On("NameTG", tc.NameTGCall.Namespace, tc.NameTGCall.IngressName, tc.NameTGCall.ServiceName, tc.NameTGCall.ServicePort, tc.NameTGCall.TargetType, tc.NameTGCall.Protocol, tc.NameTGCall.Version).Return(tc.NameTGCall.TGName)
 			}
 			if tc.TagTGCall != nil {
 				mockNameTagGen.On("TagTG", tc.TagTGGroupCall.Namespace, tc.TagTGGroupCall.IngressName, tc.TagTGCall.ServiceName, tc.TagTGCall.ServicePort).Return(tc.TagTGCall.Tags)
@@ -1323,3 +1455,177 @@ func TestDefaultController_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultController_resolveServiceHealthCheckPort(t *testing.T) {
+	for _, tc := range []struct {
+		Name           string
+		Port           intstr.IntOrString
+		TargetType     string
+		GetServiceCall *GetServiceCall
+		ExpectedPort   string
+	}{
+		{
+			Name:         "traffic-port for target-type=ip",
+			Port:         intstr.FromString(healthcheck.DefaultPort),
+			TargetType:   elbv2.TargetTypeEnumIp,
+			ExpectedPort: healthcheck.DefaultPort,
+		},
+		{
+			Name:       "traffic-port for target-type=instance with externalTrafficPolicy Cluster",
+			Port:       intstr.FromString(healthcheck.DefaultPort),
+			TargetType: elbv2.TargetTypeEnumInstance,
+			GetServiceCall: &GetServiceCall{
+				Key: "namespace/service",
+				service: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+					},
+				},
+			},
+			ExpectedPort: healthcheck.DefaultPort,
+		},
+		{
+			Name:       "traffic-port for target-type=instance with externalTrafficPolicy Local",
+			Port:       intstr.FromString(healthcheck.DefaultPort),
+			TargetType: elbv2.TargetTypeEnumInstance,
+			GetServiceCall: &GetServiceCall{
+				Key: "namespace/service",
+				service: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+						HealthCheckNodePort:   32000,
+					},
+				},
+			},
+			ExpectedPort: "32000",
+		},
+		{
+			Name:         "explicit port for target-type=instance",
+			Port:         intstr.FromInt(8080),
+			TargetType:   elbv2.TargetTypeEnumInstance,
+			ExpectedPort: "8080",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockStore := &store.MockStorer{}
+			if tc.GetServiceCall != nil {
+				mockStore.On("GetService", tc.GetServiceCall.Key).Return(tc.GetServiceCall.service, tc.GetServiceCall.Err)
+			}
+			controller := &defaultController{
+				store: mockStore,
+			}
+
+			port, err := controller.resolveServiceHealthCheckPort("namespace", "service", tc.Port, tc.TargetType)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedPort, port)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultController_resolveKubeProxyHealthCheck(t *testing.T) {
+	localService := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			HealthCheckNodePort:   32000,
+		},
+	}
+	for _, tc := range []struct {
+		Name                string
+		HealthCheck         *healthcheck.Config
+		TargetType          string
+		IngressAnnotations  map[string]string
+		GetServiceCall      *GetServiceCall
+		ExpectedHealthCheck *healthcheck.Config
+	}{
+		{
+			Name:                "target-type=ip",
+			HealthCheck:         &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+			TargetType:          elbv2.TargetTypeEnumIp,
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+		},
+		{
+			Name:                "explicit port for target-type=instance",
+			HealthCheck:         &healthcheck.Config{Port: aws.String("8080"), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+			TargetType:          elbv2.TargetTypeEnumInstance,
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String("8080"), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+		},
+		{
+			Name:        "traffic-port for target-type=instance with externalTrafficPolicy Cluster",
+			HealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+			TargetType:  elbv2.TargetTypeEnumInstance,
+			GetServiceCall: &GetServiceCall{
+				Key: "namespace/service",
+				service: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+					},
+				},
+			},
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+		},
+		{
+			Name:        "traffic-port for target-type=instance with externalTrafficPolicy Local and backend-protocol HTTPS",
+			HealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+			TargetType:  elbv2.TargetTypeEnumInstance,
+			GetServiceCall: &GetServiceCall{
+				Key: "namespace/service",
+				service: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"alb.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+					},
+					Spec: localService.Spec,
+				},
+			},
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTP"), Path: aws.String("/healthz")},
+		},
+		{
+			Name:        "traffic-port for target-type=instance with externalTrafficPolicy Local and annotated path",
+			HealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTP"), Path: aws.String("/ping")},
+			TargetType:  elbv2.TargetTypeEnumInstance,
+			GetServiceCall: &GetServiceCall{
+				Key: "namespace/service",
+				service: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"alb.ingress.kubernetes.io/healthcheck-path": "/ping"},
+					},
+					Spec: localService.Spec,
+				},
+			},
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTP"), Path: aws.String("/ping")},
+		},
+		{
+			Name:               "traffic-port for target-type=instance with externalTrafficPolicy Local and protocol annotated on ingress",
+			HealthCheck:        &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/")},
+			TargetType:         elbv2.TargetTypeEnumInstance,
+			IngressAnnotations: map[string]string{"alb.ingress.kubernetes.io/healthcheck-protocol": "HTTPS"},
+			GetServiceCall: &GetServiceCall{
+				Key:     "namespace/service",
+				service: localService,
+			},
+			ExpectedHealthCheck: &healthcheck.Config{Port: aws.String(healthcheck.DefaultPort), Protocol: aws.String("HTTPS"), Path: aws.String("/healthz")},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockStore := &store.MockStorer{}
+			if tc.GetServiceCall != nil {
+				mockStore.On("GetService", tc.GetServiceCall.Key).Return(tc.GetServiceCall.service, tc.GetServiceCall.Err)
+			}
+			controller := &defaultController{
+				store: mockStore,
+			}
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "namespace",
+					Name:        "ingress",
+					Annotations: tc.IngressAnnotations,
+				},
+			}
+
+			healthCheck, err := controller.resolveKubeProxyHealthCheck(ingress, "service", tc.HealthCheck, tc.TargetType)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedHealthCheck, healthCheck)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,10 @@ package tg
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -10,14 +14,29 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
 	backendpkg "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	api "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// PodDeregistrationDelayAnnotation is set on terminating pods deregistered from target groups of target-type ip,
+	// its value is the deregistration delay of the target group in seconds.
+	PodDeregistrationDelayAnnotation = "target-health.alb.ingress.k8s.aws/deregistration-delay-seconds"
+
+	// PodDeregistrationDeadlineAnnotation is set on terminating pods deregistered from target groups of target-type ip,
+	// its value is the RFC3339 time after which the target is no longer draining and the pod can safely exit.
+	PodDeregistrationDeadlineAnnotation = "target-health.alb.ingress.k8s.aws/deregistration-deadline"
 )
 
 type targetGroupWatch struct {
@@ -27,54 +46,124 @@ type targetGroupWatch struct {
 	// backend is the ingress backend for the target group
 	backend *extensions.IngressBackend
 
-	// channels used to communicate the reconiliation interval and list of targets to reconcile into the go routine
-	interval           chan int64
-	targetsToReconcile chan []*elbv2.TargetDescription
-	cancel             context.CancelFunc
+	// targetType is the target type of the target group
+	targetType string
+
+	// updates communicates the reconciliation interval and list of targets to reconcile into the go routine,
+	// it holds the latest update only, so that sending never blocks the reconcile.
+	updates chan targetGroupWatchUpdate
+	cancel  context.CancelFunc
+}
+
+type targetGroupWatchUpdate struct {
+	interval           int64
+	targetsToReconcile []*elbv2.TargetDescription
 }
 
 type targetGroupWatches map[string]*targetGroupWatch
 
-func newTargetGroupWatch(ctx context.Context, ingress *extensions.Ingress, backend *extensions.IngressBackend) (*targetGroupWatch, context.Context) {
+func newTargetGroupWatch(ctx context.Context, ingress *extensions.Ingress, backend *extensions.IngressBackend, targetType string) (*targetGroupWatch, context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
 	return &targetGroupWatch{
-		ingress:            ingress,
-		backend:            backend,
-		interval:           make(chan int64),
-		targetsToReconcile: make(chan []*elbv2.TargetDescription),
-		cancel:             cancel,
+		ingress:    ingress,
+		backend:    backend,
+		targetType: targetType,
+		updates:    make(chan targetGroupWatchUpdate, 1),
+		cancel:     cancel,
 	}, ctx
 }
 
+// update sends update to the go routine without blocking, replacing the previous update if it wasn't received yet.
+func (w *targetGroupWatch) update(update targetGroupWatchUpdate) {
+	for {
+		select {
+		case w.updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-w.updates:
+		default:
+		}
+	}
+}
+
 // TargetHealthController provides functionality to reconcile pod condition status from target health of targets in a target group
 type TargetHealthController interface {
-	// Reconcile ensures the target group targets in AWS matches the targets configured in the ingress backend.
+	// SyncTargetsForReconciliation starts or stops reconciling the pod condition statuses of the desired targets of the target group.
 	SyncTargetsForReconciliation(ctx context.Context, t *Targets, desiredTargets []*elbv2.TargetDescription) error
 	RemovePodConditions(ctx context.Context, t *Targets, targets []*elbv2.TargetDescription) error
+	// SignalPodDeregistration signals the terminating pods of deregistered targets, and of draining targets that weren't signaled yet,
+	// when they can exit.
+	SignalPodDeregistration(ctx context.Context, t *Targets, deregisteredTargets []*elbv2.TargetDescription, drainingTargets []*elbv2.TargetDescription) error
 	StopReconcilingPodConditionStatus(tgArn string)
+
+	// ReportTargetHealth reports the target health of the target group as metrics of the ingress backend,
+	// and records an event once the backend has no healthy targets left. It keeps reporting from a poller until the target group stops being reconciled.
+	ReportTargetHealth(ctx context.Context, t *Targets, targetHealthDescriptions []*elbv2.TargetHealthDescription)
+
+	// Start blocks until stop is closed, then stops reconciling pod condition statuses of all target groups.
+	manager.Runnable
 }
 
 // NewTargetHealthController constructs a new target health controller
-func NewTargetHealthController(cloud aws.CloudAPI, store store.Storer, endpointResolver backend.EndpointResolver, client client.Client) TargetHealthController {
+func NewTargetHealthController(cloud aws.CloudAPI, store store.Storer, endpointResolver backend.EndpointResolver, client client.Client, podClient corev1client.PodsGetter, targetHealthCache TargetHealthCache, metricCollector metric.Collector) TargetHealthController {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &targetHealthController{
-		cloud:            cloud,
-		store:            store,
-		endpointResolver: endpointResolver,
-		client:           client,
-		tgWatches:        make(targetGroupWatches),
+		cloud:             cloud,
+		store:             store,
+		endpointResolver:  endpointResolver,
+		client:            client,
+		podClient:         podClient,
+		targetHealthCache: targetHealthCache,
+		reporter:          newTargetHealthReporter(metricCollector),
+		ctx:               ctx,
+		cancel:            cancel,
+		tgWatches:         make(targetGroupWatches),
 	}
 }
 
 type targetHealthController struct {
-	cloud            aws.CloudAPI
-	store            store.Storer
-	endpointResolver backend.EndpointResolver
-	client           client.Client
-	tgWatches        targetGroupWatches
+	cloud             aws.CloudAPI
+	store             store.Storer
+	endpointResolver  backend.EndpointResolver
+	client            client.Client
+	podClient         corev1client.PodsGetter // patches pods, which the client can't do
+	targetHealthCache TargetHealthCache
+	reporter          *targetHealthReporter
+
+	// ctx is the parent context of all targetGroupWatches, it's cancelled once the manager stops.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mutex guards tgWatches, which are synced by concurrent reconciles
+	mutex     sync.Mutex
+	tgWatches targetGroupWatches
+}
+
+// Start blocks until stop is closed, then stops the go routines reconciling pod condition statuses.
+func (c *targetHealthController) Start(stop <-chan struct{}) error {
+	<-stop
+	c.cancel()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tgWatches = make(targetGroupWatches)
+	return nil
 }
 
 // SyncTargetsForReconciliation starts a go routine for reconciling pod condition statuses for the given targets in the background until they are healthy in the target group
 func (c *targetHealthController) SyncTargetsForReconciliation(ctx context.Context, t *Targets, desiredTargets []*elbv2.TargetDescription) error {
+	if t.TargetType == elbv2.TargetTypeEnumInstance {
+		if !c.store.GetConfig().FeatureGate.Enabled(config.InstanceTargetReadinessGate) {
+			return nil
+		}
+		// instance targets don't map to pods, so the pods are tracked by their IP targets instead
+		var err error
+		if desiredTargets, err = c.endpointResolver.Resolve(t.Ingress, t.Backend, elbv2.TargetTypeEnumIp); err != nil {
+			return err
+		}
+	}
 	readinessConditionTypes := []api.PodConditionType{
 		backendpkg.PodReadinessGateConditionType(t.Ingress, t.Backend),
 		backendpkg.AnyLBTGReadyConditionType,
@@ -84,9 +173,19 @@ func (c *targetHealthController) SyncTargetsForReconciliation(ctx context.Contex
 		return err
 	}
 
+	var interval int64
+	if len(targetsToReconcile) > 0 {
+		interval = c.ingressTargetHealthReconciliationInterval(t.Backend.ServiceName, t.Ingress)
+	}
+
 	// create, update or remove targetGroupWatch for this target group;
 	// a targetGroupWatch exists as long as there are targets in the target group whose pod condition statuses need to be reconciled;
 	// while the targetGroupWatch exists, a go routine regularly monitors the target health of the targets in the target group and updates the pod condition status for the corresponding pods
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.ctx.Err() != nil {
+		return nil
+	}
 	tgWatch, ok := c.tgWatches[t.TgArn]
 	if ok {
 		if len(targetsToReconcile) == 0 {
@@ -99,26 +198,52 @@ func (c *targetHealthController) SyncTargetsForReconciliation(ctx context.Contex
 			return nil
 		}
 
-		// targetGroupWatch for this target group doesn't exist yet -> create it
-		tgWatch, ctx = newTargetGroupWatch(ctx, t.Ingress, t.Backend)
+		// targetGroupWatch for this target group doesn't exist yet -> create it;
+		// it outlives the reconcile, so it's derived from the controller's context, keeping the reconcile's logger and events
+		watchCtx := albctx.SetEventf(albctx.SetLogger(c.ctx, albctx.GetLogger(ctx)), albctx.GetEventf(ctx))
+		tgWatch, watchCtx = newTargetGroupWatch(watchCtx, t.Ingress, t.Backend, t.TargetType)
 		c.tgWatches[t.TgArn] = tgWatch
 
 		// start watching target health in target group and updating pod condition status
-		go c.reconcilePodConditionsLoop(ctx, t.TgArn, tgWatch, readinessConditionTypes...)
+		go c.reconcilePodConditionsLoop(watchCtx, t.TgArn, tgWatch, readinessConditionTypes...)
 	}
-	tgWatch.interval <- c.ingressTargetHealthReconciliationInterval(t.Backend.ServiceName, t.Ingress)
-	tgWatch.targetsToReconcile <- targetsToReconcile
+	tgWatch.update(targetGroupWatchUpdate{
+		interval:           interval,
+		targetsToReconcile: targetsToReconcile,
+	})
 
 	return nil
 }
 
 // StopReconcilingPodConditionStatus stops a running go routine (if there is any) which was started to reconcile pod condition statuses in the background for a specific target group
 func (c *targetHealthController) StopReconcilingPodConditionStatus(tgArn string) {
+	c.mutex.Lock()
 	tgWatch, ok := c.tgWatches[tgArn]
 	if ok {
 		tgWatch.cancel()
 		delete(c.tgWatches, tgArn)
 	}
+	c.mutex.Unlock()
+	c.targetHealthCache.StopPolling(tgArn)
+	c.reporter.forget(tgArn)
+}
+
+// ReportTargetHealth reports the target health of the target group, and polls it in the background at the health check interval
+// to keep reporting it until the target group is no longer reconciled, so that backends losing their healthy targets are reported
+// without waiting for the next reconcile. The poller also serves the pod condition statuses of the target group from the cache.
+func (c *targetHealthController) ReportTargetHealth(ctx context.Context, t *Targets, targetHealthDescriptions []*elbv2.TargetHealthDescription) {
+	c.reporter.report(ctx, t.TgArn, t.Ingress, t.Backend, targetHealthDescriptions)
+
+	if c.ctx.Err() != nil {
+		return
+	}
+	// the poller outlives the reconcile, so it's derived from the controller's context, keeping the reconcile's logger and events
+	pollCtx := albctx.SetEventf(albctx.SetLogger(c.ctx, albctx.GetLogger(ctx)), albctx.GetEventf(ctx))
+	interval := c.ingressTargetHealthReconciliationInterval(t.Backend.ServiceName, t.Ingress)
+	tgArn, ingress, backend := t.TgArn, t.Ingress, t.Backend
+	c.targetHealthCache.Poll(pollCtx, tgArn, time.Duration(interval)*time.Second, func(targetHealthDescriptions []*elbv2.TargetHealthDescription) {
+		c.reporter.report(pollCtx, tgArn, ingress, backend, targetHealthDescriptions)
+	})
 }
 
 // RemovePodConditions removes the condition (that we added before for the given ingress/backend) from the given pods
@@ -136,6 +261,10 @@ func (c *targetHealthController) RemovePodConditions(ctx context.Context, t *Tar
 		if pod == nil {
 			continue
 		}
+		// terminating pods keep their conditions, which show them draining instead
+		if pod.DeletionTimestamp != nil && c.store.GetConfig().FeatureGate.Enabled(config.PodDeregistrationSignal) {
+			continue
+		}
 		needUpdates := false
 		for _, conditionType := range readinessConditionTypes {
 			i, cond := backendpkg.PodConditionForReadinessGate(pod, conditionType)
@@ -154,6 +283,106 @@ func (c *targetHealthController) RemovePodConditions(ctx context.Context, t *Tar
 	return nil
 }
 
+// SignalPodDeregistration annotates the terminating pods of the given deregistered targets with the deregistration delay of the target group,
+// so that a preStop hook can wait until the target stops draining before the pod exits. The pod conditions of the target group show the pods
+// draining until the deadline.
+// The terminating pods of draining targets, which were deregistered by an earlier reconcile, are only annotated if they weren't yet,
+// like when signaling them failed, so that their deadline isn't pushed back.
+// Pods are looked up by IP since terminating pods are no longer part of the service endpoints.
+func (c *targetHealthController) SignalPodDeregistration(ctx context.Context, t *Targets, deregisteredTargets []*elbv2.TargetDescription, drainingTargets []*elbv2.TargetDescription) error {
+	if !c.store.GetConfig().FeatureGate.Enabled(config.PodDeregistrationSignal) || len(deregisteredTargets)+len(drainingTargets) == 0 {
+		return nil
+	}
+	deregisteredIPs := sets.NewString()
+	for _, target := range deregisteredTargets {
+		deregisteredIPs.Insert(aws.StringValue(target.Id))
+	}
+	drainingIPs := sets.NewString()
+	for _, target := range drainingTargets {
+		drainingIPs.Insert(aws.StringValue(target.Id))
+	}
+	podList := &api.PodList{}
+	if err := c.client.List(ctx, client.InNamespace(t.Ingress.Namespace), podList); err != nil {
+		return err
+	}
+
+	delaySeconds := c.deregistrationDelaySeconds(t.Backend.ServiceName, t.Ingress)
+	deadline := time.Now().Add(time.Duration(delaySeconds) * time.Second).UTC()
+	drainingHealth := &elbv2.TargetHealth{
+		State:       aws.String(elbv2.TargetHealthStateEnumDraining),
+		Reason:      aws.String(elbv2.TargetHealthReasonEnumTargetDeregistrationInProgress),
+		Description: aws.String(fmt.Sprintf("Target is draining until %v.", deadline.Format(time.RFC3339))),
+	}
+	readinessConditionTypes := []api.PodConditionType{
+		backendpkg.PodReadinessGateConditionType(t.Ingress, t.Backend),
+		backendpkg.AnyLBTGReadyConditionType,
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp == nil {
+			continue
+		}
+		existingDeadline, err := time.Parse(time.RFC3339, pod.Annotations[PodDeregistrationDeadlineAnnotation])
+		signaled := err == nil
+		switch {
+		case deregisteredIPs.Has(pod.Status.PodIP):
+			// a pod can be a target of multiple target groups, it must wait for the one that finishes draining last
+			if !signaled || !existingDeadline.After(deadline) {
+				if pod, err = c.patchPodDeregistrationAnnotations(pod, delaySeconds, deadline); err != nil {
+					return err
+				}
+			}
+		case drainingIPs.Has(pod.Status.PodIP) && !signaled:
+			if pod, err = c.patchPodDeregistrationAnnotations(pod, delaySeconds, deadline); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		if pod == nil {
+			continue
+		}
+
+		needsUpdate := false
+		for _, conditionType := range readinessConditionTypes {
+			if backendpkg.PodHasReadinessGate(pod, conditionType) {
+				updatePodReadinessCondition(pod, drainingHealth, conditionType)
+				needsUpdate = true
+			}
+		}
+		if needsUpdate {
+			if err := c.client.Status().Update(ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// patchPodDeregistrationAnnotations sets the deregistration annotations of pod with a strategic merge patch, so that it doesn't conflict with
+// other updates of the terminating pod. It returns the patched pod, or nil if the pod is gone.
+func (c *targetHealthController) patchPodDeregistrationAnnotations(pod *api.Pod, delaySeconds int64, deadline time.Time) (*api.Pod, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				PodDeregistrationDelayAnnotation:    strconv.FormatInt(delaySeconds, 10),
+				PodDeregistrationDeadlineAnnotation: deadline.Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	patchedPod, err := c.podClient.Pods(pod.Namespace).Patch(pod.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return patchedPod, nil
+}
+
 // Background loop which keeps reconciling pod condition statuses for the given target groups until the given context is cancelled.
 func (c *targetHealthController) reconcilePodConditionsLoop(ctx context.Context, tgArn string, tgWatch *targetGroupWatch, readinessConditionTypes ...api.PodConditionType) {
 	logger := albctx.GetLogger(ctx)
@@ -169,11 +398,12 @@ func (c *targetHealthController) reconcilePodConditionsLoop(ctx context.Context,
 		}
 
 		select {
-		case interval = <-tgWatch.interval: // update interval
-		case targetsToReconcile = <-tgWatch.targetsToReconcile: // update targets
+		case update := <-tgWatch.updates: // update interval and targets
+			interval = update.interval
+			targetsToReconcile = update.targetsToReconcile
 
 		case <-reconcile:
-			notReadyTargets, err := c.reconcilePodConditions(ctx, tgArn, tgWatch.ingress, tgWatch.backend, targetsToReconcile, readinessConditionTypes...)
+			notReadyTargets, err := c.reconcilePodConditions(ctx, tgArn, tgWatch.ingress, tgWatch.backend, tgWatch.targetType, targetsToReconcile, readinessConditionTypes...)
 			if err == nil {
 				targetsToReconcile = notReadyTargets
 			} else {
@@ -189,21 +419,28 @@ func (c *targetHealthController) reconcilePodConditionsLoop(ctx context.Context,
 }
 
 // For each given pod, checks for the health status of the corresponding target in the target group and adds/updates a pod condition that can be used for pod readiness gates.
-func (c *targetHealthController) reconcilePodConditions(ctx context.Context, tgArn string, ingress *extensions.Ingress, backend *extensions.IngressBackend, targetsToReconcile []*elbv2.TargetDescription, readinessConditionTypes ...api.PodConditionType) ([]*elbv2.TargetDescription, error) {
+// With target type == instance, the given targets are the IP targets of the pods, whose health status is approximated from the nodes.
+func (c *targetHealthController) reconcilePodConditions(ctx context.Context, tgArn string, ingress *extensions.Ingress, backend *extensions.IngressBackend, targetType string, targetsToReconcile []*elbv2.TargetDescription, readinessConditionTypes ...api.PodConditionType) ([]*elbv2.TargetDescription, error) {
 	var notReadyTargets []*elbv2.TargetDescription
 
-	in := &elbv2.DescribeTargetHealthInput{
-		TargetGroupArn: aws.String(tgArn),
-		Targets:        targetsToReconcile,
-	}
-	resp, err := c.cloud.DescribeTargetHealthWithContext(ctx, in)
+	targetHealthDescriptions, err := c.targetHealthCache.Get(ctx, tgArn)
 	if err != nil {
 		return notReadyTargets, err
 	}
+	c.reporter.report(ctx, tgArn, ingress, backend, targetHealthDescriptions)
 	targetsHealth := map[string]*elbv2.TargetHealth{}
-	for _, desc := range resp.TargetHealthDescriptions {
+	for _, desc := range targetHealthDescriptions {
 		targetsHealth[*desc.Target.Id] = desc.TargetHealth
 	}
+	podTargetHealth := func(pod *api.Pod) (*elbv2.TargetHealth, bool) {
+		targetHealth, ok := targetsHealth[pod.Status.PodIP]
+		return targetHealth, ok
+	}
+	if targetType == elbv2.TargetTypeEnumInstance {
+		if podTargetHealth, err = c.instancePodTargetHealth(ingress, backend, targetHealthDescriptions); err != nil {
+			return notReadyTargets, err
+		}
+	}
 
 	pods, err := c.endpointResolver.ReverseResolve(ingress, backend, targetsToReconcile)
 	if err != nil {
@@ -215,7 +452,7 @@ func (c *targetHealthController) reconcilePodConditions(ctx context.Context, tgA
 		if pod == nil {
 			continue
 		}
-		targetHealth, ok := targetsHealth[pod.Status.PodIP]
+		targetHealth, ok := podTargetHealth(pod)
 		if !ok {
 			continue
 		}
@@ -282,7 +519,7 @@ func (c *targetHealthController) ingressTargetHealthReconciliationInterval(servi
 	if err == nil {
 		serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: serviceName}
 		serviceAnnos, err := c.store.GetServiceAnnotations(serviceKey.String(), ingressAnnos)
-		if err == nil {
+		if err == nil && serviceAnnos.HealthCheck != nil && serviceAnnos.HealthCheck.IntervalSeconds != nil {
 			return *serviceAnnos.HealthCheck.IntervalSeconds
 		}
 	}
@@ -290,6 +527,21 @@ func (c *targetHealthController) ingressTargetHealthReconciliationInterval(servi
 	return healthcheck.DefaultIntervalSeconds
 }
 
+func (c *targetHealthController) deregistrationDelaySeconds(serviceName string, ingress *extensions.Ingress) int64 {
+	ingressAnnos, err := c.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	if err == nil {
+		serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: serviceName}
+		serviceAnnos, err := c.store.GetServiceAnnotations(serviceKey.String(), ingressAnnos)
+		if err == nil {
+			if attributes, err := NewAttributes(serviceAnnos.TargetGroup.Attributes); err == nil {
+				return attributes.DeregistrationDelayTimeoutSeconds
+			}
+		}
+	}
+
+	return DeregistrationDelayTimeoutSeconds
+}
+
 // updatePodReadinessCondition will creates or updates the condition status for the given pod with the given target health
 // returns whether pod readinessGate becomes healthy
 func updatePodReadinessCondition(pod *api.Pod, targetHealth *elbv2.TargetHealth, conditionType api.PodConditionType) bool {
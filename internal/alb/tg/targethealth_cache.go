@@ -0,0 +1,192 @@
+package tg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// targetHealthCacheTTL is how long the target health of target groups without poller is cached
+	targetHealthCacheTTL = 5 * time.Second
+
+	// targetHealthPollJitterFactor spreads the polls of target groups with the same interval over time
+	targetHealthPollJitterFactor = 0.2
+)
+
+// TargetHealthCache caches the target health of target groups, so that pod condition statuses reuse the target health
+// described by pollers and by reconciling targets, instead of calling DescribeTargetHealth for each target group on their own.
+type TargetHealthCache interface {
+	// Get returns the health of all targets in the target group, from the cache if it's fresh, or from AWS otherwise.
+	Get(ctx context.Context, tgArn string) ([]*elbv2.TargetHealthDescription, error)
+
+	// Refresh returns the health of all targets in the target group from AWS, bypassing the cache, and caches it.
+	Refresh(ctx context.Context, tgArn string) ([]*elbv2.TargetHealthDescription, error)
+
+	// Invalidate drops the cached target health of the target group, e.g. once targets are registered or deregistered.
+	Invalidate(tgArn string)
+
+	// Poll starts or updates a single poller of the target group, refreshing its target health every jittered interval until ctx is done.
+	// onRefresh is invoked with the target health of each successful poll.
+	Poll(ctx context.Context, tgArn string, interval time.Duration, onRefresh func(targetHealthDescriptions []*elbv2.TargetHealthDescription))
+
+	// StopPolling stops the poller of the target group and drops its cached target health.
+	StopPolling(tgArn string)
+}
+
+// NewTargetHealthCache constructs a new target health cache
+func NewTargetHealthCache(cloud aws.CloudAPI, metricCollector metric.Collector) TargetHealthCache {
+	return &targetHealthCache{
+		cloud:           cloud,
+		metricCollector: metricCollector,
+		entries:         make(map[string]*targetHealthCacheEntry),
+		generations:     make(map[string]uint64),
+		pollers:         make(map[string]*targetHealthPoller),
+	}
+}
+
+type targetHealthCacheEntry struct {
+	targetHealthDescriptions []*elbv2.TargetHealthDescription
+	refreshTime              time.Time
+}
+
+type targetHealthPoller struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	interval  time.Duration
+	onRefresh func(targetHealthDescriptions []*elbv2.TargetHealthDescription)
+}
+
+var _ TargetHealthCache = (*targetHealthCache)(nil)
+
+type targetHealthCache struct {
+	cloud           aws.CloudAPI
+	metricCollector metric.Collector
+
+	mutex   sync.Mutex
+	entries map[string]*targetHealthCacheEntry
+	// generations are incremented when the target health of target groups is invalidated,
+	// so that refreshes started before are not cached.
+	generations map[string]uint64
+	pollers     map[string]*targetHealthPoller
+}
+
+func (c *targetHealthCache) Get(ctx context.Context, tgArn string) ([]*elbv2.TargetHealthDescription, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[tgArn]
+	fresh := ok && time.Since(entry.refreshTime) < c.ttl(tgArn)
+	c.mutex.Unlock()
+	if fresh {
+		c.metricCollector.IncTargetHealthCacheHitCount()
+		return entry.targetHealthDescriptions, nil
+	}
+
+	c.metricCollector.IncTargetHealthCacheMissCount()
+	return c.refresh(ctx, tgArn)
+}
+
+func (c *targetHealthCache) Refresh(ctx context.Context, tgArn string) ([]*elbv2.TargetHealthDescription, error) {
+	return c.refresh(ctx, tgArn)
+}
+
+func (c *targetHealthCache) Invalidate(tgArn string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, tgArn)
+	c.generations[tgArn]++
+}
+
+func (c *targetHealthCache) Poll(ctx context.Context, tgArn string, interval time.Duration, onRefresh func(targetHealthDescriptions []*elbv2.TargetHealthDescription)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if poller, ok := c.pollers[tgArn]; ok {
+		if poller.interval == interval && poller.ctx.Err() == nil {
+			poller.onRefresh = onRefresh
+			return
+		}
+		poller.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	poller := &targetHealthPoller{
+		ctx:       ctx,
+		cancel:    cancel,
+		interval:  interval,
+		onRefresh: onRefresh,
+	}
+	c.pollers[tgArn] = poller
+	go c.poll(ctx, tgArn, poller)
+}
+
+func (c *targetHealthCache) poll(ctx context.Context, tgArn string, poller *targetHealthPoller) {
+	// the first poll is delayed as well, so that target groups watched at once are polled at different times
+	select {
+	case <-time.After(wait.Jitter(poller.interval, targetHealthPollJitterFactor)):
+	case <-ctx.Done():
+		return
+	}
+	wait.JitterUntil(func() {
+		targetHealthDescriptions, err := c.refresh(ctx, tgArn)
+		if err != nil {
+			if ctx.Err() == nil {
+				albctx.GetLogger(ctx).Errorf("Failed to poll target health of target group %v: %v", tgArn, err)
+			}
+			return
+		}
+		c.mutex.Lock()
+		onRefresh := poller.onRefresh
+		c.mutex.Unlock()
+		if onRefresh != nil && ctx.Err() == nil {
+			onRefresh(targetHealthDescriptions)
+		}
+	}, poller.interval, targetHealthPollJitterFactor, true, ctx.Done())
+}
+
+func (c *targetHealthCache) StopPolling(tgArn string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if poller, ok := c.pollers[tgArn]; ok {
+		poller.cancel()
+		delete(c.pollers, tgArn)
+	}
+	delete(c.entries, tgArn)
+	delete(c.generations, tgArn)
+}
+
+func (c *targetHealthCache) refresh(ctx context.Context, tgArn string) ([]*elbv2.TargetHealthDescription, error) {
+	c.mutex.Lock()
+	generation := c.generations[tgArn]
+	c.mutex.Unlock()
+
+	resp, err := c.cloud.DescribeTargetHealthWithContext(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(tgArn)})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generations[tgArn] || ctx.Err() != nil {
+		return resp.TargetHealthDescriptions, nil
+	}
+	c.entries[tgArn] = &targetHealthCacheEntry{
+		targetHealthDescriptions: resp.TargetHealthDescriptions,
+		refreshTime:              time.Now(),
+	}
+	return resp.TargetHealthDescriptions, nil
+}
+
+// ttl returns how long the cached target health of the target group is fresh, it must be called with mutex held.
+// The target health of polled target groups stays fresh until the poller missed a refresh.
+func (c *targetHealthCache) ttl(tgArn string) time.Duration {
+	if poller, ok := c.pollers[tgArn]; ok && poller.ctx.Err() == nil {
+		return 2 * poller.interval
+	}
+	return targetHealthCacheTTL
+}
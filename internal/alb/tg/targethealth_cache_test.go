@@ -0,0 +1,184 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type countingTargetHealthCacheCollector struct {
+	metric.DummyCollector
+	hits   int
+	misses int
+}
+
+func (c *countingTargetHealthCacheCollector) IncTargetHealthCacheHitCount() {
+	c.hits++
+}
+
+func (c *countingTargetHealthCacheCollector) IncTargetHealthCacheMissCount() {
+	c.misses++
+}
+
+func newTargetHealthOutput(ids ...string) *elbv2.DescribeTargetHealthOutput {
+	output := &elbv2.DescribeTargetHealthOutput{}
+	for _, id := range ids {
+		output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			Target:       &elbv2.TargetDescription{Id: aws.String(id), Port: aws.Int64(8080)},
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumHealthy)},
+		})
+	}
+	return output
+}
+
+func Test_targetHealthCache_Get(t *testing.T) {
+	ctx := context.Background()
+	describeInput := &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String("arn")}
+
+	for _, tc := range []struct {
+		name           string
+		test           func(c TargetHealthCache)
+		describeCalls  int
+		expectedHits   int
+		expectedMisses int
+	}{
+		{
+			name: "target health is described once while fresh",
+			test: func(c TargetHealthCache) {
+				c.Get(ctx, "arn")
+				c.Get(ctx, "arn")
+			},
+			describeCalls:  1,
+			expectedHits:   1,
+			expectedMisses: 1,
+		},
+		{
+			name: "target health is described again once invalidated",
+			test: func(c TargetHealthCache) {
+				c.Get(ctx, "arn")
+				c.Invalidate("arn")
+				c.Get(ctx, "arn")
+			},
+			describeCalls:  2,
+			expectedHits:   0,
+			expectedMisses: 2,
+		},
+		{
+			name: "refreshed target health is described and cached",
+			test: func(c TargetHealthCache) {
+				c.Get(ctx, "arn")
+				c.Refresh(ctx, "arn")
+				c.Get(ctx, "arn")
+			},
+			describeCalls:  2,
+			expectedHits:   1,
+			expectedMisses: 1,
+		},
+		{
+			name: "target health is described again once expired",
+			test: func(c TargetHealthCache) {
+				c.Get(ctx, "arn")
+				c.(*targetHealthCache).entries["arn"].refreshTime = time.Now().Add(-targetHealthCacheTTL)
+				c.Get(ctx, "arn")
+			},
+			describeCalls:  2,
+			expectedHits:   0,
+			expectedMisses: 2,
+		},
+		{
+			name: "target health of polled target groups is fresh for longer",
+			test: func(c TargetHealthCache) {
+				pollCtx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				c.Poll(pollCtx, "arn", time.Hour, nil)
+				c.Get(ctx, "arn")
+				c.(*targetHealthCache).entries["arn"].refreshTime = time.Now().Add(-targetHealthCacheTTL)
+				c.Get(ctx, "arn")
+			},
+			describeCalls:  1,
+			expectedHits:   1,
+			expectedMisses: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := &mocks.CloudAPI{}
+			cloud.On("DescribeTargetHealthWithContext", ctx, describeInput).Return(newTargetHealthOutput("ip1"), nil).Times(tc.describeCalls)
+			collector := &countingTargetHealthCacheCollector{}
+			c := NewTargetHealthCache(cloud, collector)
+
+			tc.test(c)
+			cloud.AssertExpectations(t)
+			assert.Equal(t, tc.expectedHits, collector.hits)
+			assert.Equal(t, tc.expectedMisses, collector.misses)
+		})
+	}
+}
+
+func Test_targetHealthCache_GetError(t *testing.T) {
+	ctx := context.Background()
+	cloud := &mocks.CloudAPI{}
+	cloud.On("DescribeTargetHealthWithContext", ctx, mock.Anything).Return(nil, errors.New("error"))
+	c := NewTargetHealthCache(cloud, metric.DummyCollector{})
+
+	_, err := c.Get(ctx, "arn")
+	assert.EqualError(t, err, "error")
+	assert.NotContains(t, c.(*targetHealthCache).entries, "arn")
+}
+
+func Test_targetHealthCache_InvalidateWhileRefreshing(t *testing.T) {
+	ctx := context.Background()
+	cloud := &mocks.CloudAPI{}
+	c := NewTargetHealthCache(cloud, metric.DummyCollector{})
+	// targets registered while describing the target health aren't part of it
+	cloud.On("DescribeTargetHealthWithContext", ctx, mock.Anything).Run(func(mock.Arguments) {
+		c.Invalidate("arn")
+	}).Return(newTargetHealthOutput("ip1"), nil)
+
+	targetHealthDescriptions, err := c.Get(ctx, "arn")
+	assert.NoError(t, err)
+	assert.Equal(t, newTargetHealthOutput("ip1").TargetHealthDescriptions, targetHealthDescriptions)
+	assert.NotContains(t, c.(*targetHealthCache).entries, "arn")
+}
+
+func Test_targetHealthCache_Poll(t *testing.T) {
+	cloud := &mocks.CloudAPI{}
+	cloud.On("DescribeTargetHealthWithContext", mock.Anything, &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String("arn")}).Return(newTargetHealthOutput("ip1"), nil)
+	c := NewTargetHealthCache(cloud, metric.DummyCollector{}).(*targetHealthCache)
+
+	c.Poll(context.Background(), "arn", 10*time.Millisecond, func([]*elbv2.TargetHealthDescription) {
+		t.Error("replaced onRefresh must not be invoked")
+	})
+	// polling again with the same interval keeps the poller running, invoking the latest onRefresh
+	poller := c.pollers["arn"]
+	refreshed := make(chan []*elbv2.TargetHealthDescription, 1)
+	c.Poll(context.Background(), "arn", 10*time.Millisecond, func(targetHealthDescriptions []*elbv2.TargetHealthDescription) {
+		select {
+		case refreshed <- targetHealthDescriptions:
+		default:
+		}
+	})
+	assert.True(t, poller == c.pollers["arn"])
+
+	select {
+	case targetHealthDescriptions := <-refreshed:
+		assert.Equal(t, newTargetHealthOutput("ip1").TargetHealthDescriptions, targetHealthDescriptions)
+	case <-time.After(5 * time.Second):
+		t.Error("target health wasn't polled")
+	}
+	c.mutex.Lock()
+	assert.Contains(t, c.entries, "arn")
+	c.mutex.Unlock()
+
+	c.StopPolling("arn")
+	assert.Error(t, poller.ctx.Err())
+	assert.NotContains(t, c.pollers, "arn")
+	assert.NotContains(t, c.entries, "arn")
+}
@@ -0,0 +1,71 @@
+package tg
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	api "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// instancePodTargetHealth returns the target health of pods of the ingress backend, approximated from the health of the nodes registered in its target group of target type == instance.
+// With externalTrafficPolicy Local, a pod is as healthy as the node hosting it, since the node only forwards traffic to its local pods.
+// With externalTrafficPolicy Cluster, a pod is healthy once all registered nodes are healthy, since each of them forwards traffic to it.
+// A pod whose node isn't registered yet is approximated the same way, since with Local its node is only registered once it hosts a ready pod.
+func (c *targetHealthController) instancePodTargetHealth(ingress *extensions.Ingress, backend *extensions.IngressBackend, targetHealthDescriptions []*elbv2.TargetHealthDescription) (func(pod *api.Pod) (*elbv2.TargetHealth, bool), error) {
+	serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: backend.ServiceName}
+	service, err := c.store.GetService(serviceKey.String())
+	if err != nil {
+		return nil, err
+	}
+
+	nodesHealth := make(map[string]*elbv2.TargetHealth)
+	for _, desc := range targetHealthDescriptions {
+		// draining nodes are being deregistered, they are no longer forwarding new traffic to the pods
+		if aws.StringValue(desc.TargetHealth.State) == elbv2.TargetHealthStateEnumDraining {
+			continue
+		}
+		nodesHealth[aws.StringValue(desc.Target.Id)] = desc.TargetHealth
+	}
+	registeredNodesHealth := aggregateNodesTargetHealth(nodesHealth)
+	local := service.Spec.ExternalTrafficPolicy == api.ServiceExternalTrafficPolicyTypeLocal
+
+	return func(pod *api.Pod) (*elbv2.TargetHealth, bool) {
+		if local && pod.Spec.NodeName != "" {
+			if node, err := c.store.GetNode(pod.Spec.NodeName); err == nil {
+				if instanceID, err := c.store.GetNodeInstanceID(node); err == nil {
+					if nodeHealth, ok := nodesHealth[instanceID]; ok {
+						return nodeHealth, true
+					}
+				}
+			}
+		}
+		return registeredNodesHealth, true
+	}, nil
+}
+
+// aggregateNodesTargetHealth returns the target health of the first node that isn't healthy, or healthy if all nodes are healthy.
+// Without any registered node, there is nothing to wait for, so it's healthy as well.
+func aggregateNodesTargetHealth(nodesHealth map[string]*elbv2.TargetHealth) *elbv2.TargetHealth {
+	var instanceIDs []string
+	for instanceID := range nodesHealth {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	sort.Strings(instanceIDs)
+
+	for _, instanceID := range instanceIDs {
+		nodeHealth := nodesHealth[instanceID]
+		if aws.StringValue(nodeHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+			continue
+		}
+		return &elbv2.TargetHealth{
+			State:       nodeHealth.State,
+			Reason:      nodeHealth.Reason,
+			Description: aws.String(fmt.Sprintf("node %s: %s", instanceID, aws.StringValue(nodeHealth.Description))),
+		}
+	}
+	return &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumHealthy)}
+}
@@ -0,0 +1,181 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	backendpkg "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	api "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNodeTargetHealthDescription(instanceID string, state string) *elbv2.TargetHealthDescription {
+	return &elbv2.TargetHealthDescription{
+		Target:       &elbv2.TargetDescription{Id: aws.String(instanceID), Port: aws.Int64(30080)},
+		TargetHealth: &elbv2.TargetHealth{State: aws.String(state), Reason: aws.String(state + "-reason"), Description: aws.String(state + "-description")},
+	}
+}
+
+func Test_SyncTargetsForReconciliation_instance(t *testing.T) {
+	backend := &extensions.IngressBackend{ServiceName: "name", ServicePort: intstr.FromInt(123)}
+	ingress := dummy.NewIngress()
+	targets := &Targets{TgArn: "arn:", Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumInstance}
+	desiredTargets := []*elbv2.TargetDescription{{Id: aws.String("i-1"), Port: aws.Int64(30080)}}
+	podTargets := []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)}}
+	pods := podsWithReadinessGateAndStatus(backendpkg.PodReadinessGateConditionType(ingress, backend), noConditionType, api.ConditionUnknown)
+
+	for _, tc := range []struct {
+		name            string
+		enabled         bool
+		expectedTgWatch bool
+	}{
+		{
+			name:            "pods behind instance targets aren't reconciled by default",
+			enabled:         false,
+			expectedTgWatch: false,
+		},
+		{
+			name:            "pods behind instance targets are tracked by their IP targets once enabled",
+			enabled:         true,
+			expectedTgWatch: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointResolver := &mocks.EndpointResolver{}
+			if tc.enabled {
+				endpointResolver.On("Resolve", ingress, backend, elbv2.TargetTypeEnumIp).Return(podTargets, nil)
+				endpointResolver.On("ReverseResolve", ingress, backend, podTargets).Return(pods, nil)
+			}
+			cfg := &config.Configuration{FeatureGate: config.NewFeatureGate()}
+			if tc.enabled {
+				cfg.FeatureGate.Enable(config.InstanceTargetReadinessGate)
+			}
+			store := &store.MockStorer{}
+			store.On("GetConfig").Return(cfg)
+			healthCheckIntervalSeconds := int64(healthcheck.DefaultIntervalSeconds)
+			store.On("GetIngressAnnotations", mock.Anything).Return(nil, nil)
+			store.On("GetServiceAnnotations", mock.Anything, mock.Anything).Return(&annotations.Service{
+				HealthCheck: &healthcheck.Config{IntervalSeconds: &healthCheckIntervalSeconds},
+			}, nil)
+			cloud := &mocks.CloudAPI{}
+			controller := NewTargetHealthController(cloud, store, endpointResolver, testclient.NewFakeClient(), fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+			defer controller.cancel()
+
+			assert.NoError(t, controller.SyncTargetsForReconciliation(context.Background(), targets, desiredTargets))
+			tgWatch, ok := controller.tgWatches["arn:"]
+			assert.Equal(t, tc.expectedTgWatch, ok)
+			if ok {
+				assert.Equal(t, elbv2.TargetTypeEnumInstance, tgWatch.targetType)
+			}
+			endpointResolver.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_instancePodTargetHealth(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: v1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)}
+	pod := &api.Pod{Spec: api.PodSpec{NodeName: "node"}, Status: api.PodStatus{PodIP: "10.0.0.1"}}
+	node := &api.Node{ObjectMeta: v1.ObjectMeta{Name: "node"}}
+	healthy := elbv2.TargetHealthStateEnumHealthy
+	unhealthy := elbv2.TargetHealthStateEnumUnhealthy
+
+	for _, tc := range []struct {
+		name                     string
+		externalTrafficPolicy    api.ServiceExternalTrafficPolicyType
+		podInstanceID            string
+		targetHealthDescriptions []*elbv2.TargetHealthDescription
+		expectedTargetHealth     *elbv2.TargetHealth
+	}{
+		{
+			name:                  "with Cluster, pod is healthy once all registered nodes are healthy",
+			externalTrafficPolicy: api.ServiceExternalTrafficPolicyTypeCluster,
+			podInstanceID:         "i-1",
+			targetHealthDescriptions: []*elbv2.TargetHealthDescription{
+				newNodeTargetHealthDescription("i-1", healthy),
+				newNodeTargetHealthDescription("i-2", healthy),
+				newNodeTargetHealthDescription("i-3", elbv2.TargetHealthStateEnumDraining),
+			},
+			expectedTargetHealth: &elbv2.TargetHealth{State: aws.String(healthy)},
+		},
+		{
+			name:                  "with Cluster, pod isn't healthy while any registered node isn't healthy",
+			externalTrafficPolicy: api.ServiceExternalTrafficPolicyTypeCluster,
+			podInstanceID:         "i-1",
+			targetHealthDescriptions: []*elbv2.TargetHealthDescription{
+				newNodeTargetHealthDescription("i-1", healthy),
+				newNodeTargetHealthDescription("i-2", unhealthy),
+			},
+			expectedTargetHealth: &elbv2.TargetHealth{State: aws.String(unhealthy), Reason: aws.String("unhealthy-reason"), Description: aws.String("node i-2: unhealthy-description")},
+		},
+		{
+			name:                  "with Local, pod is as healthy as its node",
+			externalTrafficPolicy: api.ServiceExternalTrafficPolicyTypeLocal,
+			podInstanceID:         "i-1",
+			targetHealthDescriptions: []*elbv2.TargetHealthDescription{
+				newNodeTargetHealthDescription("i-1", healthy),
+				newNodeTargetHealthDescription("i-2", unhealthy),
+			},
+			expectedTargetHealth: newNodeTargetHealthDescription("i-1", healthy).TargetHealth,
+		},
+		{
+			name:                  "with Local, pod whose node isn't registered is healthy once all registered nodes are healthy",
+			externalTrafficPolicy: api.ServiceExternalTrafficPolicyTypeLocal,
+			podInstanceID:         "i-3",
+			targetHealthDescriptions: []*elbv2.TargetHealthDescription{
+				newNodeTargetHealthDescription("i-1", healthy),
+				newNodeTargetHealthDescription("i-2", elbv2.TargetHealthStateEnumInitial),
+			},
+			expectedTargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumInitial), Reason: aws.String("initial-reason"), Description: aws.String("node i-2: initial-description")},
+		},
+		{
+			name:                     "with Local, pod is healthy without registered nodes",
+			externalTrafficPolicy:    api.ServiceExternalTrafficPolicyTypeLocal,
+			podInstanceID:            "i-1",
+			targetHealthDescriptions: nil,
+			expectedTargetHealth:     &elbv2.TargetHealth{State: aws.String(healthy)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &store.MockStorer{}
+			store.On("GetService", "namespace/service").Return(&api.Service{Spec: api.ServiceSpec{ExternalTrafficPolicy: tc.externalTrafficPolicy}}, nil)
+			store.On("GetNode", "node").Maybe().Return(node, nil)
+			store.On("GetNodeInstanceID", node).Maybe().Return(tc.podInstanceID, nil)
+			cloud := &mocks.CloudAPI{}
+			controller := NewTargetHealthController(cloud, store, &mocks.EndpointResolver{}, testclient.NewFakeClient(), fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+
+			podTargetHealth, err := controller.instancePodTargetHealth(ingress, backend, tc.targetHealthDescriptions)
+			assert.NoError(t, err)
+			targetHealth, ok := podTargetHealth(pod)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedTargetHealth, targetHealth)
+		})
+	}
+}
+
+func Test_instancePodTargetHealth_serviceNotFound(t *testing.T) {
+	store := &store.MockStorer{}
+	store.On("GetService", "namespace/service").Return(nil, errors.New("not found"))
+	cloud := &mocks.CloudAPI{}
+	controller := NewTargetHealthController(cloud, store, &mocks.EndpointResolver{}, testclient.NewFakeClient(), fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+
+	ingress := &extensions.Ingress{ObjectMeta: v1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	_, err := controller.instancePodTargetHealth(ingress, &extensions.IngressBackend{ServiceName: "service"}, nil)
+	assert.EqualError(t, err, "not found")
+}
@@ -0,0 +1,102 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric/collectors"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	api "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// targetHealthReporter reports the target health of target groups as metrics per ingress backend and target group,
+// and records an event on the ingress when a backend loses all of its healthy targets.
+type targetHealthReporter struct {
+	metricCollector metric.Collector
+
+	mutex sync.Mutex
+	// reports are the last target health reported for each target group
+	reports map[string]targetHealthReport
+}
+
+type targetHealthReport struct {
+	backend        collectors.TargetHealthBackend
+	healthyTargets int
+}
+
+func newTargetHealthReporter(metricCollector metric.Collector) *targetHealthReporter {
+	return &targetHealthReporter{
+		metricCollector: metricCollector,
+		reports:         make(map[string]targetHealthReport),
+	}
+}
+
+// report reports the target health of the target group of the ingress backend.
+func (r *targetHealthReporter) report(ctx context.Context, tgArn string, ingress *extensions.Ingress, backend *extensions.IngressBackend, targetHealthDescriptions []*elbv2.TargetHealthDescription) {
+	counts := make(map[collectors.TargetHealthState]int)
+	for _, desc := range targetHealthDescriptions {
+		if desc.TargetHealth == nil {
+			continue
+		}
+		state := collectors.TargetHealthState{
+			State:  aws.StringValue(desc.TargetHealth.State),
+			Reason: aws.StringValue(desc.TargetHealth.Reason),
+		}
+		counts[state]++
+	}
+	report := targetHealthReport{
+		backend: collectors.TargetHealthBackend{
+			Ingress:     k8s.MetaNamespaceKey(ingress),
+			Service:     backend.ServiceName,
+			Port:        backend.ServicePort.String(),
+			TargetGroup: tgArn,
+		},
+		healthyTargets: counts[collectors.TargetHealthState{State: elbv2.TargetHealthStateEnumHealthy}],
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	lastReport, reported := r.reports[tgArn]
+	r.reports[tgArn] = report
+	r.metricCollector.SetTargetHealthTargets(report.backend, counts)
+
+	if reported && lastReport.healthyTargets > 0 && report.healthyTargets == 0 {
+		albctx.GetLogger(ctx).Warnf("backend %v:%v has no healthy targets in target group %v: %v", report.backend.Service, report.backend.Port, tgArn, targetHealthCountsString(counts))
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "UNHEALTHY", "backend %s:%s has no healthy targets in target group %s: %s", report.backend.Service, report.backend.Port, tgArn, targetHealthCountsString(counts))
+	}
+}
+
+// forget stops reporting the target health of the target group.
+func (r *targetHealthReporter) forget(tgArn string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if report, ok := r.reports[tgArn]; ok {
+		r.metricCollector.RemoveTargetHealthTargets(report.backend)
+		delete(r.reports, tgArn)
+	}
+}
+
+// targetHealthCountsString describes the target health counts, e.g. `2 unhealthy (Target.FailedHealthChecks)`
+func targetHealthCountsString(counts map[collectors.TargetHealthState]int) string {
+	if len(counts) == 0 {
+		return "no targets"
+	}
+	var s []string
+	for state, count := range counts {
+		if state.Reason == "" {
+			s = append(s, fmt.Sprintf("%v %v", count, state.State))
+		} else {
+			s = append(s, fmt.Sprintf("%v %v (%v)", count, state.State, state.Reason))
+		}
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,172 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric/collectors"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type pollingTargetHealthCache struct {
+	TargetHealthCache
+	intervals map[string]time.Duration
+	onRefresh map[string]func(targetHealthDescriptions []*elbv2.TargetHealthDescription)
+}
+
+func (c *pollingTargetHealthCache) Poll(ctx context.Context, tgArn string, interval time.Duration, onRefresh func(targetHealthDescriptions []*elbv2.TargetHealthDescription)) {
+	c.intervals[tgArn] = interval
+	c.onRefresh[tgArn] = onRefresh
+}
+
+type recordingTargetHealthCollector struct {
+	metric.DummyCollector
+	targets map[collectors.TargetHealthBackend]map[collectors.TargetHealthState]int
+}
+
+func (c *recordingTargetHealthCollector) SetTargetHealthTargets(backend collectors.TargetHealthBackend, counts map[collectors.TargetHealthState]int) {
+	c.targets[backend] = counts
+}
+
+func (c *recordingTargetHealthCollector) RemoveTargetHealthTargets(backend collectors.TargetHealthBackend) {
+	delete(c.targets, backend)
+}
+
+func newTargetHealthDescription(id string, state string, reason string) *elbv2.TargetHealthDescription {
+	targetHealth := &elbv2.TargetHealth{State: aws.String(state)}
+	if reason != "" {
+		targetHealth.Reason = aws.String(reason)
+	}
+	return &elbv2.TargetHealthDescription{
+		Target:       &elbv2.TargetDescription{Id: aws.String(id), Port: aws.Int64(8080)},
+		TargetHealth: targetHealth,
+	}
+}
+
+func Test_targetHealthReporter(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromString("http")}
+	expectedBackend := collectors.TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "http", TargetGroup: "arn"}
+	healthy := newTargetHealthDescription("ip1", elbv2.TargetHealthStateEnumHealthy, "")
+	unhealthy := newTargetHealthDescription("ip2", elbv2.TargetHealthStateEnumUnhealthy, elbv2.TargetHealthReasonEnumTargetFailedHealthChecks)
+	initial := newTargetHealthDescription("ip3", elbv2.TargetHealthStateEnumInitial, elbv2.TargetHealthReasonEnumElbRegistrationInProgress)
+
+	for _, tc := range []struct {
+		name           string
+		reports        [][]*elbv2.TargetHealthDescription
+		expectedCounts map[collectors.TargetHealthState]int
+		expectedEvents []string
+	}{
+		{
+			name:    "targets are counted by state and reason",
+			reports: [][]*elbv2.TargetHealthDescription{{healthy, unhealthy, initial}},
+			expectedCounts: map[collectors.TargetHealthState]int{
+				{State: elbv2.TargetHealthStateEnumHealthy}:                                                                 1,
+				{State: elbv2.TargetHealthStateEnumUnhealthy, Reason: elbv2.TargetHealthReasonEnumTargetFailedHealthChecks}: 1,
+				{State: elbv2.TargetHealthStateEnumInitial, Reason: elbv2.TargetHealthReasonEnumElbRegistrationInProgress}:  1,
+			},
+		},
+		{
+			name:    "backend without healthy targets from the start records no event",
+			reports: [][]*elbv2.TargetHealthDescription{{initial}, {unhealthy}},
+			expectedCounts: map[collectors.TargetHealthState]int{
+				{State: elbv2.TargetHealthStateEnumUnhealthy, Reason: elbv2.TargetHealthReasonEnumTargetFailedHealthChecks}: 1,
+			},
+		},
+		{
+			name:    "backend losing all of its healthy targets records an event once",
+			reports: [][]*elbv2.TargetHealthDescription{{healthy, initial}, {unhealthy, initial}, {unhealthy}},
+			expectedCounts: map[collectors.TargetHealthState]int{
+				{State: elbv2.TargetHealthStateEnumUnhealthy, Reason: elbv2.TargetHealthReasonEnumTargetFailedHealthChecks}: 1,
+			},
+			expectedEvents: []string{
+				"Warning UNHEALTHY backend service:http has no healthy targets in target group arn: 1 initial (Elb.RegistrationInProgress), 1 unhealthy (Target.FailedHealthChecks)",
+			},
+		},
+		{
+			name:           "backend losing all of its targets records an event",
+			reports:        [][]*elbv2.TargetHealthDescription{{healthy}, {}},
+			expectedCounts: map[collectors.TargetHealthState]int{},
+			expectedEvents: []string{
+				"Warning UNHEALTHY backend service:http has no healthy targets in target group arn: no targets",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			ctx := albctx.SetEventf(context.Background(), func(eventType string, reason string, messageFmt string, args ...interface{}) {
+				events = append(events, fmt.Sprintf("%v %v %v", eventType, reason, fmt.Sprintf(messageFmt, args...)))
+			})
+			collector := &recordingTargetHealthCollector{targets: make(map[collectors.TargetHealthBackend]map[collectors.TargetHealthState]int)}
+			r := newTargetHealthReporter(collector)
+
+			for _, report := range tc.reports {
+				r.report(ctx, "arn", ingress, backend, report)
+			}
+			assert.Equal(t, tc.expectedCounts, collector.targets[expectedBackend])
+			assert.Equal(t, tc.expectedEvents, events)
+
+			r.forget("arn")
+			assert.NotContains(t, collector.targets, expectedBackend)
+			assert.NotContains(t, r.reports, "arn")
+		})
+	}
+}
+
+func Test_targetHealthReporter_replacedTargetGroup(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromString("http")}
+	oldBackend := collectors.TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "http", TargetGroup: "old"}
+	newBackend := collectors.TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "http", TargetGroup: "new"}
+	collector := &recordingTargetHealthCollector{targets: make(map[collectors.TargetHealthBackend]map[collectors.TargetHealthState]int)}
+	r := newTargetHealthReporter(collector)
+
+	r.report(context.Background(), "old", ingress, backend, []*elbv2.TargetHealthDescription{newTargetHealthDescription("ip1", elbv2.TargetHealthStateEnumHealthy, "")})
+	r.report(context.Background(), "new", ingress, backend, []*elbv2.TargetHealthDescription{newTargetHealthDescription("ip1", elbv2.TargetHealthStateEnumInitial, elbv2.TargetHealthReasonEnumElbRegistrationInProgress)})
+	assert.Equal(t, map[collectors.TargetHealthState]int{{State: elbv2.TargetHealthStateEnumHealthy}: 1}, collector.targets[oldBackend])
+
+	r.forget("old")
+	assert.NotContains(t, collector.targets, oldBackend)
+	assert.Equal(t, map[collectors.TargetHealthState]int{
+		{State: elbv2.TargetHealthStateEnumInitial, Reason: elbv2.TargetHealthReasonEnumElbRegistrationInProgress}: 1,
+	}, collector.targets[newBackend])
+}
+
+func Test_ReportTargetHealth(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend := &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromString("http")}
+	expectedBackend := collectors.TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "http", TargetGroup: "arn"}
+	collector := &recordingTargetHealthCollector{targets: make(map[collectors.TargetHealthBackend]map[collectors.TargetHealthState]int)}
+	cache := &pollingTargetHealthCache{
+		intervals: make(map[string]time.Duration),
+		onRefresh: make(map[string]func(targetHealthDescriptions []*elbv2.TargetHealthDescription)),
+	}
+	store := store.NewDummy()
+	c := NewTargetHealthController(nil, store, nil, nil, nil, cache, collector).(*targetHealthController)
+	defer c.cancel()
+
+	c.ReportTargetHealth(context.Background(), &Targets{TgArn: "arn", Ingress: ingress, Backend: backend}, []*elbv2.TargetHealthDescription{
+		newTargetHealthDescription("ip1", elbv2.TargetHealthStateEnumHealthy, ""),
+	})
+	assert.Equal(t, map[collectors.TargetHealthState]int{{State: elbv2.TargetHealthStateEnumHealthy}: 1}, collector.targets[expectedBackend])
+
+	// the target group is polled at its health check interval, reporting its target health between reconciles
+	assert.Equal(t, time.Duration(healthcheck.DefaultIntervalSeconds)*time.Second, cache.intervals["arn"])
+	cache.onRefresh["arn"]([]*elbv2.TargetHealthDescription{
+		newTargetHealthDescription("ip1", elbv2.TargetHealthStateEnumUnhealthy, elbv2.TargetHealthReasonEnumTargetFailedHealthChecks),
+	})
+	assert.Equal(t, map[collectors.TargetHealthState]int{
+		{State: elbv2.TargetHealthStateEnumUnhealthy, Reason: elbv2.TargetHealthReasonEnumTargetFailedHealthChecks}: 1,
+	}, collector.targets[expectedBackend])
+}
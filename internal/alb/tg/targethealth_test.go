@@ -3,7 +3,9 @@ package tg
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -14,15 +16,19 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
 	backendpkg "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	api "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	fakekube "k8s.io/client-go/kubernetes/fake"
 	realclient "sigs.k8s.io/controller-runtime/pkg/client"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -72,7 +78,7 @@ func Test_SyncTargetsForReconciliation(t *testing.T) {
 	backend := &extensions.IngressBackend{ServiceName: serviceName, ServicePort: servicePort}
 	ingress := dummy.NewIngress()
 
-	targets := &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumInstance}
+	targets := &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp}
 	desiredTargets := []*elbv2.TargetDescription{
 		{
 			Id: aws.String("10.0.0.1"),
@@ -123,20 +129,12 @@ func Test_SyncTargetsForReconciliation(t *testing.T) {
 			}, nil)
 			client := testclient.NewFakeClient()
 
-			controller := NewTargetHealthController(cloud, store, endpointResolver, client).(*targetHealthController)
+			controller := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
 
 			if tc.ExistingTgWatch {
-				tgWatch, newCtx := newTargetGroupWatch(ctx, ingress, backend)
+				tgWatch, newCtx := newTargetGroupWatch(ctx, ingress, backend, elbv2.TargetTypeEnumIp)
 				ctx = newCtx
 				controller.tgWatches[tgArn] = tgWatch
-				go func(tgWatch *targetGroupWatch) {
-					for {
-						select {
-						case <-tgWatch.interval:
-						case <-tgWatch.targetsToReconcile:
-						}
-					}
-				}(tgWatch)
 			}
 
 			err := controller.SyncTargetsForReconciliation(ctx, targets, tc.DesiredTargets)
@@ -156,6 +154,78 @@ func Test_SyncTargetsForReconciliation(t *testing.T) {
 	}
 }
 
+func Test_SyncTargetsForReconciliation_concurrentReconciles(t *testing.T) {
+	serviceName := "name"
+	backend := &extensions.IngressBackend{ServiceName: serviceName, ServicePort: intstr.FromInt(123)}
+	ingress := dummy.NewIngress()
+	desiredTargets := []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}}
+	pods := podsWithReadinessGateAndStatus(backendpkg.PodReadinessGateConditionType(ingress, backend), noConditionType, api.ConditionUnknown)
+
+	endpointResolver := &mocks.EndpointResolver{}
+	endpointResolver.On("ReverseResolve", ingress, backend, desiredTargets).Return(pods, nil)
+	cloud := &mocks.CloudAPI{}
+	store := &store.MockStorer{}
+	healthCheckIntervalSeconds := int64(healthcheck.DefaultIntervalSeconds)
+	store.On("GetIngressAnnotations", mock.Anything).Return(nil, nil)
+	store.On("GetServiceAnnotations", mock.Anything, mock.Anything).Return(&annotations.Service{
+		HealthCheck: &healthcheck.Config{IntervalSeconds: &healthCheckIntervalSeconds},
+	}, nil)
+	controller := NewTargetHealthController(cloud, store, endpointResolver, testclient.NewFakeClient(), fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+
+	// reconciles of the same target groups racing with each other and with target group deletions must neither block nor race
+	const tgCount = 5
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tgArn := fmt.Sprintf("arn%d", i%tgCount)
+			if i%10 == 9 {
+				controller.StopReconcilingPodConditionStatus(tgArn)
+				return
+			}
+			targets := &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp}
+			assert.NoError(t, controller.SyncTargetsForReconciliation(context.Background(), targets, desiredTargets))
+		}(i)
+	}
+	wg.Wait()
+
+	controller.mutex.Lock()
+	tgWatches := make([]*targetGroupWatch, 0, len(controller.tgWatches))
+	for _, tgWatch := range controller.tgWatches {
+		tgWatches = append(tgWatches, tgWatch)
+	}
+	controller.mutex.Unlock()
+	assert.True(t, len(tgWatches) <= tgCount)
+
+	// all watches are stopped with the manager
+	stop := make(chan struct{})
+	close(stop)
+	assert.NoError(t, controller.Start(stop))
+	assert.Error(t, controller.ctx.Err())
+	assert.Empty(t, controller.tgWatches)
+
+	// reconciles after the manager stopped don't start watches anymore
+	targets := &Targets{TgArn: "arn0", Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp}
+	assert.NoError(t, controller.SyncTargetsForReconciliation(context.Background(), targets, desiredTargets))
+	assert.Empty(t, controller.tgWatches)
+}
+
+func Test_targetGroupWatch_update(t *testing.T) {
+	tgWatch, _ := newTargetGroupWatch(context.Background(), &extensions.Ingress{}, &extensions.IngressBackend{}, elbv2.TargetTypeEnumIp)
+
+	// updates don't block while the go routine is busy, only the latest one is received
+	for interval := int64(1); interval <= 3; interval++ {
+		tgWatch.update(targetGroupWatchUpdate{interval: interval})
+	}
+	assert.Equal(t, targetGroupWatchUpdate{interval: 3}, <-tgWatch.updates)
+	select {
+	case update := <-tgWatch.updates:
+		t.Errorf("unexpected update %v", update)
+	default:
+	}
+}
+
 func Test_RemovePodConditions(t *testing.T) {
 	tgArn := "arn:"
 	serviceName := "name"
@@ -180,9 +250,13 @@ func Test_RemovePodConditions(t *testing.T) {
 	)
 	podsWithMultipleConditions := podsWithReadinessGateAndStatus(conditionType, conditionType, api.ConditionTrue)
 	podsWithMultipleConditions[0].Status.Conditions = append(podsWithMultipleConditions[0].Status.Conditions, podsWithForeignCondition[0].Status.Conditions[0])
+	deletionTimestamp := v1.Now()
+	terminatingPodsWithCondition := podsWithReadinessGateAndStatus(conditionType, conditionType, api.ConditionTrue)
+	terminatingPodsWithCondition[0].DeletionTimestamp = &deletionTimestamp
 
 	for _, tc := range []struct {
 		Name           string
+		FeatureEnabled bool
 		Targets        *Targets
 		RemovedTargets []*elbv2.TargetDescription
 		PodsBefore     []*api.Pod
@@ -217,13 +291,26 @@ func Test_RemovePodConditions(t *testing.T) {
 			PodsBefore:     podsWithMultipleConditions,
 			PodsAfter:      podsWithForeignCondition,
 		},
+		{
+			Name:           "Terminating pod keeps the condition with pod-deregistration-signal",
+			FeatureEnabled: true,
+			Targets:        &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp},
+			RemovedTargets: desiredTargets,
+			PodsBefore:     terminatingPodsWithCondition,
+			PodsAfter:      terminatingPodsWithCondition,
+		},
 	} {
 		t.Run(tc.Name, func(t *testing.T) {
 			ctx := context.Background()
 			endpointResolver := &mocks.EndpointResolver{}
 			endpointResolver.On("ReverseResolve", ingress, backend, tc.RemovedTargets).Return(deepCopyPods(tc.PodsBefore), nil)
 
+			cfg := &config.Configuration{FeatureGate: config.NewFeatureGate()}
+			if tc.FeatureEnabled {
+				cfg.FeatureGate.Enable(config.PodDeregistrationSignal)
+			}
 			store := &store.MockStorer{}
+			store.On("GetConfig").Maybe().Return(cfg)
 			cloud := &mocks.CloudAPI{}
 
 			client := testclient.NewFakeClient()
@@ -231,7 +318,7 @@ func Test_RemovePodConditions(t *testing.T) {
 				assert.NoError(t, client.Create(ctx, actualPod))
 			}
 
-			controller := NewTargetHealthController(cloud, store, endpointResolver, client).(*targetHealthController)
+			controller := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
 
 			err := controller.RemovePodConditions(ctx, tc.Targets, tc.RemovedTargets)
 
@@ -254,6 +341,181 @@ func Test_RemovePodConditions(t *testing.T) {
 	}
 }
 
+func Test_SignalPodDeregistration(t *testing.T) {
+	tgArn := "arn:"
+	backend := &extensions.IngressBackend{ServiceName: "name", ServicePort: intstr.FromInt(123)}
+	ingress := dummy.NewIngress()
+	deletionTimestamp := v1.Now()
+	laterDeadline := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	conditionType := backendpkg.PodReadinessGateConditionType(ingress, backend)
+
+	newPod := func(name string, ip string, deletionTimestamp *v1.Time, annos map[string]string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace:         ingress.Namespace,
+				Name:              name,
+				DeletionTimestamp: deletionTimestamp,
+				Annotations:       annos,
+			},
+			Status: api.PodStatus{PodIP: ip},
+		}
+	}
+	newPodWithCondition := func(name string, ip string, deletionTimestamp *v1.Time) *api.Pod {
+		pod := newPod(name, ip, deletionTimestamp, nil)
+		pod.Spec.ReadinessGates = []api.PodReadinessGate{{ConditionType: conditionType}}
+		pod.Status.Conditions = []api.PodCondition{{Type: conditionType, Status: api.ConditionTrue}}
+		return pod
+	}
+
+	for _, tc := range []struct {
+		Name                    string
+		FeatureEnabled          bool
+		RemovedTargets          []*elbv2.TargetDescription
+		DrainingTargets         []*elbv2.TargetDescription
+		PodsBefore              []*api.Pod
+		ExpectedDelaySeconds    map[string]string
+		ExpectedDeadlineAfter   map[string]time.Duration
+		ExpectedConditionReason map[string]string
+	}{
+		{
+			Name:           "Feature disabled leaves pods untouched",
+			RemovedTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}},
+			PodsBefore:     []*api.Pod{newPod("terminating", "10.0.0.1", &deletionTimestamp, nil)},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating": "",
+			},
+		},
+		{
+			Name:           "Only terminating pods of removed targets are annotated",
+			FeatureEnabled: true,
+			RemovedTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}, {Id: aws.String("10.0.0.2")}},
+			PodsBefore: []*api.Pod{
+				newPod("terminating", "10.0.0.1", &deletionTimestamp, nil),
+				newPod("running", "10.0.0.2", nil, nil),
+				newPod("terminating-unrelated", "10.0.0.3", &deletionTimestamp, nil),
+			},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating":           "30",
+				"running":               "",
+				"terminating-unrelated": "",
+			},
+			ExpectedDeadlineAfter: map[string]time.Duration{
+				"terminating": 29 * time.Second,
+			},
+		},
+		{
+			Name:           "Later deadline of another target group is kept",
+			FeatureEnabled: true,
+			RemovedTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}},
+			PodsBefore: []*api.Pod{
+				newPod("terminating", "10.0.0.1", &deletionTimestamp, map[string]string{
+					PodDeregistrationDelayAnnotation:    "3600",
+					PodDeregistrationDeadlineAnnotation: laterDeadline,
+				}),
+			},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating": "3600",
+			},
+			ExpectedDeadlineAfter: map[string]time.Duration{
+				"terminating": 59 * time.Minute,
+			},
+		},
+		{
+			Name:            "Draining target whose pod wasn't signaled yet is annotated",
+			FeatureEnabled:  true,
+			DrainingTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}},
+			PodsBefore:      []*api.Pod{newPod("terminating", "10.0.0.1", &deletionTimestamp, nil)},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating": "30",
+			},
+			ExpectedDeadlineAfter: map[string]time.Duration{
+				"terminating": 29 * time.Second,
+			},
+		},
+		{
+			Name:            "Draining target whose pod was signaled keeps its deadline",
+			FeatureEnabled:  true,
+			DrainingTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}},
+			PodsBefore: []*api.Pod{
+				newPod("terminating", "10.0.0.1", &deletionTimestamp, map[string]string{
+					PodDeregistrationDelayAnnotation:    "10",
+					PodDeregistrationDeadlineAnnotation: time.Now().Add(5 * time.Second).UTC().Format(time.RFC3339),
+				}),
+			},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating": "10",
+			},
+		},
+		{
+			Name:           "Condition of terminating pod shows the target draining",
+			FeatureEnabled: true,
+			RemovedTargets: []*elbv2.TargetDescription{{Id: aws.String("10.0.0.1")}},
+			PodsBefore: []*api.Pod{
+				newPodWithCondition("terminating", "10.0.0.1", &deletionTimestamp),
+				newPodWithCondition("running", "10.0.0.2", nil),
+			},
+			ExpectedDelaySeconds: map[string]string{
+				"terminating": "30",
+				"running":     "",
+			},
+			ExpectedConditionReason: map[string]string{
+				"terminating": elbv2.TargetHealthReasonEnumTargetDeregistrationInProgress,
+				"running":     "",
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := &config.Configuration{FeatureGate: config.NewFeatureGate()}
+			if tc.FeatureEnabled {
+				cfg.FeatureGate.Enable(config.PodDeregistrationSignal)
+			}
+			store := store.NewDummy()
+			store.SetConfig(cfg)
+			store.GetServiceAnnotationsResponse.TargetGroup.Attributes = []*elbv2.TargetGroupAttribute{
+				{Key: aws.String(DeregistrationDelayTimeoutSecondsKey), Value: aws.String("30")},
+			}
+
+			client := testclient.NewFakeClient()
+			kubeClient := fakekube.NewSimpleClientset()
+			for _, pod := range tc.PodsBefore {
+				assert.NoError(t, client.Create(ctx, pod.DeepCopy()))
+				_, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(pod.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			controller := NewTargetHealthController(&mocks.CloudAPI{}, store, &mocks.EndpointResolver{}, client, kubeClient.CoreV1(), NewTargetHealthCache(&mocks.CloudAPI{}, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+			err := controller.SignalPodDeregistration(ctx, &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp}, tc.RemovedTargets, tc.DrainingTargets)
+			assert.NoError(t, err)
+
+			// annotations are patched through the pod client
+			for name, expectedDelaySeconds := range tc.ExpectedDelaySeconds {
+				actualPod, err := kubeClient.CoreV1().Pods(ingress.Namespace).Get(name, v1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, expectedDelaySeconds, actualPod.Annotations[PodDeregistrationDelayAnnotation])
+				if expectedDeadlineAfter, ok := tc.ExpectedDeadlineAfter[name]; ok {
+					deadline, err := time.Parse(time.RFC3339, actualPod.Annotations[PodDeregistrationDeadlineAnnotation])
+					assert.NoError(t, err)
+					assert.True(t, deadline.After(time.Now().Add(expectedDeadlineAfter)))
+				}
+			}
+			for name, expectedReason := range tc.ExpectedConditionReason {
+				var actualPod api.Pod
+				assert.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: ingress.Namespace, Name: name}, &actualPod))
+				_, cond := backendpkg.PodConditionForReadinessGate(&actualPod, conditionType)
+				if assert.NotNil(t, cond) {
+					assert.Equal(t, expectedReason, cond.Reason)
+					if expectedReason != "" {
+						assert.Equal(t, api.ConditionFalse, cond.Status)
+						assert.Contains(t, cond.Message, "Target is draining until")
+					}
+				}
+			}
+		})
+	}
+}
+
 func Test_reconcilePodConditionsLoop(t *testing.T) {
 	ctx := context.Background()
 	endpointResolver := &mocks.EndpointResolver{}
@@ -262,9 +524,9 @@ func Test_reconcilePodConditionsLoop(t *testing.T) {
 	store.On("GetIngressAnnotations", mock.Anything).Return(nil, fmt.Errorf("some error that should not propagate"))
 	client := testclient.NewFakeClient()
 
-	controller := NewTargetHealthController(cloud, store, endpointResolver, client).(*targetHealthController)
+	controller := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
 
-	tgWatch, ctx := newTargetGroupWatch(ctx, &extensions.Ingress{}, &extensions.IngressBackend{})
+	tgWatch, ctx := newTargetGroupWatch(ctx, &extensions.Ingress{}, &extensions.IngressBackend{}, elbv2.TargetTypeEnumIp)
 	go func() {
 		tgWatch.cancel()
 	}()
@@ -315,20 +577,6 @@ func Test_reconcilePodConditions(t *testing.T) {
 			describeTargetHealthWithContextCall: &describeTargetHealthWithContextCall{
 				input: &elbv2.DescribeTargetHealthInput{
 					TargetGroupArn: aws.String("tgArn1"),
-					Targets: []*elbv2.TargetDescription{
-						{
-							Id:   aws.String("ip1"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip2"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip3"),
-							Port: aws.Int64(8080),
-						},
-					},
 				},
 				output: &elbv2.DescribeTargetHealthOutput{
 					TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
@@ -525,20 +773,6 @@ func Test_reconcilePodConditions(t *testing.T) {
 			describeTargetHealthWithContextCall: &describeTargetHealthWithContextCall{
 				input: &elbv2.DescribeTargetHealthInput{
 					TargetGroupArn: aws.String("tgArn1"),
-					Targets: []*elbv2.TargetDescription{
-						{
-							Id:   aws.String("ip1"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip2"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip3"),
-							Port: aws.Int64(8080),
-						},
-					},
 				},
 				output: &elbv2.DescribeTargetHealthOutput{
 					TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
@@ -740,20 +974,6 @@ func Test_reconcilePodConditions(t *testing.T) {
 			describeTargetHealthWithContextCall: &describeTargetHealthWithContextCall{
 				input: &elbv2.DescribeTargetHealthInput{
 					TargetGroupArn: aws.String("tgArn1"),
-					Targets: []*elbv2.TargetDescription{
-						{
-							Id:   aws.String("ip1"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip2"),
-							Port: aws.Int64(8080),
-						},
-						{
-							Id:   aws.String("ip3"),
-							Port: aws.Int64(8080),
-						},
-					},
 				},
 				output: &elbv2.DescribeTargetHealthOutput{
 					TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
@@ -905,8 +1125,8 @@ func Test_reconcilePodConditions(t *testing.T) {
 			}
 		}
 
-		controller := NewTargetHealthController(cloud, store, endpointResolver, client).(*targetHealthController)
-		notReadyTargets, err := controller.reconcilePodConditions(context.Background(), tc.tgARN, tc.ingress, tc.backend, tc.targetsToReconcile, conditionType)
+		controller := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
+		notReadyTargets, err := controller.reconcilePodConditions(context.Background(), tc.tgARN, tc.ingress, tc.backend, elbv2.TargetTypeEnumIp, tc.targetsToReconcile, conditionType)
 		if tc.expectedError != nil {
 			assert.EqualError(t, err, tc.expectedError.Error())
 		} else {
@@ -1081,7 +1301,7 @@ func Test_filterTargetsNeedingReconciliation(t *testing.T) {
 			cloud := &mocks.CloudAPI{}
 			client := testclient.NewFakeClient()
 
-			controller := NewTargetHealthController(cloud, store, endpointResolver, client).(*targetHealthController)
+			controller := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), NewTargetHealthCache(cloud, metric.DummyCollector{}), metric.DummyCollector{}).(*targetHealthController)
 			filteredTargets, err := controller.filterTargetsNeedingReconciliation(targets, tc.DesiredTargets, conditionType)
 
 			if tc.ExpectedError != nil {
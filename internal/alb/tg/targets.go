@@ -49,18 +49,20 @@ type TargetsController interface {
 }
 
 // NewTargetsController constructs a new target group targets controller
-func NewTargetsController(cloud aws.CloudAPI, endpointResolver backend.EndpointResolver, healthController TargetHealthController) TargetsController {
+func NewTargetsController(cloud aws.CloudAPI, endpointResolver backend.EndpointResolver, healthController TargetHealthController, targetHealthCache TargetHealthCache) TargetsController {
 	return &targetsController{
-		cloud:            cloud,
-		endpointResolver: endpointResolver,
-		healthController: healthController,
+		cloud:             cloud,
+		endpointResolver:  endpointResolver,
+		healthController:  healthController,
+		targetHealthCache: targetHealthCache,
 	}
 }
 
 type targetsController struct {
-	cloud            aws.CloudAPI
-	endpointResolver backend.EndpointResolver
-	healthController TargetHealthController
+	cloud             aws.CloudAPI
+	endpointResolver  backend.EndpointResolver
+	healthController  TargetHealthController
+	targetHealthCache TargetHealthCache
 }
 
 func (c *targetsController) Reconcile(ctx context.Context, t *Targets) error {
@@ -74,18 +76,16 @@ func (c *targetsController) Reconcile(ctx context.Context, t *Targets) error {
 			return err
 		}
 	}
-	current, err := c.getCurrentTargets(ctx, t.TgArn)
+	current, draining, err := c.getCurrentTargets(ctx, t)
 	if err != nil {
 		return err
 	}
-	if t.TargetType == elbv2.TargetTypeEnumIp {
-		// pods conditions reconciling is only implemented for target type == IP;
-		// with target type == node, a 1:1 mapping between ALB target and pod is only possible if hostPort is used, which is discouraged
-		if err := c.healthController.SyncTargetsForReconciliation(ctx, t, desired); err != nil {
-			albctx.GetLogger(ctx).Errorf("Error syncing targets in target group %v for pod condition status reconciliation: %v", t.TgArn, err.Error())
-			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "Error syncing targets in target group %s for pod condition status reconciliation: %s", t.TgArn, err.Error())
-			return err
-		}
+	// with target type == instance, there is no 1:1 mapping between ALB target and pod,
+	// so pod conditions are only approximated from the health of the nodes if enabled
+	if err := c.healthController.SyncTargetsForReconciliation(ctx, t, desired); err != nil {
+		albctx.GetLogger(ctx).Errorf("Error syncing targets in target group %v for pod condition status reconciliation: %v", t.TgArn, err.Error())
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "Error syncing targets in target group %s for pod condition status reconciliation: %s", t.TgArn, err.Error())
+		return err
 	}
 
 	additions, removals := targetChangeSets(current, desired)
@@ -96,7 +96,9 @@ func (c *targetsController) Reconcile(ctx context.Context, t *Targets) error {
 			Targets:        additions,
 		}
 
-		if _, err := c.cloud.RegisterTargetsWithContext(ctx, in); err != nil {
+		_, err := c.cloud.RegisterTargetsWithContext(ctx, in)
+		c.targetHealthCache.Invalidate(t.TgArn)
+		if err != nil {
 			albctx.GetLogger(ctx).Errorf("Error adding targets to %v: %v", t.TgArn, err.Error())
 			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "Error adding targets to target group %s: %s", t.TgArn, err.Error())
 			return err
@@ -117,13 +119,23 @@ func (c *targetsController) Reconcile(ctx context.Context, t *Targets) error {
 			Targets:        removals,
 		}
 
-		if _, err := c.cloud.DeregisterTargetsWithContext(ctx, in); err != nil {
+		_, err := c.cloud.DeregisterTargetsWithContext(ctx, in)
+		c.targetHealthCache.Invalidate(t.TgArn)
+		if err != nil {
 			albctx.GetLogger(ctx).Errorf("Error removing targets from %v: %v", t.TgArn, err.Error())
 			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "Error removing targets from target group %s: %s", t.TgArn, err.Error())
 			return err
 		}
 		// TODO add Delete events ?
 	}
+	// draining targets are signaled on every reconcile, so that pods whose signal failed are signaled on retry
+	if t.TargetType == elbv2.TargetTypeEnumIp {
+		if err := c.healthController.SignalPodDeregistration(ctx, t, removals, draining); err != nil {
+			albctx.GetLogger(ctx).Errorf("Error signaling deregistration of targets from %v to pods: %v", t.TgArn, err.Error())
+			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "Error signaling deregistration of targets from target group %s to pods: %s", t.TgArn, err.Error())
+			return err
+		}
+	}
 	t.Targets = desired
 	return nil
 }
@@ -132,21 +144,24 @@ func (c *targetsController) StopReconcilingPodConditionStatus(tgArn string) {
 	c.healthController.StopReconcilingPodConditionStatus(tgArn)
 }
 
-func (c *targetsController) getCurrentTargets(ctx context.Context, TgArn string) ([]*elbv2.TargetDescription, error) {
-	opts := &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(TgArn)}
-	resp, err := c.cloud.DescribeTargetHealthWithContext(ctx, opts)
+// getCurrentTargets returns the registered targets that aren't draining, and the draining ones. The target health is read from the cache,
+// which is invalidated whenever targets are registered or deregistered, so that the diff doesn't miss targets changed by the previous reconcile.
+func (c *targetsController) getCurrentTargets(ctx context.Context, t *Targets) ([]*elbv2.TargetDescription, []*elbv2.TargetDescription, error) {
+	targetHealthDescriptions, err := c.targetHealthCache.Get(ctx, t.TgArn)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	c.healthController.ReportTargetHealth(ctx, t, targetHealthDescriptions)
 
-	var current []*elbv2.TargetDescription
-	for _, thd := range resp.TargetHealthDescriptions {
+	var current, draining []*elbv2.TargetDescription
+	for _, thd := range targetHealthDescriptions {
 		if aws.StringValue(thd.TargetHealth.State) == elbv2.TargetHealthStateEnumDraining {
+			draining = append(draining, thd.Target)
 			continue
 		}
 		current = append(current, thd.Target)
 	}
-	return current, nil
+	return current, draining, nil
 }
 
 func (c *targetsController) populateTargetAZ(ctx context.Context, a []*elbv2.TargetDescription) error {
@@ -0,0 +1,178 @@
+package tg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// TargetsQueue reconciles the targets of target groups on a work queue keyed by target group ARN,
+// so that endpoints and pods changes only register and deregister targets, instead of reconciling the whole ingress.
+// A target group is tracked once its targets are reconciled through the queue by an ingress reconcile,
+// until StopReconcilingPodConditionStatus is called when it's deleted.
+type TargetsQueue interface {
+	TargetsController
+	manager.Runnable
+
+	// EnqueueTargetGroups enqueues the tracked target groups of ingress backed by serviceName.
+	// It returns false if none is tracked, in which case the ingress should be reconciled instead.
+	EnqueueTargetGroups(ingressKey types.NamespacedName, serviceName string) bool
+}
+
+// NewTargetsQueue constructs a new TargetsQueue reconciling targets with targetsController
+func NewTargetsQueue(targetsController TargetsController, recorder record.EventRecorder, maxConcurrentReconciles int) TargetsQueue {
+	return &targetsQueue{
+		targetsController:       targetsController,
+		recorder:                recorder,
+		maxConcurrentReconciles: maxConcurrentReconciles,
+		queue:                   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "alb-targets"),
+		trackedTargets:          make(map[string]*Targets),
+		tgLocks:                 make(map[string]*targetGroupLock),
+	}
+}
+
+var _ TargetsQueue = (*targetsQueue)(nil)
+
+type targetsQueue struct {
+	targetsController       TargetsController
+	recorder                record.EventRecorder
+	maxConcurrentReconciles int
+	queue                   workqueue.RateLimitingInterface
+
+	mutex sync.Mutex
+	// trackedTargets are the targets of tracked target groups by ARN, without the registered targets
+	trackedTargets map[string]*Targets
+	// tgLocks serializes reconciling the targets of a target group from the queue and from ingress reconciles,
+	// they are only kept while held or awaited.
+	tgLocks map[string]*targetGroupLock
+}
+
+type targetGroupLock struct {
+	sync.Mutex
+	// refs is the number of reconciles holding or awaiting the lock, it's guarded by the mutex of targetsQueue
+	refs int
+}
+
+// Reconcile reconciles the targets of a target group, and tracks it to reconcile its targets on endpoints or pods changes.
+func (q *targetsQueue) Reconcile(ctx context.Context, t *Targets) error {
+	unlock := q.lockTargetGroup(t.TgArn)
+	defer unlock()
+
+	if err := q.targetsController.Reconcile(ctx, t); err != nil {
+		return err
+	}
+
+	tracked := NewTargets(t.TargetType, t.Ingress, t.Backend)
+	tracked.TgArn = t.TgArn
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.trackedTargets[t.TgArn] = tracked
+	return nil
+}
+
+// StopReconcilingPodConditionStatus stops tracking the target group, and reconciling the pod condition statuses of its targets.
+func (q *targetsQueue) StopReconcilingPodConditionStatus(tgArn string) {
+	q.mutex.Lock()
+	delete(q.trackedTargets, tgArn)
+	q.mutex.Unlock()
+
+	q.targetsController.StopReconcilingPodConditionStatus(tgArn)
+}
+
+func (q *targetsQueue) EnqueueTargetGroups(ingressKey types.NamespacedName, serviceName string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	enqueued := false
+	for tgArn, t := range q.trackedTargets {
+		if t.Ingress.Namespace != ingressKey.Namespace || t.Ingress.Name != ingressKey.Name || t.Backend.ServiceName != serviceName {
+			continue
+		}
+		q.queue.Add(tgArn)
+		enqueued = true
+	}
+	return enqueued
+}
+
+// Start runs the workers reconciling the enqueued target groups until stop is closed.
+func (q *targetsQueue) Start(stop <-chan struct{}) error {
+	defer q.queue.ShutDown()
+	for i := 0; i < q.maxConcurrentReconciles; i++ {
+		go wait.Until(q.worker, time.Second, stop)
+	}
+	<-stop
+	return nil
+}
+
+func (q *targetsQueue) worker() {
+	for q.processNextTargetGroup() {
+	}
+}
+
+func (q *targetsQueue) processNextTargetGroup() bool {
+	key, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(key)
+
+	tgArn := key.(string)
+	q.mutex.Lock()
+	tracked, ok := q.trackedTargets[tgArn]
+	q.mutex.Unlock()
+	if !ok {
+		q.queue.Forget(key)
+		return true
+	}
+
+	t := NewTargets(tracked.TargetType, tracked.Ingress, tracked.Backend)
+	t.TgArn = tgArn
+	if err := q.Reconcile(q.buildReconcileContext(t), t); err != nil {
+		q.queue.AddRateLimited(key)
+		return true
+	}
+	q.queue.Forget(key)
+	return true
+}
+
+// buildReconcileContext builds the context to reconcile targets out of an ingress reconcile, logging and recording events for the ingress.
+func (q *targetsQueue) buildReconcileContext(t *Targets) context.Context {
+	ingressKey := types.NamespacedName{Namespace: t.Ingress.Namespace, Name: t.Ingress.Name}
+	ctx := albctx.SetLogger(context.Background(), log.New(ingressKey.String()))
+	ctx = albctx.SetEventf(ctx, func(eventType string, reason string, messageFmt string, args ...interface{}) {
+		q.recorder.Eventf(t.Ingress, eventType, reason, messageFmt, args...)
+	})
+	return ctx
+}
+
+// lockTargetGroup locks the targets of a target group, returning the function to unlock them.
+func (q *targetsQueue) lockTargetGroup(tgArn string) func() {
+	q.mutex.Lock()
+	tgLock, ok := q.tgLocks[tgArn]
+	if !ok {
+		tgLock = &targetGroupLock{}
+		q.tgLocks[tgArn] = tgLock
+	}
+	tgLock.refs++
+	q.mutex.Unlock()
+
+	tgLock.Lock()
+	return func() {
+		tgLock.Unlock()
+
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		tgLock.refs--
+		if tgLock.refs == 0 {
+			delete(q.tgLocks, tgArn)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+)
+
+func newQueuedTargets(tgArn string, ingressName string, serviceName string) *Targets {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: ingressName}}
+	backend := &extensions.IngressBackend{ServiceName: serviceName, ServicePort: intstr.FromInt(80)}
+	t := NewTargets("ip", ingress, backend)
+	t.TgArn = tgArn
+	return t
+}
+
+func Test_targetsQueue_EnqueueTargetGroups(t *testing.T) {
+	targetsController := &MockTargetsController{}
+	targetsController.On("Reconcile", mock.Anything, mock.Anything).Return(nil)
+	targetsController.On("StopReconcilingPodConditionStatus", "arn2").Return()
+	q := NewTargetsQueue(targetsController, record.NewFakeRecorder(10), 1).(*targetsQueue)
+
+	for _, targets := range []*Targets{
+		newQueuedTargets("arn1", "ingress", "service"),
+		newQueuedTargets("arn2", "ingress", "service"),
+		newQueuedTargets("arn3", "ingress", "other-service"),
+		newQueuedTargets("arn4", "other-ingress", "service"),
+	} {
+		assert.NoError(t, q.Reconcile(context.Background(), targets))
+	}
+	q.StopReconcilingPodConditionStatus("arn2")
+
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	assert.True(t, q.EnqueueTargetGroups(ingressKey, "service"))
+	assert.Equal(t, 1, q.queue.Len())
+	key, _ := q.queue.Get()
+	assert.Equal(t, "arn1", key)
+
+	assert.False(t, q.EnqueueTargetGroups(ingressKey, "unknown-service"))
+	assert.False(t, q.EnqueueTargetGroups(types.NamespacedName{Namespace: "namespace", Name: "unknown-ingress"}, "service"))
+	targetsController.AssertExpectations(t)
+}
+
+func Test_targetsQueue_Reconcile(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		reconcileErr    error
+		expectedTracked bool
+	}{
+		{
+			name:            "target group is tracked once its targets are reconciled",
+			expectedTracked: true,
+		},
+		{
+			name:            "target group isn't tracked if its targets failed to reconcile",
+			reconcileErr:    errors.New("error"),
+			expectedTracked: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			targets := newQueuedTargets("arn", "ingress", "service")
+			targetsController := &MockTargetsController{}
+			targetsController.On("Reconcile", mock.Anything, targets).Return(tc.reconcileErr)
+			q := NewTargetsQueue(targetsController, record.NewFakeRecorder(10), 1).(*targetsQueue)
+
+			assert.Equal(t, tc.reconcileErr, q.Reconcile(context.Background(), targets))
+			assert.Equal(t, tc.expectedTracked, q.EnqueueTargetGroups(types.NamespacedName{Namespace: "namespace", Name: "ingress"}, "service"))
+			targetsController.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_targetsQueue_processNextTargetGroup(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		reconcileErr  error
+		expectedRetry bool
+	}{
+		{
+			name: "enqueued target group is reconciled",
+		},
+		{
+			name:          "enqueued target group is retried on error",
+			reconcileErr:  errors.New("error"),
+			expectedRetry: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			targets := newQueuedTargets("arn", "ingress", "service")
+			targetsController := &MockTargetsController{}
+			targetsController.On("Reconcile", mock.Anything, targets).Return(nil).Once()
+			q := NewTargetsQueue(targetsController, record.NewFakeRecorder(10), 1).(*targetsQueue)
+			assert.NoError(t, q.Reconcile(context.Background(), targets))
+
+			targetsController.On("Reconcile", mock.Anything, mock.MatchedBy(func(queued *Targets) bool {
+				return queued.TgArn == "arn" && queued.Ingress == targets.Ingress && queued.Backend == targets.Backend
+			})).Return(tc.reconcileErr).Once()
+			q.queue.Add("arn")
+			assert.True(t, q.processNextTargetGroup())
+			assert.Equal(t, tc.expectedRetry, q.queue.NumRequeues("arn") > 0)
+			targetsController.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_targetsQueue_processNextTargetGroup_untracked(t *testing.T) {
+	targetsController := &MockTargetsController{}
+	q := NewTargetsQueue(targetsController, record.NewFakeRecorder(10), 1).(*targetsQueue)
+
+	q.queue.Add("arn")
+	assert.True(t, q.processNextTargetGroup())
+	targetsController.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything)
+
+	q.queue.ShutDown()
+	assert.False(t, q.processNextTargetGroup())
+}
+
+func Test_targetsQueue_lockTargetGroup(t *testing.T) {
+	targetsController := &MockTargetsController{}
+	targetsController.On("StopReconcilingPodConditionStatus", "arn").Return()
+	q := NewTargetsQueue(targetsController, record.NewFakeRecorder(10), 1).(*targetsQueue)
+	refs := func() int {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		if tgLock, ok := q.tgLocks["arn"]; ok {
+			return tgLock.refs
+		}
+		return 0
+	}
+
+	unlock := q.lockTargetGroup("arn")
+	locked := make(chan struct{})
+	go func() {
+		unlockAwaited := q.lockTargetGroup("arn")
+		close(locked)
+		unlockAwaited()
+	}()
+	assert.NoError(t, wait.PollImmediate(time.Millisecond, time.Second, func() (bool, error) {
+		return refs() == 2, nil
+	}))
+
+	// the lock is kept while held or awaited, even once the target group is no longer tracked
+	q.StopReconcilingPodConditionStatus("arn")
+	assert.Equal(t, 2, refs())
+	select {
+	case <-locked:
+		t.Fatal("target group locked twice")
+	default:
+	}
+
+	unlock()
+	<-locked
+	assert.NoError(t, wait.PollImmediate(time.Millisecond, time.Second, func() (bool, error) {
+		return refs() == 0, nil
+	}))
+	q.mutex.Lock()
+	assert.Empty(t, q.tgLocks)
+	q.mutex.Unlock()
+	targetsController.AssertExpectations(t)
+}
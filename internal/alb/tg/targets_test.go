@@ -9,14 +9,18 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	fakekube "k8s.io/client-go/kubernetes/fake"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -288,10 +292,13 @@ func Test_TargetsReconcile(t *testing.T) {
 			}
 
 			store := &store.MockStorer{}
+			store.On("GetConfig").Maybe().Return(&config.Configuration{FeatureGate: config.NewFeatureGate()})
+			store.On("GetIngressAnnotations", mock.Anything).Maybe().Return(nil, errors.New("no annotations"))
 			client := testclient.NewFakeClient()
-			healthController := NewTargetHealthController(cloud, store, endpointResolver, client)
+			targetHealthCache := NewTargetHealthCache(cloud, metric.DummyCollector{})
+			healthController := NewTargetHealthController(cloud, store, endpointResolver, client, fakekube.NewSimpleClientset().CoreV1(), targetHealthCache, metric.DummyCollector{})
 
-			controller := NewTargetsController(cloud, endpointResolver, healthController)
+			controller := NewTargetsController(cloud, endpointResolver, healthController, targetHealthCache)
 			err := controller.Reconcile(context.Background(), tc.Targets)
 
 			if tc.ExpectedError != nil {
@@ -305,6 +312,80 @@ func Test_TargetsReconcile(t *testing.T) {
 
 	}
 }
+func Test_TargetsReconcile_TargetHealthCache(t *testing.T) {
+	ctx := context.Background()
+	tgArn := "arn:"
+	ingress := dummy.NewIngress()
+	backend := &extensions.IngressBackend{ServiceName: "name", ServicePort: intstr.FromInt(123)}
+
+	endpointResolver := &mocks.EndpointResolver{}
+	endpointResolver.On("Resolve", ingress, backend, elbv2.TargetTypeEnumInstance).Return([]*elbv2.TargetDescription{newTd("id", 123)}, nil)
+	cloud := &mocks.CloudAPI{}
+	describeInput := &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(tgArn)}
+	cloud.On("DescribeTargetHealthWithContext", ctx, describeInput).Return(&elbv2.DescribeTargetHealthOutput{}, nil).Once()
+	cloud.On("RegisterTargetsWithContext", ctx, &elbv2.RegisterTargetsInput{TargetGroupArn: aws.String(tgArn), Targets: []*elbv2.TargetDescription{newTd("id", 123)}}).Return(nil, nil).Once()
+	cloud.On("DescribeTargetHealthWithContext", ctx, describeInput).Return(&elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+		{Target: newTd("id", 123), TargetHealth: newTh(elbv2.TargetHealthStateEnumInitial)},
+	}}, nil).Once()
+
+	store := &store.MockStorer{}
+	store.On("GetConfig").Maybe().Return(&config.Configuration{FeatureGate: config.NewFeatureGate()})
+	store.On("GetIngressAnnotations", mock.Anything).Maybe().Return(nil, errors.New("no annotations"))
+	targetHealthCache := NewTargetHealthCache(cloud, metric.DummyCollector{})
+	healthController := NewTargetHealthController(cloud, store, endpointResolver, testclient.NewFakeClient(), fakekube.NewSimpleClientset().CoreV1(), targetHealthCache, metric.DummyCollector{})
+	controller := NewTargetsController(cloud, endpointResolver, healthController, targetHealthCache)
+
+	// the registration invalidates the cached target health, so that the next reconcile describes it again,
+	// and the reconciles after reuse it rather than registering the target twice
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, controller.Reconcile(ctx, &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumInstance}))
+	}
+	cloud.AssertExpectations(t)
+}
+
+func Test_TargetsReconcile_SignalsDrainingTargets(t *testing.T) {
+	ctx := context.Background()
+	tgArn := "arn:"
+	ingress := dummy.NewIngress()
+	backend := &extensions.IngressBackend{ServiceName: "name", ServicePort: intstr.FromInt(123)}
+
+	endpointResolver := &mocks.EndpointResolver{}
+	endpointResolver.On("Resolve", ingress, backend, elbv2.TargetTypeEnumIp).Return([]*elbv2.TargetDescription{}, nil)
+	endpointResolver.On("ReverseResolve", ingress, backend, mock.Anything).Maybe().Return(nil, nil)
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetVpcWithContext", ctx).Return(&ec2.Vpc{}, nil)
+	cloud.On("DescribeTargetHealthWithContext", ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(tgArn)}).Return(&elbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+			{Target: newTd("10.0.0.1", 123), TargetHealth: newTh(elbv2.TargetHealthStateEnumDraining)},
+		},
+	}, nil)
+
+	cfg := &config.Configuration{FeatureGate: config.NewFeatureGate()}
+	cfg.FeatureGate.Enable(config.PodDeregistrationSignal)
+	store := store.NewDummy()
+	store.SetConfig(cfg)
+
+	// the pod of a target deregistered by a previous reconcile, whose signal failed
+	deletionTimestamp := metav1.Now()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ingress.Namespace, Name: "terminating", DeletionTimestamp: &deletionTimestamp},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+	client := testclient.NewFakeClient(pod.DeepCopy())
+	kubeClient := fakekube.NewSimpleClientset(pod.DeepCopy())
+	targetHealthCache := NewTargetHealthCache(cloud, metric.DummyCollector{})
+	healthController := NewTargetHealthController(cloud, store, endpointResolver, client, kubeClient.CoreV1(), targetHealthCache, metric.DummyCollector{})
+
+	controller := NewTargetsController(cloud, endpointResolver, healthController, targetHealthCache)
+	assert.NoError(t, controller.Reconcile(ctx, &Targets{TgArn: tgArn, Ingress: ingress, Backend: backend, TargetType: elbv2.TargetTypeEnumIp}))
+
+	actualPod, err := kubeClient.CoreV1().Pods(ingress.Namespace).Get("terminating", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "300", actualPod.Annotations[PodDeregistrationDelayAnnotation])
+	assert.NotEmpty(t, actualPod.Annotations[PodDeregistrationDeadlineAnnotation])
+	cloud.AssertExpectations(t)
+}
+
 func Test_targetChangeSets(t *testing.T) {
 	for _, tc := range []struct {
 		name      string
@@ -2,6 +2,7 @@ package tg
 
 import (
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	extensions "k8s.io/api/extensions/v1beta1"
 )
 
@@ -10,6 +11,37 @@ type TargetGroup struct {
 	Arn        string
 	TargetType string
 	Targets    []*elbv2.TargetDescription
+
+	// ProtocolVersion is the protocol version of targetGroups of service backends, e.g. HTTP1.
+	ProtocolVersion string
+
+	// ReplacedArns are the targetGroups of the backend that keep serving its requests while this targetGroup replaces them
+	// and isn't healthy yet.
+	ReplacedArns []string
+}
+
+// TargetGroupTuples returns the targetGroupTuples forwarding requests to the targetGroup with weight.
+// While the targetGroup replaces others, the first replaced targetGroup keeps the weight, and the targetGroup is attached
+// with a weight of 0, so that its targets are health checked without serving requests.
+func (t TargetGroup) TargetGroupTuples(weight int64) []*elbv2.TargetGroupTuple {
+	var tgTuples []*elbv2.TargetGroupTuple
+	for i, replacedArn := range t.ReplacedArns {
+		replacedWeight := int64(0)
+		if i == 0 {
+			replacedWeight = weight
+		}
+		tgTuples = append(tgTuples, &elbv2.TargetGroupTuple{
+			TargetGroupArn: aws.String(replacedArn),
+			Weight:         aws.Int64(replacedWeight),
+		})
+	}
+	if len(t.ReplacedArns) != 0 {
+		weight = 0
+	}
+	return append(tgTuples, &elbv2.TargetGroupTuple{
+		TargetGroupArn: aws.String(t.Arn),
+		Weight:         aws.Int64(weight),
+	})
 }
 
 // TargetGroupGroup represents an collection of targetGroups for a single ingress in AWS
@@ -17,6 +49,9 @@ type TargetGroupGroup struct {
 	// targetGroups created for serviceBackends.
 	TGByBackend map[extensions.IngressBackend]TargetGroup
 
+	// targetGroups created for lambda functions by function ARN.
+	TGByLambdaFunction map[string]TargetGroup
+
 	// external targetGroups referenced by ARN.
 	externalTGARNs []string
 	selector       map[string]string
@@ -25,9 +60,9 @@ type TargetGroupGroup struct {
 // NameGenerator provides name generation functionality for tg package.
 type NameGenerator interface {
 	// NameTG generates name for targetGroups.
-	// Note: targetType & protocol is included here to ensure we'll create new targetGroups if one of them changed(they cannot be modified)
+	// Note: targetType, protocol & protocolVersion is included here to ensure we'll create new targetGroups if one of them changed(they cannot be modified)
 	NameTG(namespace string, ingressName string, serviceName, servicePort string,
-		targetType string, protocol string) string
+		targetType string, protocol string, protocolVersion string) string
 }
 
 // TagGenerator provides tag generation functionality for tg package.
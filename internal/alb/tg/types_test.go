@@ -0,0 +1,38 @@
+package tg
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetGroup_TargetGroupTuples(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		targetGroup TargetGroup
+		expected    []*elbv2.TargetGroupTuple
+	}{
+		{
+			name:        "targetGroup gets the weight",
+			targetGroup: TargetGroup{Arn: "arn2"},
+			expected: []*elbv2.TargetGroupTuple{
+				{TargetGroupArn: aws.String("arn2"), Weight: aws.Int64(10)},
+			},
+		},
+		{
+			name:        "targetGroup replacing others is attached with a weight of 0",
+			targetGroup: TargetGroup{Arn: "arn3", ReplacedArns: []string{"arn1", "arn2"}},
+			expected: []*elbv2.TargetGroupTuple{
+				{TargetGroupArn: aws.String("arn1"), Weight: aws.Int64(10)},
+				{TargetGroupArn: aws.String("arn2"), Weight: aws.Int64(0)},
+				{TargetGroupArn: aws.String("arn3"), Weight: aws.Int64(0)},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.targetGroup.TargetGroupTuples(10))
+		})
+	}
+}
@@ -10,16 +10,24 @@ import (
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/service/acm"
 	"github.com/aws/aws-sdk-go/service/acm/acmiface"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-sdk-go/service/shield"
 	"github.com/aws/aws-sdk-go/service/shield/shieldiface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/wafregional"
 	"github.com/aws/aws-sdk-go/service/wafregional/wafregionaliface"
 	"github.com/aws/aws-sdk-go/service/wafv2"
@@ -30,11 +38,15 @@ import (
 
 type CloudAPI interface {
 	ACMAPI
+	CognitoAPI
 	EC2API
 	ELBV2API
 	IAMAPI
+	LambdaAPI
 	ResourceGroupsTaggingAPIAPI
+	SecretsManagerAPI
 	ShieldAPI
+	SSMAPI
 	WAFRegionalAPI
 	WAFV2API
 
@@ -51,10 +63,15 @@ type Cloud struct {
 	ec2         ec2iface.EC2API
 	elbv2       elbv2iface.ELBV2API
 	iam         iamiface.IAMAPI
+	lambda      lambdaiface.LambdaAPI
 	shield      shieldiface.ShieldAPI
 	rgt         resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
 	wafregional wafregionaliface.WAFRegionalAPI
 	wafv2       wafv2iface.WAFV2API
+
+	secretsmanager secretsmanageriface.SecretsManagerAPI
+	ssm            ssmiface.SSMAPI
+	cognito        cognitoidentityprovideriface.CognitoIdentityProviderAPI
 }
 
 // Initialize the global AWS clients.
@@ -89,10 +106,14 @@ func New(cfg CloudConfig, clusterName string, mc metric.Collector, ce bool, cc *
 		ec2.New(awsSession),
 		elbv2.New(awsSession),
 		iam.New(awsSession),
+		lambda.New(awsSession),
 		shield.New(awsSession, &aws.Config{Region: aws.String("us-east-1")}),
 		resourcegroupstaggingapi.New(awsSession),
 		wafregional.New(awsSession),
 		wafv2.New(awsSession),
+		secretsmanager.New(awsSession),
+		ssm.New(awsSession),
+		cognitoidentityprovider.New(awsSession),
 	}, nil
 }
 
@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// the maximum number of user pools returned by each ListUserPools request
+const listUserPoolsMaxResults = 60
+
+// CognitoAPI is our wrapper CognitoIdentityProvider API interface
+type CognitoAPI interface {
+	// GetUserPoolByName returns the user pool named name, an error is returned unless exactly one user pool matches
+	GetUserPoolByName(ctx context.Context, name string) (*cognitoidentityprovider.UserPoolType, error)
+
+	// GetUserPoolClientByName returns the client named name of user pool userPoolID, an error is returned unless exactly one client matches
+	GetUserPoolClientByName(ctx context.Context, userPoolID string, name string) (*cognitoidentityprovider.UserPoolClientType, error)
+
+	// DescribeUserPoolClient returns the client clientID of user pool userPoolID
+	DescribeUserPoolClient(ctx context.Context, userPoolID string, clientID string) (*cognitoidentityprovider.UserPoolClientType, error)
+}
+
+func (c *Cloud) GetUserPoolByName(ctx context.Context, name string) (*cognitoidentityprovider.UserPoolType, error) {
+	var userPoolIDs []string
+	if err := c.cognito.ListUserPoolsPagesWithContext(ctx, &cognitoidentityprovider.ListUserPoolsInput{
+		MaxResults: aws.Int64(listUserPoolsMaxResults),
+	}, func(output *cognitoidentityprovider.ListUserPoolsOutput, _ bool) bool {
+		for _, userPool := range output.UserPools {
+			if aws.StringValue(userPool.Name) == name {
+				userPoolIDs = append(userPoolIDs, aws.StringValue(userPool.Id))
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if len(userPoolIDs) != 1 {
+		return nil, fmt.Errorf("expected exactly one user pool named %v, got %v", name, len(userPoolIDs))
+	}
+
+	resp, err := c.cognito.DescribeUserPoolWithContext(ctx, &cognitoidentityprovider.DescribeUserPoolInput{
+		UserPoolId: aws.String(userPoolIDs[0]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.UserPool, nil
+}
+
+func (c *Cloud) GetUserPoolClientByName(ctx context.Context, userPoolID string, name string) (*cognitoidentityprovider.UserPoolClientType, error) {
+	var clientIDs []string
+	if err := c.cognito.ListUserPoolClientsPagesWithContext(ctx, &cognitoidentityprovider.ListUserPoolClientsInput{
+		UserPoolId: aws.String(userPoolID),
+	}, func(output *cognitoidentityprovider.ListUserPoolClientsOutput, _ bool) bool {
+		for _, client := range output.UserPoolClients {
+			if aws.StringValue(client.ClientName) == name {
+				clientIDs = append(clientIDs, aws.StringValue(client.ClientId))
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if len(clientIDs) != 1 {
+		return nil, fmt.Errorf("expected exactly one client named %v in user pool %v, got %v", name, userPoolID, len(clientIDs))
+	}
+	return c.DescribeUserPoolClient(ctx, userPoolID, clientIDs[0])
+}
+
+func (c *Cloud) DescribeUserPoolClient(ctx context.Context, userPoolID string, clientID string) (*cognitoidentityprovider.UserPoolClientType, error) {
+	resp, err := c.cognito.DescribeUserPoolClientWithContext(ctx, &cognitoidentityprovider.DescribeUserPoolClientInput{
+		UserPoolId: aws.String(userPoolID),
+		ClientId:   aws.String(clientID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.UserPoolClient, nil
+}
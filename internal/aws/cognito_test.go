@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockCognitoAPI mocks the CognitoIdentityProvider API calls used by Cloud
+type mockCognitoAPI struct {
+	cognitoidentityprovideriface.CognitoIdentityProviderAPI
+	mock.Mock
+
+	userPoolPages [][]*cognitoidentityprovider.UserPoolDescriptionType
+}
+
+func (m *mockCognitoAPI) ListUserPoolsPagesWithContext(ctx aws.Context, input *cognitoidentityprovider.ListUserPoolsInput, fn func(*cognitoidentityprovider.ListUserPoolsOutput, bool) bool, _ ...request.Option) error {
+	for i, page := range m.userPoolPages {
+		if !fn(&cognitoidentityprovider.ListUserPoolsOutput{UserPools: page}, i == len(m.userPoolPages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockCognitoAPI) DescribeUserPoolWithContext(ctx aws.Context, input *cognitoidentityprovider.DescribeUserPoolInput, _ ...request.Option) (*cognitoidentityprovider.DescribeUserPoolOutput, error) {
+	ret := m.Called(ctx, input)
+	output, _ := ret.Get(0).(*cognitoidentityprovider.DescribeUserPoolOutput)
+	return output, ret.Error(1)
+}
+
+func TestCloud_GetUserPoolByName(t *testing.T) {
+	for _, tc := range []struct {
+		Name          string
+		UserPoolPages [][]*cognitoidentityprovider.UserPoolDescriptionType
+		DescribeID    string
+		Expected      *cognitoidentityprovider.UserPoolType
+		ExpectedError error
+	}{
+		{
+			Name: "one user pool named on the second page",
+			UserPoolPages: [][]*cognitoidentityprovider.UserPoolDescriptionType{
+				{{Id: aws.String("id-1"), Name: aws.String("other")}},
+				{{Id: aws.String("id-2"), Name: aws.String("pool")}},
+			},
+			DescribeID: "id-2",
+			Expected:   &cognitoidentityprovider.UserPoolType{Id: aws.String("id-2"), Name: aws.String("pool")},
+		},
+		{
+			Name: "no user pool named",
+			UserPoolPages: [][]*cognitoidentityprovider.UserPoolDescriptionType{
+				{{Id: aws.String("id-1"), Name: aws.String("other")}},
+			},
+			ExpectedError: errors.New("expected exactly one user pool named pool, got 0"),
+		},
+		{
+			Name: "multiple user pools named",
+			UserPoolPages: [][]*cognitoidentityprovider.UserPoolDescriptionType{
+				{{Id: aws.String("id-1"), Name: aws.String("pool")}},
+				{{Id: aws.String("id-2"), Name: aws.String("pool")}},
+			},
+			ExpectedError: errors.New("expected exactly one user pool named pool, got 2"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cognitosvc := &mockCognitoAPI{userPoolPages: tc.UserPoolPages}
+			if tc.DescribeID != "" {
+				cognitosvc.On("DescribeUserPoolWithContext", ctx, &cognitoidentityprovider.DescribeUserPoolInput{
+					UserPoolId: aws.String(tc.DescribeID),
+				}).Return(&cognitoidentityprovider.DescribeUserPoolOutput{UserPool: tc.Expected}, nil)
+			}
+
+			cloud := &Cloud{
+				cognito: cognitosvc,
+			}
+
+			userPool, err := cloud.GetUserPoolByName(ctx, "pool")
+			assert.Equal(t, tc.Expected, userPool)
+			assert.Equal(t, tc.ExpectedError, err)
+			cognitosvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/lambda"
+)
+
+// ELBPrincipal is the service principal of Elastic Load Balancing invoking lambda functions
+const ELBPrincipal = "elasticloadbalancing.amazonaws.com"
+
+// LambdaAPI is our wrapper Lambda API interface
+type LambdaAPI interface {
+	// AddLambdaInvokePermission grants the targetGroup sourceArn the permission to invoke the lambda function with the policy statement statementID,
+	// succeeding if the statement already exists
+	AddLambdaInvokePermission(ctx context.Context, functionArn string, statementID string, sourceArn string) error
+
+	// RemoveLambdaInvokePermission revokes the permission granted by the policy statement statementID from the lambda function,
+	// succeeding if the statement or function doesn't exist
+	RemoveLambdaInvokePermission(ctx context.Context, functionArn string, statementID string) error
+}
+
+func (c *Cloud) AddLambdaInvokePermission(ctx context.Context, functionArn string, statementID string, sourceArn string) error {
+	_, err := c.lambda.AddPermissionWithContext(ctx, &lambda.AddPermissionInput{
+		Action:       aws.String("lambda:InvokeFunction"),
+		FunctionName: aws.String(functionArn),
+		Principal:    aws.String(ELBPrincipal),
+		SourceArn:    aws.String(sourceArn),
+		StatementId:  aws.String(statementID),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == lambda.ErrCodeResourceConflictException {
+		return nil
+	}
+	return err
+}
+
+func (c *Cloud) RemoveLambdaInvokePermission(ctx context.Context, functionArn string, statementID string) error {
+	_, err := c.lambda.RemovePermissionWithContext(ctx, &lambda.RemovePermissionInput{
+		FunctionName: aws.String(functionArn),
+		StatementId:  aws.String(statementID),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == lambda.ErrCodeResourceNotFoundException {
+		return nil
+	}
+	return err
+}
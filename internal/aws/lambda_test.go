@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockLambdaAPI mocks the Lambda API calls used by Cloud
+type mockLambdaAPI struct {
+	lambdaiface.LambdaAPI
+	mock.Mock
+}
+
+func (m *mockLambdaAPI) AddPermissionWithContext(ctx aws.Context, input *lambda.AddPermissionInput, _ ...request.Option) (*lambda.AddPermissionOutput, error) {
+	ret := m.Called(ctx, input)
+	output, _ := ret.Get(0).(*lambda.AddPermissionOutput)
+	return output, ret.Error(1)
+}
+
+func (m *mockLambdaAPI) RemovePermissionWithContext(ctx aws.Context, input *lambda.RemovePermissionInput, _ ...request.Option) (*lambda.RemovePermissionOutput, error) {
+	ret := m.Called(ctx, input)
+	output, _ := ret.Get(0).(*lambda.RemovePermissionOutput)
+	return output, ret.Error(1)
+}
+
+func TestCloud_AddLambdaInvokePermission(t *testing.T) {
+	for _, tc := range []struct {
+		Name               string
+		AddPermissionError error
+		ExpectedError      error
+	}{
+		{
+			Name: "permission added",
+		},
+		{
+			Name:               "permission already added",
+			AddPermissionError: awserr.New(lambda.ErrCodeResourceConflictException, "The statement id (k8s-tgName) provided already exists.", nil),
+		},
+		{
+			Name:               "Error from AddPermissionWithContext",
+			AddPermissionError: errors.New(lambda.ErrCodeResourceNotFoundException),
+			ExpectedError:      errors.New(lambda.ErrCodeResourceNotFoundException),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			lambdasvc := &mockLambdaAPI{}
+			lambdasvc.On("AddPermissionWithContext", ctx, &lambda.AddPermissionInput{
+				Action:       aws.String("lambda:InvokeFunction"),
+				FunctionName: aws.String("functionArn"),
+				Principal:    aws.String("elasticloadbalancing.amazonaws.com"),
+				SourceArn:    aws.String("tgArn"),
+				StatementId:  aws.String("k8s-tgName"),
+			}).Return(&lambda.AddPermissionOutput{}, tc.AddPermissionError)
+
+			cloud := &Cloud{
+				lambda: lambdasvc,
+			}
+
+			err := cloud.AddLambdaInvokePermission(ctx, "functionArn", "k8s-tgName", "tgArn")
+			assert.Equal(t, tc.ExpectedError, err)
+			lambdasvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCloud_RemoveLambdaInvokePermission(t *testing.T) {
+	for _, tc := range []struct {
+		Name                  string
+		RemovePermissionError error
+		ExpectedError         error
+	}{
+		{
+			Name: "permission removed",
+		},
+		{
+			Name:                  "permission already removed",
+			RemovePermissionError: awserr.New(lambda.ErrCodeResourceNotFoundException, "No policy is associated with the given resource.", nil),
+		},
+		{
+			Name:                  "Error from RemovePermissionWithContext",
+			RemovePermissionError: errors.New(lambda.ErrCodeServiceException),
+			ExpectedError:         errors.New(lambda.ErrCodeServiceException),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			lambdasvc := &mockLambdaAPI{}
+			lambdasvc.On("RemovePermissionWithContext", ctx, &lambda.RemovePermissionInput{
+				FunctionName: aws.String("functionArn"),
+				StatementId:  aws.String("k8s-tgName"),
+			}).Return(&lambda.RemovePermissionOutput{}, tc.RemovePermissionError)
+
+			cloud := &Cloud{
+				lambda: lambdasvc,
+			}
+
+			err := cloud.RemoveLambdaInvokePermission(ctx, "functionArn", "k8s-tgName")
+			assert.Equal(t, tc.ExpectedError, err)
+			lambdasvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// SecretsManagerAPI is our wrapper SecretsManager API interface
+type SecretsManagerAPI interface {
+	// GetSecretValue returns the current version of the secret identified by secretID
+	GetSecretValue(ctx context.Context, secretID string) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+func (c *Cloud) GetSecretValue(ctx context.Context, secretID string) (*secretsmanager.GetSecretValueOutput, error) {
+	return c.secretsmanager.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+}
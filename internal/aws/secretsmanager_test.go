@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockSecretsManagerAPI mocks the SecretsManager API calls used by Cloud
+type mockSecretsManagerAPI struct {
+	secretsmanageriface.SecretsManagerAPI
+	mock.Mock
+}
+
+func (m *mockSecretsManagerAPI) GetSecretValueWithContext(ctx aws.Context, input *secretsmanager.GetSecretValueInput, _ ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	ret := m.Called(ctx, input)
+	output, _ := ret.Get(0).(*secretsmanager.GetSecretValueOutput)
+	return output, ret.Error(1)
+}
+
+func TestCloud_GetSecretValue(t *testing.T) {
+	secretArn := "arn:aws:secretsmanager:us-west-2:123456789012:secret:alb/oidc"
+
+	for _, tc := range []struct {
+		Name                    string
+		GetSecretValueResponses []*secretsmanager.GetSecretValueOutput
+		GetSecretValueError     error
+		Expected                []*secretsmanager.GetSecretValueOutput
+		ExpectedError           error
+	}{
+		{
+			Name: "current version of the secret",
+			GetSecretValueResponses: []*secretsmanager.GetSecretValueOutput{
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("value"),
+					VersionId:     aws.String("version-1"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+			},
+			Expected: []*secretsmanager.GetSecretValueOutput{
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("value"),
+					VersionId:     aws.String("version-1"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+			},
+		},
+		{
+			Name: "rotated secret returns the new current version",
+			GetSecretValueResponses: []*secretsmanager.GetSecretValueOutput{
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("value"),
+					VersionId:     aws.String("version-1"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("rotated-value"),
+					VersionId:     aws.String("version-2"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+			},
+			Expected: []*secretsmanager.GetSecretValueOutput{
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("value"),
+					VersionId:     aws.String("version-1"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+				{
+					ARN:           aws.String(secretArn),
+					SecretString:  aws.String("rotated-value"),
+					VersionId:     aws.String("version-2"),
+					VersionStages: aws.StringSlice([]string{"AWSCURRENT"}),
+				},
+			},
+		},
+		{
+			Name:                    "Error from GetSecretValueWithContext",
+			GetSecretValueResponses: []*secretsmanager.GetSecretValueOutput{nil},
+			GetSecretValueError:     errors.New(secretsmanager.ErrCodeResourceNotFoundException),
+			Expected:                []*secretsmanager.GetSecretValueOutput{nil},
+			ExpectedError:           errors.New(secretsmanager.ErrCodeResourceNotFoundException),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			secretsmanagersvc := &mockSecretsManagerAPI{}
+			for _, response := range tc.GetSecretValueResponses {
+				secretsmanagersvc.On("GetSecretValueWithContext", ctx, &secretsmanager.GetSecretValueInput{
+					SecretId: aws.String(secretArn),
+				}).Return(response, tc.GetSecretValueError).Once()
+			}
+
+			cloud := &Cloud{
+				secretsmanager: secretsmanagersvc,
+			}
+
+			for _, expected := range tc.Expected {
+				output, err := cloud.GetSecretValue(ctx, secretArn)
+				assert.Equal(t, expected, output)
+				assert.Equal(t, tc.ExpectedError, err)
+			}
+			secretsmanagersvc.AssertExpectations(t)
+		})
+	}
+}
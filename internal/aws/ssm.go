@@ -0,0 +1,29 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// SSMAPI is our wrapper SSM API interface
+type SSMAPI interface {
+	// GetSecureStringParameter returns the decrypted SecureString parameter identified by name
+	GetSecureStringParameter(ctx context.Context, name string) (*ssm.Parameter, error)
+}
+
+func (c *Cloud) GetSecureStringParameter(ctx context.Context, name string) (*ssm.Parameter, error) {
+	resp, err := c.ssm.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(resp.Parameter.Type) != ssm.ParameterTypeSecureString {
+		return nil, fmt.Errorf("parameter %v is %v instead of %v", name, aws.StringValue(resp.Parameter.Type), ssm.ParameterTypeSecureString)
+	}
+	return resp.Parameter, nil
+}
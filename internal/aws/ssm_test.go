@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockSSMAPI mocks the SSM API calls used by Cloud
+type mockSSMAPI struct {
+	ssmiface.SSMAPI
+	mock.Mock
+}
+
+func (m *mockSSMAPI) GetParameterWithContext(ctx aws.Context, input *ssm.GetParameterInput, _ ...request.Option) (*ssm.GetParameterOutput, error) {
+	ret := m.Called(ctx, input)
+	output, _ := ret.Get(0).(*ssm.GetParameterOutput)
+	return output, ret.Error(1)
+}
+
+func TestCloud_GetSecureStringParameter(t *testing.T) {
+	for _, tc := range []struct {
+		Name                 string
+		GetParameterResponse *ssm.GetParameterOutput
+		GetParameterError    error
+		Expected             *ssm.Parameter
+		ExpectedError        error
+	}{
+		{
+			Name: "SecureString parameter",
+			GetParameterResponse: &ssm.GetParameterOutput{
+				Parameter: &ssm.Parameter{
+					Name:    aws.String("/alb/oidc"),
+					Type:    aws.String(ssm.ParameterTypeSecureString),
+					Value:   aws.String("value"),
+					Version: aws.Int64(2),
+				},
+			},
+			Expected: &ssm.Parameter{
+				Name:    aws.String("/alb/oidc"),
+				Type:    aws.String(ssm.ParameterTypeSecureString),
+				Value:   aws.String("value"),
+				Version: aws.Int64(2),
+			},
+		},
+		{
+			Name: "String parameter",
+			GetParameterResponse: &ssm.GetParameterOutput{
+				Parameter: &ssm.Parameter{
+					Name:  aws.String("/alb/oidc"),
+					Type:  aws.String(ssm.ParameterTypeString),
+					Value: aws.String("value"),
+				},
+			},
+			ExpectedError: errors.New("parameter /alb/oidc is String instead of SecureString"),
+		},
+		{
+			Name:              "Error from GetParameterWithContext",
+			GetParameterError: errors.New(ssm.ErrCodeParameterNotFound),
+			ExpectedError:     errors.New(ssm.ErrCodeParameterNotFound),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			ssmsvc := &mockSSMAPI{}
+			ssmsvc.On("GetParameterWithContext", ctx, &ssm.GetParameterInput{
+				Name:           aws.String("/alb/oidc"),
+				WithDecryption: aws.Bool(true),
+			}).Return(tc.GetParameterResponse, tc.GetParameterError)
+
+			cloud := &Cloud{
+				ssm: ssmsvc,
+			}
+
+			parameter, err := cloud.GetSecureStringParameter(ctx, "/alb/oidc")
+			assert.Equal(t, tc.Expected, parameter)
+			assert.Equal(t, tc.ExpectedError, err)
+			ssmsvc.AssertExpectations(t)
+		})
+	}
+}
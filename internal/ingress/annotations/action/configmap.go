@@ -0,0 +1,74 @@
+package action
+
+import (
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ConfigMapNames returns the names of ConfigMaps referenced by the actions
+func (c *Config) ConfigMapNames() []string {
+	names := sets.NewString()
+	for _, action := range c.Actions {
+		if action.FixedResponseConfig == nil {
+			continue
+		}
+		for _, selector := range []*ConfigMapKeySelector{action.FixedResponseConfig.ContentTypeFrom, action.FixedResponseConfig.MessageBodyFrom} {
+			if selector != nil {
+				names.Insert(aws.StringValue(selector.Name))
+			}
+		}
+	}
+	return names.List()
+}
+
+// ResolveConfigMapRefs returns a copy of the Config, where the ContentType and MessageBody of fixed response actions
+// are populated from the ConfigMap keys they reference. getConfigMap looks up ConfigMaps by name.
+func (c *Config) ResolveConfigMapRefs(getConfigMap func(name string) (*corev1.ConfigMap, error)) (*Config, error) {
+	actions := make(map[string]Action, len(c.Actions))
+	for actionName, action := range c.Actions {
+		if action.FixedResponseConfig != nil {
+			fixedResponseConfig := *action.FixedResponseConfig
+			if fixedResponseConfig.ContentTypeFrom != nil {
+				contentType, err := resolveConfigMapKey(fixedResponseConfig.ContentTypeFrom, getConfigMap)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to resolve ContentType of action %v", actionName)
+				}
+				fixedResponseConfig.ContentType = aws.String(contentType)
+				if err := validateContentType(fixedResponseConfig.ContentType); err != nil {
+					return nil, errors.Wrapf(err, "invalid ContentType of action %v from ConfigMap %v key %v", actionName,
+						aws.StringValue(fixedResponseConfig.ContentTypeFrom.Name), aws.StringValue(fixedResponseConfig.ContentTypeFrom.Key))
+				}
+			}
+			if fixedResponseConfig.MessageBodyFrom != nil {
+				messageBody, err := resolveConfigMapKey(fixedResponseConfig.MessageBodyFrom, getConfigMap)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to resolve MessageBody of action %v", actionName)
+				}
+				fixedResponseConfig.MessageBody = aws.String(messageBody)
+				if err := validateMessageBody(fixedResponseConfig.MessageBody); err != nil {
+					return nil, errors.Wrapf(err, "invalid MessageBody of action %v from ConfigMap %v key %v", actionName,
+						aws.StringValue(fixedResponseConfig.MessageBodyFrom.Name), aws.StringValue(fixedResponseConfig.MessageBodyFrom.Key))
+				}
+			}
+			action.FixedResponseConfig = &fixedResponseConfig
+		}
+		actions[actionName] = action
+	}
+	resolved := *c
+	resolved.Actions = actions
+	return &resolved, nil
+}
+
+func resolveConfigMapKey(selector *ConfigMapKeySelector, getConfigMap func(name string) (*corev1.ConfigMap, error)) (string, error) {
+	configMap, err := getConfigMap(aws.StringValue(selector.Name))
+	if err != nil {
+		return "", err
+	}
+	value, ok := configMap.Data[aws.StringValue(selector.Key)]
+	if !ok {
+		return "", errors.Errorf("key %v not found in ConfigMap %v", aws.StringValue(selector.Key), aws.StringValue(selector.Name))
+	}
+	return value, nil
+}
@@ -0,0 +1,149 @@
+package action
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfig_ConfigMapNames(t *testing.T) {
+	config := &Config{
+		Actions: map[string]Action{
+			"inline": {
+				Type: aws.String(elbv2.ActionTypeEnumFixedResponse),
+				FixedResponseConfig: &FixedResponseActionConfig{
+					MessageBody: aws.String("body"),
+					StatusCode:  aws.String("503"),
+				},
+			},
+			"from-config-map": {
+				Type: aws.String(elbv2.ActionTypeEnumFixedResponse),
+				FixedResponseConfig: &FixedResponseActionConfig{
+					ContentTypeFrom: &ConfigMapKeySelector{Name: aws.String("cm-2"), Key: aws.String("content-type")},
+					MessageBodyFrom: &ConfigMapKeySelector{Name: aws.String("cm-1"), Key: aws.String("body")},
+					StatusCode:      aws.String("503"),
+				},
+			},
+			"forward": {
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				TargetGroupArn: aws.String("tgArn"),
+			},
+		},
+	}
+	assert.Equal(t, []string{"cm-1", "cm-2"}, config.ConfigMapNames())
+}
+
+func TestConfig_ResolveConfigMapRefs(t *testing.T) {
+	configMaps := map[string]*corev1.ConfigMap{
+		"maintenance": {
+			ObjectMeta: metav1.ObjectMeta{Name: "maintenance"},
+			Data: map[string]string{
+				"body.html":    "<h1>Under maintenance</h1>",
+				"content-type": "text/html",
+				"large.html":   strings.Repeat("x", MaxMessageBodyLength+1),
+				"invalid-type": "image/png",
+			},
+		},
+	}
+	getConfigMap := func(name string) (*corev1.ConfigMap, error) {
+		configMap, ok := configMaps[name]
+		if !ok {
+			return nil, errors.New("configMap not found")
+		}
+		return configMap, nil
+	}
+
+	for _, tc := range []struct {
+		name                        string
+		fixedResponseConfig         FixedResponseActionConfig
+		expectedFixedResponseConfig FixedResponseActionConfig
+		expectedErr                 string
+	}{
+		{
+			name: "inline message body",
+			fixedResponseConfig: FixedResponseActionConfig{
+				ContentType: aws.String("text/plain"),
+				MessageBody: aws.String("body"),
+				StatusCode:  aws.String("503"),
+			},
+			expectedFixedResponseConfig: FixedResponseActionConfig{
+				ContentType: aws.String("text/plain"),
+				MessageBody: aws.String("body"),
+				StatusCode:  aws.String("503"),
+			},
+		},
+		{
+			name: "message body and content type from ConfigMap",
+			fixedResponseConfig: FixedResponseActionConfig{
+				ContentTypeFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("content-type")},
+				MessageBodyFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("body.html")},
+				StatusCode:      aws.String("503"),
+			},
+			expectedFixedResponseConfig: FixedResponseActionConfig{
+				ContentType:     aws.String("text/html"),
+				ContentTypeFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("content-type")},
+				MessageBody:     aws.String("<h1>Under maintenance</h1>"),
+				MessageBodyFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("body.html")},
+				StatusCode:      aws.String("503"),
+			},
+		},
+		{
+			name: "missing ConfigMap",
+			fixedResponseConfig: FixedResponseActionConfig{
+				MessageBodyFrom: &ConfigMapKeySelector{Name: aws.String("absent"), Key: aws.String("body.html")},
+				StatusCode:      aws.String("503"),
+			},
+			expectedErr: "failed to resolve MessageBody of action test-action: configMap not found",
+		},
+		{
+			name: "missing ConfigMap key",
+			fixedResponseConfig: FixedResponseActionConfig{
+				ContentTypeFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("absent")},
+				StatusCode:      aws.String("503"),
+			},
+			expectedErr: "failed to resolve ContentType of action test-action: key absent not found in ConfigMap maintenance",
+		},
+		{
+			name: "message body from ConfigMap exceeds size limit",
+			fixedResponseConfig: FixedResponseActionConfig{
+				MessageBodyFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("large.html")},
+				StatusCode:      aws.String("503"),
+			},
+			expectedErr: "invalid MessageBody of action test-action from ConfigMap maintenance key large.html: MessageBody is 1025 characters long, exceeding the limit of 1024 characters",
+		},
+		{
+			name: "content type from ConfigMap not accepted by ALB",
+			fixedResponseConfig: FixedResponseActionConfig{
+				ContentTypeFrom: &ConfigMapKeySelector{Name: aws.String("maintenance"), Key: aws.String("invalid-type")},
+				StatusCode:      aws.String("503"),
+			},
+			expectedErr: "invalid ContentType of action test-action from ConfigMap maintenance key invalid-type: ContentType image/png is not one of text/plain, text/css, text/html, application/javascript, application/json",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fixedResponseConfig := tc.fixedResponseConfig
+			config := &Config{
+				Actions: map[string]Action{
+					"test-action": {
+						Type:                aws.String(elbv2.ActionTypeEnumFixedResponse),
+						FixedResponseConfig: &fixedResponseConfig,
+					},
+				},
+			}
+			resolved, err := config.ResolveConfigMapRefs(getConfigMap)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFixedResponseConfig, *resolved.Actions["test-action"].FixedResponseConfig)
+			assert.Equal(t, tc.fixedResponseConfig, *config.Actions["test-action"].FixedResponseConfig, "original config should not be modified")
+		})
+	}
+}
@@ -71,6 +71,15 @@ func (c *Config) GetAction(serviceName string) (Action, error) {
 	return action, nil
 }
 
+// GetPathBackend returns the backend for the path named pathName.
+// An action bound to the path takes precedence over the path's backend.
+func (c *Config) GetPathBackend(pathName string, path extensions.HTTPIngressPath) extensions.IngressBackend {
+	if _, ok := c.Actions[pathName]; ok && pathName != "" {
+		return AnnotationBackend(pathName)
+	}
+	return path.Backend
+}
+
 // Use returns true if the parameter requested an annotation configured action
 func Use(s string) bool {
 	return s == UseActionAnnotation
@@ -94,6 +103,14 @@ func Default404Backend() extensions.IngressBackend {
 	}
 }
 
+// AnnotationBackend returns an IngressBackend that will use the action named actionName
+func AnnotationBackend(actionName string) extensions.IngressBackend {
+	return extensions.IngressBackend{
+		ServiceName: actionName,
+		ServicePort: intstr.FromString(UseActionAnnotation),
+	}
+}
+
 func Dummy() *Config {
 	redirectAction := Action{
 		Type: aws.String(elbv2.ActionTypeEnumRedirect),
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -85,6 +86,20 @@ func TestIngressActions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "forward-lambda",
+			actionJSON: `{"Type": "forward", "ForwardConfig": {"TargetGroups": [{"LambdaFunctionArn": "arn:aws:lambda:us-west-2:123456789012:function:fn"}]}}`,
+			expectedAction: Action{
+				Type: aws.String(elbv2.ActionTypeEnumForward),
+				ForwardConfig: &ForwardActionConfig{
+					TargetGroups: []*TargetGroupTuple{
+						{
+							LambdaFunctionArn: aws.String("arn:aws:lambda:us-west-2:123456789012:function:fn"),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	data := map[string]string{}
@@ -120,6 +135,21 @@ func TestInvalidIngressActions(t *testing.T) {
 			actionJSON:  `{"Type": "fixed-response"}`,
 			expectedErr: "missing FixedResponseConfig",
 		},
+		{
+			name:        "should error if both MessageBody and MessageBodyFrom are specified for fixed-response action",
+			actionJSON:  `{"Type": "fixed-response", "FixedResponseConfig": {"StatusCode": "503", "MessageBody": "body", "MessageBodyFrom": {"Name": "cm", "Key": "body"}}}`,
+			expectedErr: "invalid FixedResponseConfig: precisely one of MessageBody and MessageBodyFrom can be specified",
+		},
+		{
+			name:        "should error if Key absent for MessageBodyFrom",
+			actionJSON:  `{"Type": "fixed-response", "FixedResponseConfig": {"StatusCode": "503", "MessageBodyFrom": {"Name": "cm"}}}`,
+			expectedErr: "invalid FixedResponseConfig: invalid MessageBodyFrom: Key is required",
+		},
+		{
+			name:        "should error if MessageBody exceeds the size limit",
+			actionJSON:  `{"Type": "fixed-response", "FixedResponseConfig": {"StatusCode": "503", "MessageBody": "` + strings.Repeat("x", 1025) + `"}}`,
+			expectedErr: "invalid FixedResponseConfig: MessageBody is 1025 characters long, exceeding the limit of 1024 characters",
+		},
 		{
 			name:        "should error if RedirectConfig absent for redirect action",
 			actionJSON:  `{"Type": "redirect"}`,
@@ -140,6 +170,16 @@ func TestInvalidIngressActions(t *testing.T) {
 			actionJSON:  `{"Type": "forward", "TargetGroupArn": "tg-1", "ForwardConfig": {"TargetGroups": [{"TargetGroupArn": "tg-2", "weight": 10}]}}`,
 			expectedErr: "precisely one of TargetGroupArn and ForwardConfig can be specified",
 		},
+		{
+			name:        "should error if both TargetGroupArn and LambdaFunctionArn are specified for a target group",
+			actionJSON:  `{"Type": "forward", "ForwardConfig": {"TargetGroups": [{"TargetGroupArn": "tg-1", "LambdaFunctionArn": "arn:aws:lambda:us-west-2:123456789012:function:fn"}]}}`,
+			expectedErr: "invalid ForwardConfig: invalid TargetGroupTuple: precisely one of TargetGroupArn, ServiceName and LambdaFunctionArn can be specified",
+		},
+		{
+			name:        "should error if LambdaFunctionArn isn't the ARN of a lambda function",
+			actionJSON:  `{"Type": "forward", "ForwardConfig": {"TargetGroups": [{"LambdaFunctionArn": "arn:aws:lambda:us-west-2:123456789012:layer:fn"}]}}`,
+			expectedErr: "invalid ForwardConfig: invalid TargetGroupTuple: LambdaFunctionArn arn:aws:lambda:us-west-2:123456789012:layer:fn is not the ARN of a lambda function",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ing := dummy.NewIngress()
@@ -1,11 +1,21 @@
 package action
 
 import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/pkg/errors"
 )
 
+// MaxMessageBodyLength is the maximum length of the message of a fixed response action.
+const MaxMessageBodyLength = 1024
+
+// contentTypes are the content types of fixed response actions accepted by ALB.
+var contentTypes = []string{"text/plain", "text/css", "text/html", "application/javascript", "application/json"}
+
 // Information about an action that returns a custom HTTP response.
 type FixedResponseActionConfig struct {
 	// The content type.
@@ -21,6 +31,81 @@ type FixedResponseActionConfig struct {
 	//
 	// StatusCode is a required field
 	StatusCode *string
+
+	// Selects the content type from a key of a ConfigMap in the Ingress namespace.
+	// Cannot be specified together with ContentType.
+	ContentTypeFrom *ConfigMapKeySelector
+
+	// Selects the message from a key of a ConfigMap in the Ingress namespace.
+	// Cannot be specified together with MessageBody.
+	MessageBodyFrom *ConfigMapKeySelector
+}
+
+func (c *FixedResponseActionConfig) validate() error {
+	if c.ContentType != nil && c.ContentTypeFrom != nil {
+		return errors.New("precisely one of ContentType and ContentTypeFrom can be specified")
+	}
+	if c.MessageBody != nil && c.MessageBodyFrom != nil {
+		return errors.New("precisely one of MessageBody and MessageBodyFrom can be specified")
+	}
+	if c.ContentTypeFrom != nil {
+		if err := c.ContentTypeFrom.validate(); err != nil {
+			return errors.Wrap(err, "invalid ContentTypeFrom")
+		}
+	}
+	if c.MessageBodyFrom != nil {
+		if err := c.MessageBodyFrom.validate(); err != nil {
+			return errors.Wrap(err, "invalid MessageBodyFrom")
+		}
+	}
+	if err := validateContentType(c.ContentType); err != nil {
+		return err
+	}
+	return validateMessageBody(c.MessageBody)
+}
+
+// validateContentType checks the content type against the ones accepted by ALB for fixed responses
+func validateContentType(contentType *string) error {
+	if contentType == nil {
+		return nil
+	}
+	for _, valid := range contentTypes {
+		if aws.StringValue(contentType) == valid {
+			return nil
+		}
+	}
+	return errors.Errorf("ContentType %v is not one of %v", aws.StringValue(contentType), strings.Join(contentTypes, ", "))
+}
+
+// validateMessageBody checks the message against the fixed response size limit of ALB
+func validateMessageBody(messageBody *string) error {
+	if length := utf8.RuneCountInString(aws.StringValue(messageBody)); length > MaxMessageBodyLength {
+		return errors.Errorf("MessageBody is %v characters long, exceeding the limit of %v characters", length, MaxMessageBodyLength)
+	}
+	return nil
+}
+
+// Selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// The name of the ConfigMap.
+	//
+	// Name is a required field
+	Name *string
+
+	// The key of the ConfigMap to select.
+	//
+	// Key is a required field
+	Key *string
+}
+
+func (s *ConfigMapKeySelector) validate() error {
+	if s.Name == nil {
+		return errors.New("Name is required")
+	}
+	if s.Key == nil {
+		return errors.New("Key is required")
+	}
+	return nil
 }
 
 // Information about an redirect action
@@ -100,18 +185,33 @@ type TargetGroupTuple struct {
 	// the K8s service port
 	ServicePort *string
 
+	// The Amazon Resource Name (ARN) of a lambda function, which is invoked through a target group of target-type lambda created for the ingress.
+	LambdaFunctionArn *string
+
 	// The weight. The range is 0 to 999.
 	Weight *int64
 }
 
 func (t *TargetGroupTuple) validate() error {
-	if (t.TargetGroupArn != nil) == (t.ServiceName != nil) {
-		return errors.New("precisely one of TargetGroupArn and ServiceName can be specified")
+	specified := 0
+	for _, field := range []*string{t.TargetGroupArn, t.ServiceName, t.LambdaFunctionArn} {
+		if field != nil {
+			specified++
+		}
+	}
+	if specified != 1 {
+		return errors.New("precisely one of TargetGroupArn, ServiceName and LambdaFunctionArn can be specified")
 	}
 
 	if t.ServiceName != nil && t.ServicePort == nil {
 		return errors.New("missing ServicePort")
 	}
+	if t.LambdaFunctionArn != nil {
+		functionArn, err := arn.Parse(*t.LambdaFunctionArn)
+		if err != nil || functionArn.Service != "lambda" || !strings.HasPrefix(functionArn.Resource, "function:") {
+			return errors.Errorf("LambdaFunctionArn %v is not the ARN of a lambda function", *t.LambdaFunctionArn)
+		}
+	}
 	return nil
 }
 
@@ -175,6 +275,9 @@ func (a *Action) validate() error {
 		if a.FixedResponseConfig == nil {
 			return errors.New("missing FixedResponseConfig")
 		}
+		if err := a.FixedResponseConfig.validate(); err != nil {
+			return errors.Wrap(err, "invalid FixedResponseConfig")
+		}
 	case elbv2.ActionTypeEnumRedirect:
 		if a.RedirectConfig == nil {
 			return errors.New("missing RedirectConfig")
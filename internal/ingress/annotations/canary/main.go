@@ -0,0 +1,83 @@
+package canary
+
+import (
+	"encoding/json"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// AnnotationStatus is the annotation through which the controller reports the canaries applied to the load balancer
+const AnnotationStatus = "canary-status"
+
+// Config contains canaries keyed by the serviceName of their stable backend, or by the name of the path they are bound to.
+type Config struct {
+	Canaries map[string]Canary
+}
+
+type canaryParser struct{}
+
+// NewParser creates a new canary annotation parser
+func NewParser() parser.IngressAnnotation {
+	return &canaryParser{}
+}
+
+// Parse parses the annotations contained in the resource
+func (p *canaryParser) Parse(ing parser.AnnotationInterface) (interface{}, error) {
+	canaries := make(map[string]Canary)
+	annos, err := parser.GetStringAnnotations("canary", ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+
+	for name, raw := range annos {
+		canary := Canary{}
+		if err := json.Unmarshal([]byte(raw), &canary); err != nil {
+			return nil, err
+		}
+		if err := canary.validate(); err != nil {
+			return nil, pkgerrors.Wrapf(err, "invalid canary %v", name)
+		}
+		canaries[name] = canary
+	}
+
+	return &Config{
+		Canaries: canaries,
+	}, nil
+}
+
+// GetPathCanary returns the canary for the path named pathName.
+// A canary bound to the path takes precedence over the canary named after the path's backend serviceName.
+func (c *Config) GetPathCanary(pathName string, serviceName string) (Canary, bool) {
+	if canary, ok := c.Canaries[pathName]; ok && pathName != "" {
+		return canary, true
+	}
+	canary, ok := c.Canaries[serviceName]
+	return canary, ok
+}
+
+// Status reports a canary applied to the load balancer
+type Status struct {
+	// The name of the canary service.
+	ServiceName *string
+
+	// The percentage of requests routed to the canary service by weight.
+	Weight *int64
+}
+
+// BuildStatus returns the status of the canaries keyed by their name, once they are applied to the load balancer
+func (c *Config) BuildStatus() map[string]Status {
+	status := make(map[string]Status, len(c.Canaries))
+	for name, canary := range c.Canaries {
+		status[name] = Status{
+			ServiceName: canary.ServiceName,
+			Weight:      aws.Int64(aws.Int64Value(canary.Weight)),
+		}
+	}
+	return status
+}
@@ -0,0 +1,179 @@
+package canary
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryParse(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		canaryJSON     string
+		expectedCanary Canary
+		expectedErr    string
+	}{
+		{
+			name:       "weighted canary",
+			canaryJSON: `{"ServiceName": "svc-v2", "ServicePort": "http", "Weight": 10}`,
+			expectedCanary: Canary{
+				ServiceName: aws.String("svc-v2"),
+				ServicePort: aws.String("http"),
+				Weight:      aws.Int64(10),
+			},
+		},
+		{
+			name:       "canary with header and cookie match",
+			canaryJSON: `{"ServiceName": "svc-v2", "ServicePort": "80", "HeaderMatch": {"HttpHeaderName": "X-Canary", "Values": ["true"]}, "CookieMatch": {"Name": "canary", "Value": "always"}}`,
+			expectedCanary: Canary{
+				ServiceName: aws.String("svc-v2"),
+				ServicePort: aws.String("80"),
+				HeaderMatch: &HeaderMatch{
+					HttpHeaderName: aws.String("X-Canary"),
+					Values:         aws.StringSlice([]string{"true"}),
+				},
+				CookieMatch: &CookieMatch{
+					Name:  aws.String("canary"),
+					Value: aws.String("always"),
+				},
+			},
+		},
+		{
+			name:        "missing ServicePort",
+			canaryJSON:  `{"ServiceName": "svc-v2"}`,
+			expectedErr: "invalid canary svc: ServicePort is required",
+		},
+		{
+			name:        "weight out of range",
+			canaryJSON:  `{"ServiceName": "svc-v2", "ServicePort": "80", "Weight": 101}`,
+			expectedErr: "invalid canary svc: Weight must be within 0 to 100, got 101",
+		},
+		{
+			name:        "header match without values",
+			canaryJSON:  `{"ServiceName": "svc-v2", "ServicePort": "80", "HeaderMatch": {"HttpHeaderName": "X-Canary"}}`,
+			expectedErr: "invalid canary svc: invalid HeaderMatch: Values cannot be empty",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := dummy.NewIngress()
+			ing.SetAnnotations(map[string]string{
+				parser.GetAnnotationWithPrefix("canary.svc"): tc.canaryJSON,
+			})
+			raw, err := NewParser().Parse(ing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCanary, raw.(*Config).Canaries["svc"])
+		})
+	}
+}
+
+func TestConfig_GetPathCanary(t *testing.T) {
+	byService := Canary{ServiceName: aws.String("by-service"), ServicePort: aws.String("80")}
+	byPath := Canary{ServiceName: aws.String("by-path"), ServicePort: aws.String("80")}
+	config := &Config{
+		Canaries: map[string]Canary{
+			"svc":  byService,
+			"beta": byPath,
+		},
+	}
+
+	canary, ok := config.GetPathCanary("", "svc")
+	assert.True(t, ok)
+	assert.Equal(t, byService, canary)
+
+	canary, ok = config.GetPathCanary("beta", "svc")
+	assert.True(t, ok)
+	assert.Equal(t, byPath, canary)
+
+	_, ok = config.GetPathCanary("other", "other-svc")
+	assert.False(t, ok)
+}
+
+func TestConfig_BuildStatus(t *testing.T) {
+	config := &Config{
+		Canaries: map[string]Canary{
+			"svc":           {ServiceName: aws.String("svc-v2"), ServicePort: aws.String("80"), Weight: aws.Int64(20)},
+			"rule-0.path-1": {ServiceName: aws.String("other-v2"), ServicePort: aws.String("80")},
+		},
+	}
+	assert.Equal(t, map[string]Status{
+		"svc":           {ServiceName: aws.String("svc-v2"), Weight: aws.Int64(20)},
+		"rule-0.path-1": {ServiceName: aws.String("other-v2"), Weight: aws.Int64(0)},
+	}, config.BuildStatus())
+}
+
+// matchesHeaderPattern matches value against an ALB http-header condition pattern, where * matches any characters and ? a single one.
+func matchesHeaderPattern(pattern string, value string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+	return regexp.MustCompile("^" + expr + "$").MatchString(value)
+}
+
+func TestCookieMatch_HeaderValues(t *testing.T) {
+	cookieMatch := &CookieMatch{Name: aws.String("canary"), Value: aws.String("always")}
+	for _, tc := range []struct {
+		name          string
+		cookieHeader  string
+		expectedMatch bool
+	}{
+		{
+			name:          "only cookie",
+			cookieHeader:  "canary=always",
+			expectedMatch: true,
+		},
+		{
+			name:          "first cookie",
+			cookieHeader:  "canary=always; session=abc",
+			expectedMatch: true,
+		},
+		{
+			name:          "last cookie",
+			cookieHeader:  "session=abc; canary=always",
+			expectedMatch: true,
+		},
+		{
+			name:          "middle cookie",
+			cookieHeader:  "session=abc; canary=always; theme=dark",
+			expectedMatch: true,
+		},
+		{
+			name:          "cookie name with prefix",
+			cookieHeader:  "xcanary=always",
+			expectedMatch: false,
+		},
+		{
+			name:          "cookie value with suffix",
+			cookieHeader:  "session=abc; canary=always2",
+			expectedMatch: false,
+		},
+		{
+			name:          "other cookie value containing the cookie",
+			cookieHeader:  "othername=canary=alwaysx; session=abc",
+			expectedMatch: false,
+		},
+		{
+			name:          "cookie with another value",
+			cookieHeader:  "canary=never",
+			expectedMatch: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matched := false
+			for _, pattern := range cookieMatch.HeaderValues() {
+				if matchesHeaderPattern(pattern, tc.cookieHeader) {
+					matched = true
+				}
+			}
+			assert.Equal(t, tc.expectedMatch, matched)
+		})
+	}
+}
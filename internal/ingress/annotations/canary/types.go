@@ -0,0 +1,123 @@
+package canary
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/pkg/errors"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Information about a canary, which routes a part of the requests for a stable backend to a canary service.
+type Canary struct {
+	// The name of the canary service.
+	//
+	// ServiceName is a required field
+	ServiceName *string
+
+	// The port of the canary service.
+	//
+	// ServicePort is a required field
+	ServicePort *string
+
+	// The percentage of the requests not matched by HeaderMatch or CookieMatch
+	// that are routed to the canary service. The range is 0 to 100.
+	Weight *int64
+
+	// Requests with a matching HTTP header are always routed to the canary service.
+	HeaderMatch *HeaderMatch
+
+	// Requests with a matching cookie are always routed to the canary service.
+	CookieMatch *CookieMatch
+}
+
+func (c *Canary) validate() error {
+	if c.ServiceName == nil {
+		return errors.New("ServiceName is required")
+	}
+	if c.ServicePort == nil {
+		return errors.New("ServicePort is required")
+	}
+	if weight := aws.Int64Value(c.Weight); weight < 0 || weight > 100 {
+		return errors.Errorf("Weight must be within 0 to 100, got %v", weight)
+	}
+	if c.HeaderMatch != nil {
+		if err := c.HeaderMatch.validate(); err != nil {
+			return errors.Wrap(err, "invalid HeaderMatch")
+		}
+	}
+	if c.CookieMatch != nil {
+		if err := c.CookieMatch.validate(); err != nil {
+			return errors.Wrap(err, "invalid CookieMatch")
+		}
+	}
+	return nil
+}
+
+// Backend returns the backend of the canary service
+func (c *Canary) Backend() extensions.IngressBackend {
+	return extensions.IngressBackend{
+		ServiceName: aws.StringValue(c.ServiceName),
+		ServicePort: intstr.Parse(aws.StringValue(c.ServicePort)),
+	}
+}
+
+// Information about an HTTP header match.
+type HeaderMatch struct {
+	// The name of the HTTP header field.
+	//
+	// HttpHeaderName is a required field
+	HttpHeaderName *string
+
+	// One or more strings to compare against the value of the HTTP header, the
+	// header matches if one of them matches. Wildcards * and ? are supported.
+	//
+	// Values is a required field
+	Values []*string
+}
+
+func (m *HeaderMatch) validate() error {
+	if m.HttpHeaderName == nil {
+		return errors.New("HttpHeaderName is required")
+	}
+	if len(m.Values) == 0 {
+		return errors.New("Values cannot be empty")
+	}
+	return nil
+}
+
+// Information about a cookie match.
+type CookieMatch struct {
+	// The name of the cookie.
+	//
+	// Name is a required field
+	Name *string
+
+	// The value of the cookie.
+	//
+	// Value is a required field
+	Value *string
+}
+
+func (m *CookieMatch) validate() error {
+	if m.Name == nil {
+		return errors.New("Name is required")
+	}
+	if m.Value == nil {
+		return errors.New("Value is required")
+	}
+	return nil
+}
+
+// HeaderValues returns the patterns matching the Cookie HTTP header of requests having the cookie.
+// The cookie is delimited by `; ` from other cookies, so that cookies whose name or value merely contains it don't match.
+func (m *CookieMatch) HeaderValues() []string {
+	cookie := fmt.Sprintf("%v=%v", aws.StringValue(m.Name), aws.StringValue(m.Value))
+	return []string{
+		cookie,
+		cookie + ";*",
+		"*; " + cookie,
+		"*; " + cookie + ";*",
+	}
+}
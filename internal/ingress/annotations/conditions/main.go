@@ -61,6 +61,15 @@ func (c *Config) GetConditions(serviceName string) []RuleCondition {
 	return conditions
 }
 
+// GetPathConditions returns the conditions for the path named pathName.
+// Conditions bound to the path take precedence over the conditions named after the path's backend serviceName.
+func (c *Config) GetPathConditions(pathName string, serviceName string) []RuleCondition {
+	if conditions, ok := c.Conditions[pathName]; ok && pathName != "" {
+		return conditions
+	}
+	return c.GetConditions(serviceName)
+}
+
 // Use returns true if the parameter requested an annotation configured action
 func Use(s string) bool {
 	return s == UseConditionAnnotation
@@ -17,6 +17,7 @@ limitations under the License.
 package parser
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -162,6 +163,59 @@ func GetAnnotationWithPrefix(suffix string) string {
 	return fmt.Sprintf("%v/%v", AnnotationsPrefix, suffix)
 }
 
+// GetPathNames parses the path-names annotation, which names paths of the ingress spec, like `{"v1":"d1.example.com/v1"}`.
+// The paths are keyed by name, as the host of the ingress rule followed by the path.
+func GetPathNames(ing AnnotationInterface) (map[string]string, error) {
+	name := GetAnnotationWithPrefix("path-names")
+	raw, ok := ing.GetAnnotations()[name]
+	if !ok {
+		return nil, nil
+	}
+	var paths map[string]string
+	if err := json.Unmarshal([]byte(raw), &paths); err != nil {
+		return nil, errors.NewInvalidAnnotationContent(name, raw)
+	}
+	names := make(map[string]string, len(paths))
+	for pathName, hostPath := range paths {
+		if otherName, ok := names[hostPath]; ok {
+			return nil, errors.Errorf("path %v is named both %v and %v by annotation %v", hostPath, otherName, pathName, name)
+		}
+		names[hostPath] = pathName
+	}
+	return paths, nil
+}
+
+// GetPathName returns the name which binds name-keyed annotations, such as actions and conditions,
+// to the path of the ingress rule for host. The name is empty if the path-names annotation doesn't name the path.
+func GetPathName(ing AnnotationInterface, host string, path string) (string, error) {
+	paths, err := GetPathNames(ing)
+	if err != nil {
+		return "", err
+	}
+	for pathName, hostPath := range paths {
+		if hostPath == host+path {
+			return pathName, nil
+		}
+	}
+	return "", nil
+}
+
+// GetPathNameHost returns the host of the ingress rule of the path named pathName, and whether the path is named.
+func GetPathNameHost(ing AnnotationInterface, pathName string) (string, bool) {
+	paths, err := GetPathNames(ing)
+	if err != nil {
+		return "", false
+	}
+	hostPath, ok := paths[pathName]
+	if !ok {
+		return "", false
+	}
+	if i := strings.Index(hostPath, "/"); i >= 0 {
+		return hostPath[:i], true
+	}
+	return hostPath, true
+}
+
 // MergeString replaces a with b if it is undefined or the default value d
 func MergeString(a, b *string, d string) *string {
 	if b == nil {
@@ -187,3 +187,49 @@ func TestMergeString(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPathName(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		host    string
+		path    string
+		exp     string
+		expHost string
+		expErr  bool
+	}{
+		{"unnamed paths", "", "d1.example.com", "/v1", "", "", false},
+		{"path of rule with host", `{"v1": "d1.example.com/v1"}`, "d1.example.com", "/v1", "v1", "d1.example.com", false},
+		{"path of rule without host", `{"v1": "/v1"}`, "", "/v1", "v1", "", false},
+		{"same path of another host", `{"v1": "d1.example.com/v1"}`, "d2.example.com", "/v1", "", "", false},
+		{"path named twice", `{"v1": "/v1", "other": "/v1"}`, "", "/v1", "", "", true},
+		{"invalid annotation", `{`, "", "/v1", "", "", true},
+	}
+
+	for _, test := range tests {
+		ing := buildIngress()
+		if test.value != "" {
+			ing.SetAnnotations(map[string]string{GetAnnotationWithPrefix("path-names"): test.value})
+		}
+
+		pathName, err := GetPathName(ing, test.host, test.path)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("%v: expected error but retuned nil", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", test.name, err)
+		}
+		if pathName != test.exp {
+			t.Errorf("%v: expected \"%v\" but \"%v\" was returned", test.name, test.exp, pathName)
+		}
+		if pathName == "" {
+			continue
+		}
+		if host, ok := GetPathNameHost(ing, pathName); !ok || host != test.expHost {
+			t.Errorf("%v: expected host \"%v\" but \"%v\" was returned", test.name, test.expHost, host)
+		}
+	}
+}
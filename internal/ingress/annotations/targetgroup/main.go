@@ -27,15 +27,25 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type Config struct {
 	Attributes              []*elbv2.TargetGroupAttribute
 	BackendProtocol         *string
+	ProtocolVersion         *string
 	HealthyThresholdCount   *int64
 	SuccessCodes            *string
 	TargetType              *string
 	UnhealthyThresholdCount *int64
+
+	// RuleStickinessDurationSeconds enables stickiness between the targetGroups of a weighted forward action when set
+	RuleStickinessDurationSeconds *int64
+	// PathRuleStickinessDurationSeconds are the rule stickiness durations bound to paths by path name, like `rule-stickiness-duration-seconds.public`
+	PathRuleStickinessDurationSeconds map[string]int64
+
+	// TargetNodeSelector restricts the nodes registered as instance targets when set
+	TargetNodeSelector labels.Selector
 }
 
 type targetGroup struct {
@@ -47,6 +57,19 @@ const (
 	DefaultHealthyThresholdCount   = 2
 	DefaultUnhealthyThresholdCount = 2
 	DefaultSuccessCodes            = "200"
+	DefaultGRPCSuccessCodes        = "12"
+
+	ProtocolVersionHTTP1 = "HTTP1"
+	ProtocolVersionHTTP2 = "HTTP2"
+	ProtocolVersionGRPC  = "GRPC"
+
+	DefaultProtocolVersion = ProtocolVersionHTTP1
+
+	StickinessTypeLBCookie  = "lb-cookie"
+	StickinessTypeAppCookie = "app-cookie"
+
+	MinStickinessDurationSeconds = 1
+	MaxStickinessDurationSeconds = 604800
 )
 
 // NewParser creates a new target group annotation parser
@@ -72,6 +95,17 @@ func (tg targetGroup) Parse(ing parser.AnnotationInterface) (interface{}, error)
 		backendProtocol = aws.String(DefaultBackendProtocol)
 	}
 
+	protocolVersion, err := parser.GetStringAnnotation("backend-protocol-version", ing)
+	if err != nil {
+		protocolVersion = aws.String(DefaultProtocolVersion)
+	}
+
+	switch *protocolVersion {
+	case ProtocolVersionHTTP1, ProtocolVersionHTTP2, ProtocolVersionGRPC:
+	default:
+		return "", errors.NewInvalidAnnotationContent("backend-protocol-version", *protocolVersion)
+	}
+
 	healthyThresholdCount, err := parser.GetInt64Annotation("healthy-threshold-count", ing)
 	if err != nil {
 		healthyThresholdCount = aws.Int64(DefaultHealthyThresholdCount)
@@ -82,11 +116,8 @@ func (tg targetGroup) Parse(ing parser.AnnotationInterface) (interface{}, error)
 		unhealthyThresholdCount = aws.Int64(DefaultUnhealthyThresholdCount)
 	}
 
-	// support legacy successCodes annotation
-	successCodes, err := parser.GetStringAnnotation("successCodes", ing)
-	if err != nil {
-		successCodes = aws.String(DefaultSuccessCodes)
-	}
+	// support legacy successCodes annotation, success codes left unset default for the merged protocol version
+	successCodes, _ := parser.GetStringAnnotation("successCodes", ing)
 
 	s, err := parser.GetStringAnnotation("success-codes", ing)
 	if err == nil {
@@ -98,13 +129,38 @@ func (tg targetGroup) Parse(ing parser.AnnotationInterface) (interface{}, error)
 		return nil, err
 	}
 
+	stickinessAttributes, err := parseStickinessAttributes(ing)
+	if err != nil {
+		return nil, err
+	}
+	attributes = append(attributes, stickinessAttributes...)
+
+	ruleStickinessDurationSeconds, err := parseStickinessDurationSeconds("rule-stickiness-duration-seconds", ing)
+	if err != nil {
+		return nil, err
+	}
+	pathRuleStickinessDurationSeconds, err := parsePathStickinessDurationSeconds("rule-stickiness-duration-seconds", ing)
+	if err != nil {
+		return nil, err
+	}
+
+	targetNodeSelector, err := parseTargetNodeSelector(ing)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		TargetType:              targetType,
 		BackendProtocol:         backendProtocol,
+		ProtocolVersion:         protocolVersion,
 		HealthyThresholdCount:   healthyThresholdCount,
 		UnhealthyThresholdCount: unhealthyThresholdCount,
 		SuccessCodes:            successCodes,
 		Attributes:              attributes,
+
+		RuleStickinessDurationSeconds:     ruleStickinessDurationSeconds,
+		PathRuleStickinessDurationSeconds: pathRuleStickinessDurationSeconds,
+		TargetNodeSelector:                targetNodeSelector,
 	}, nil
 }
 
@@ -114,17 +170,48 @@ func (a *Config) Merge(b *Config, cfg *config.Configuration) *Config {
 	if attributes == nil {
 		attributes = b.Attributes
 	}
+	ruleStickinessDurationSeconds := a.RuleStickinessDurationSeconds
+	if ruleStickinessDurationSeconds == nil {
+		ruleStickinessDurationSeconds = b.RuleStickinessDurationSeconds
+	}
+	pathRuleStickinessDurationSeconds := a.PathRuleStickinessDurationSeconds
+	if pathRuleStickinessDurationSeconds == nil {
+		pathRuleStickinessDurationSeconds = b.PathRuleStickinessDurationSeconds
+	}
+	targetNodeSelector := a.TargetNodeSelector
+	if targetNodeSelector == nil {
+		targetNodeSelector = b.TargetNodeSelector
+	}
+	protocolVersion := parser.MergeString(a.ProtocolVersion, b.ProtocolVersion, DefaultProtocolVersion)
+	successCodes := parser.MergeString(a.SuccessCodes, b.SuccessCodes, DefaultSuccessCodes)
+	if successCodes == nil {
+		successCodes = aws.String(defaultSuccessCodes(aws.StringValue(protocolVersion)))
+	}
 
 	return &Config{
 		Attributes:              attributes,
 		BackendProtocol:         parser.MergeString(a.BackendProtocol, b.BackendProtocol, DefaultBackendProtocol),
+		ProtocolVersion:         protocolVersion,
 		TargetType:              parser.MergeString(a.TargetType, b.TargetType, cfg.DefaultTargetType),
-		SuccessCodes:            parser.MergeString(a.SuccessCodes, b.SuccessCodes, DefaultSuccessCodes),
+		SuccessCodes:            successCodes,
 		HealthyThresholdCount:   parser.MergeInt64(a.HealthyThresholdCount, b.HealthyThresholdCount, DefaultHealthyThresholdCount),
 		UnhealthyThresholdCount: parser.MergeInt64(a.UnhealthyThresholdCount, b.UnhealthyThresholdCount, DefaultUnhealthyThresholdCount),
+
+		RuleStickinessDurationSeconds:     ruleStickinessDurationSeconds,
+		PathRuleStickinessDurationSeconds: pathRuleStickinessDurationSeconds,
+		TargetNodeSelector:                targetNodeSelector,
 	}
 }
 
+// defaultSuccessCodes returns the default success codes of the health checks of the protocol version,
+// gRPC status codes for GRPC and HTTP status codes otherwise.
+func defaultSuccessCodes(protocolVersion string) string {
+	if protocolVersion == ProtocolVersionGRPC {
+		return DefaultGRPCSuccessCodes
+	}
+	return DefaultSuccessCodes
+}
+
 func parseAttributes(ing parser.AnnotationInterface) ([]*elbv2.TargetGroupAttribute, error) {
 	var invalid []string
 	var output []*elbv2.TargetGroupAttribute
@@ -151,9 +238,110 @@ func parseAttributes(ing parser.AnnotationInterface) ([]*elbv2.TargetGroupAttrib
 	return output, nil
 }
 
+// parseStickinessAttributes translates the typed stickiness annotations into targetGroup attributes.
+// They are appended after `target-group-attributes` so that they take precedence.
+func parseStickinessAttributes(ing parser.AnnotationInterface) ([]*elbv2.TargetGroupAttribute, error) {
+	stickinessType, err := parser.GetStringAnnotation("stickiness-type", ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	durationSeconds, err := parseStickinessDurationSeconds("stickiness-duration-seconds", ing)
+	if err != nil {
+		return nil, err
+	}
+	cookieName, err := parser.GetStringAnnotation("stickiness-app-cookie-name", ing)
+	if err != nil && !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
+	attributes := []*elbv2.TargetGroupAttribute{
+		tgAttribute("stickiness.enabled", "true"),
+	}
+	switch *stickinessType {
+	case StickinessTypeLBCookie:
+		attributes = append(attributes, tgAttribute("stickiness.type", "lb_cookie"))
+		if durationSeconds != nil {
+			attributes = append(attributes, tgAttribute("stickiness.lb_cookie.duration_seconds", fmt.Sprintf("%v", *durationSeconds)))
+		}
+	case StickinessTypeAppCookie:
+		if cookieName == nil {
+			return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("the annotation stickiness-app-cookie-name is required when stickiness-type is %v", StickinessTypeAppCookie))
+		}
+		attributes = append(attributes, tgAttribute("stickiness.type", "app_cookie"), tgAttribute("stickiness.app_cookie.cookie_name", *cookieName))
+		if durationSeconds != nil {
+			attributes = append(attributes, tgAttribute("stickiness.app_cookie.duration_seconds", fmt.Sprintf("%v", *durationSeconds)))
+		}
+	default:
+		return nil, errors.NewInvalidAnnotationContent("stickiness-type", *stickinessType)
+	}
+	return attributes, nil
+}
+
+func parseStickinessDurationSeconds(name string, ing parser.AnnotationInterface) (*int64, error) {
+	durationSeconds, err := parser.GetInt64Annotation(name, ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if *durationSeconds < MinStickinessDurationSeconds || *durationSeconds > MaxStickinessDurationSeconds {
+		return nil, errors.NewInvalidAnnotationContent(name, *durationSeconds)
+	}
+	return durationSeconds, nil
+}
+
+// parsePathStickinessDurationSeconds parses the stickiness durations of the annotation name bound to paths, like `name.public`, keyed by path name.
+func parsePathStickinessDurationSeconds(name string, ing parser.AnnotationInterface) (map[string]int64, error) {
+	prefix := parser.GetAnnotationWithPrefix(name) + "."
+	var durations map[string]int64
+	for key := range ing.GetAnnotations() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		pathName := strings.TrimPrefix(key, prefix)
+		durationSeconds, err := parseStickinessDurationSeconds(name+"."+pathName, ing)
+		if err != nil {
+			return nil, err
+		}
+		if durations == nil {
+			durations = make(map[string]int64)
+		}
+		durations[pathName] = *durationSeconds
+	}
+	return durations, nil
+}
+
+// parseTargetNodeSelector parses the label selector of nodes to register as instance targets, like `node-pool=ingress`.
+func parseTargetNodeSelector(ing parser.AnnotationInterface) (labels.Selector, error) {
+	rawSelector, err := parser.GetStringAnnotation("target-node-labels", ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	selector, err := labels.Parse(*rawSelector)
+	if err != nil {
+		return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("the annotation target-node-labels is not a valid label selector: %v", err))
+	}
+	return selector, nil
+}
+
+func tgAttribute(key, value string) *elbv2.TargetGroupAttribute {
+	return &elbv2.TargetGroupAttribute{
+		Key:   aws.String(key),
+		Value: aws.String(value),
+	}
+}
+
 func Dummy() *Config {
 	return &Config{
 		BackendProtocol:         aws.String(elbv2.ProtocolEnumHttp),
+		ProtocolVersion:         aws.String(DefaultProtocolVersion),
 		HealthyThresholdCount:   aws.Int64(2),
 		SuccessCodes:            aws.String("200"),
 		TargetType:              aws.String(elbv2.TargetTypeEnumInstance),
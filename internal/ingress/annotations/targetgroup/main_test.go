@@ -6,7 +6,10 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
 	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMerge(t *testing.T) {
@@ -104,3 +107,307 @@ func TestMerge(t *testing.T) {
 		assert.Equal(t, tc.ExpectedResult, actualResult)
 	}
 }
+
+func TestParseStickiness(t *testing.T) {
+	for _, tc := range []struct {
+		name                                  string
+		annotations                           map[string]string
+		expectedAttributes                    []*elbv2.TargetGroupAttribute
+		expectedRuleStickinessDurationSeconds *int64
+		expectedPathRuleStickiness            map[string]int64
+		expectedErr                           string
+	}{
+		{
+			name:        "no stickiness annotations",
+			annotations: map[string]string{},
+		},
+		{
+			name: "lb-cookie stickiness",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/stickiness-type":             "lb-cookie",
+				"alb.ingress.kubernetes.io/stickiness-duration-seconds": "3600",
+			},
+			expectedAttributes: []*elbv2.TargetGroupAttribute{
+				tgAttribute("stickiness.enabled", "true"),
+				tgAttribute("stickiness.type", "lb_cookie"),
+				tgAttribute("stickiness.lb_cookie.duration_seconds", "3600"),
+			},
+		},
+		{
+			name: "app-cookie stickiness takes precedence over target-group-attributes",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/target-group-attributes":    "stickiness.enabled=false,deregistration_delay.timeout_seconds=30",
+				"alb.ingress.kubernetes.io/stickiness-type":            "app-cookie",
+				"alb.ingress.kubernetes.io/stickiness-app-cookie-name": "session",
+			},
+			expectedAttributes: []*elbv2.TargetGroupAttribute{
+				tgAttribute("stickiness.enabled", "false"),
+				tgAttribute("deregistration_delay.timeout_seconds", "30"),
+				tgAttribute("stickiness.enabled", "true"),
+				tgAttribute("stickiness.type", "app_cookie"),
+				tgAttribute("stickiness.app_cookie.cookie_name", "session"),
+			},
+		},
+		{
+			name: "app-cookie stickiness without cookie name",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/stickiness-type": "app-cookie",
+			},
+			expectedErr: "the annotation stickiness-app-cookie-name is required when stickiness-type is app-cookie",
+		},
+		{
+			name: "unknown stickiness type",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/stickiness-type": "source-ip",
+			},
+			expectedErr: "the annotation stickiness-type does not contain a valid value (source-ip)",
+		},
+		{
+			name: "stickiness duration out of range",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/stickiness-type":             "lb-cookie",
+				"alb.ingress.kubernetes.io/stickiness-duration-seconds": "604801",
+			},
+			expectedErr: "the annotation stickiness-duration-seconds does not contain a valid value (604801)",
+		},
+		{
+			name: "rule stickiness",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/rule-stickiness-duration-seconds": "300",
+			},
+			expectedRuleStickinessDurationSeconds: aws.Int64(300),
+		},
+		{
+			name: "rule stickiness duration out of range",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/rule-stickiness-duration-seconds": "0",
+			},
+			expectedErr: "the annotation rule-stickiness-duration-seconds does not contain a valid value (0)",
+		},
+		{
+			name: "rule stickiness bound to paths",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/rule-stickiness-duration-seconds":          "300",
+				"alb.ingress.kubernetes.io/rule-stickiness-duration-seconds.checkout": "60",
+			},
+			expectedRuleStickinessDurationSeconds: aws.Int64(300),
+			expectedPathRuleStickiness:            map[string]int64{"checkout": 60},
+		},
+		{
+			name: "rule stickiness bound to a path out of range",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/rule-stickiness-duration-seconds.checkout": "604801",
+			},
+			expectedErr: "the annotation rule-stickiness-duration-seconds.checkout does not contain a valid value (604801)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{
+				"alb.ingress.kubernetes.io/target-type": elbv2.TargetTypeEnumInstance,
+			}
+			for k, v := range tc.annotations {
+				annotations[k] = v
+			}
+			ing := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+			}
+
+			raw, err := NewParser(resolver.Mock{}).Parse(ing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			cfg := raw.(*Config)
+			assert.Equal(t, tc.expectedAttributes, cfg.Attributes)
+			assert.Equal(t, tc.expectedRuleStickinessDurationSeconds, cfg.RuleStickinessDurationSeconds)
+			assert.Equal(t, tc.expectedPathRuleStickiness, cfg.PathRuleStickinessDurationSeconds)
+		})
+	}
+}
+
+func TestParseTargetNodeSelector(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		annotations      map[string]string
+		expectedSelector string
+		expectedErr      string
+	}{
+		{
+			name:        "no target-node-labels annotation",
+			annotations: map[string]string{},
+		},
+		{
+			name: "target-node-labels annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/target-node-labels": "node-pool=ingress,zone in (a,b)",
+			},
+			expectedSelector: "node-pool=ingress,zone in (a,b)",
+		},
+		{
+			name: "invalid target-node-labels annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/target-node-labels": "node-pool=a=b",
+			},
+			expectedErr: "the annotation target-node-labels is not a valid label selector: found '=', expected: ',' or 'end of string'",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{
+				"alb.ingress.kubernetes.io/target-type": elbv2.TargetTypeEnumInstance,
+			}
+			for k, v := range tc.annotations {
+				annotations[k] = v
+			}
+			ing := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+			}
+
+			raw, err := NewParser(resolver.Mock{}).Parse(ing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			cfg := raw.(*Config)
+			if tc.expectedSelector == "" {
+				assert.Nil(t, cfg.TargetNodeSelector)
+			} else {
+				assert.Equal(t, tc.expectedSelector, cfg.TargetNodeSelector.String())
+			}
+		})
+	}
+}
+
+func TestParseProtocolVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name                    string
+		annotations             map[string]string
+		expectedProtocolVersion string
+		expectedSuccessCodes    string
+		expectedErr             string
+	}{
+		{
+			name:                    "no backend-protocol-version annotation",
+			annotations:             map[string]string{},
+			expectedProtocolVersion: ProtocolVersionHTTP1,
+		},
+		{
+			name: "HTTP2 backend-protocol-version annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/backend-protocol-version": "HTTP2",
+			},
+			expectedProtocolVersion: ProtocolVersionHTTP2,
+		},
+		{
+			name: "GRPC backend-protocol-version annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/backend-protocol-version": "GRPC",
+			},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+		},
+		{
+			name: "GRPC backend-protocol-version annotation with success-codes annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/backend-protocol-version": "GRPC",
+				"alb.ingress.kubernetes.io/success-codes":            "0-5",
+			},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+			expectedSuccessCodes:    "0-5",
+		},
+		{
+			name: "invalid backend-protocol-version annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/backend-protocol-version": "HTTP3",
+			},
+			expectedErr: "the annotation backend-protocol-version does not contain a valid value (HTTP3)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{
+				"alb.ingress.kubernetes.io/target-type": elbv2.TargetTypeEnumInstance,
+			}
+			for k, v := range tc.annotations {
+				annotations[k] = v
+			}
+			ing := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+			}
+
+			raw, err := NewParser(resolver.Mock{}).Parse(ing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			cfg := raw.(*Config)
+			assert.Equal(t, tc.expectedProtocolVersion, aws.StringValue(cfg.ProtocolVersion))
+			assert.Equal(t, tc.expectedSuccessCodes, aws.StringValue(cfg.SuccessCodes))
+		})
+	}
+}
+
+func TestMergeProtocolVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name                    string
+		service                 *Config
+		ingress                 *Config
+		expectedProtocolVersion string
+		expectedSuccessCodes    string
+	}{
+		{
+			name:                    "GRPC on service",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionGRPC)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1)},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+			expectedSuccessCodes:    DefaultGRPCSuccessCodes,
+		},
+		{
+			name:                    "GRPC on ingress",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionGRPC)},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+			expectedSuccessCodes:    DefaultGRPCSuccessCodes,
+		},
+		{
+			name:                    "GRPC on service and success codes on ingress",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionGRPC)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1), SuccessCodes: aws.String("0-5")},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+			expectedSuccessCodes:    "0-5",
+		},
+		{
+			name:                    "HTTP2 on service and GRPC on ingress",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP2)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionGRPC)},
+			expectedProtocolVersion: ProtocolVersionHTTP2,
+			expectedSuccessCodes:    DefaultSuccessCodes,
+		},
+		{
+			name:                    "GRPC on service and HTTP success codes on service",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionGRPC), SuccessCodes: aws.String(DefaultSuccessCodes)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1)},
+			expectedProtocolVersion: ProtocolVersionGRPC,
+			expectedSuccessCodes:    DefaultSuccessCodes,
+		},
+		{
+			name:                    "HTTP1 on service and gRPC success codes on ingress",
+			service:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1)},
+			ingress:                 &Config{ProtocolVersion: aws.String(ProtocolVersionHTTP1), SuccessCodes: aws.String(DefaultGRPCSuccessCodes)},
+			expectedProtocolVersion: ProtocolVersionHTTP1,
+			expectedSuccessCodes:    DefaultGRPCSuccessCodes,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.service.Merge(tc.ingress, &config.Configuration{DefaultTargetType: "instance"})
+			assert.Equal(t, tc.expectedProtocolVersion, aws.StringValue(cfg.ProtocolVersion))
+			assert.Equal(t, tc.expectedSuccessCodes, aws.StringValue(cfg.SuccessCodes))
+		})
+	}
+}
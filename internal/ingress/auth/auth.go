@@ -2,18 +2,22 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"unicode"
 
-	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -28,6 +32,7 @@ const (
 	AnnotationAuthOnUnauthenticatedRequest string = "auth-on-unauthenticated-request"
 	AnnotationAuthIDPCognito               string = "auth-idp-cognito"
 	AnnotationAuthIDPOIDC                  string = "auth-idp-oidc"
+	AnnotationAuthProfile                  string = "auth-profile"
 )
 
 const (
@@ -38,26 +43,54 @@ const (
 	DefaultAuthOnUnauthenticatedRequest = OnUnauthenticatedRequestAuthenticate
 )
 
-const FieldAuthOIDCSecret = "authOIDCSecret"
+// authAnnotations are the auth annotations that can be configured in an auth profile, or bound to a path of ingress
+// by suffixing them with its name, like `auth-type.public`.
+var authAnnotations = []string{
+	AnnotationAuthType,
+	AnnotationAuthScope,
+	AnnotationAuthSessionCookie,
+	AnnotationAuthSessionTimeout,
+	AnnotationAuthOnUnauthenticatedRequest,
+	AnnotationAuthIDPCognito,
+	AnnotationAuthIDPOIDC,
+}
+
+const (
+	FieldAuthOIDCSecret    = "authOIDCSecret"
+	FieldAuthOIDCSecretArn = "authOIDCSecretArn"
+	FieldAuthOIDCIssuer    = "authOIDCIssuer"
+	FieldAuthProfile       = "authProfile"
+)
 
 // Authentication module interface
 type Module interface {
 	// Init setup index & watch functionality.
 	Init(controller controller.Controller, ingressChan chan<- event.GenericEvent, serviceChan chan<- event.GenericEvent) error
 
-	// NewConfig builds authentication config for ingress & ingressBackend.
-	NewConfig(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, protocol string) (Config, error)
+	// NewConfig builds authentication config for the path named pathName of ingress, which routes to ingressBackend.
+	// pathName is empty for the default backend of ingress.
+	NewConfig(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, pathName string) (Config, error)
 }
 
 // NewModule constructs new Authentication module
-func NewModule(cache cache.Cache) Module {
+func NewModule(cache cache.Cache, cloud aws.CloudAPI, oidcDiscoverer OIDCDiscoverer, awsSecretResolver AWSSecretResolver) Module {
 	return &defaultModule{
-		cache: cache,
+		cache:             cache,
+		cloud:             cloud,
+		oidcDiscoverer:    oidcDiscoverer,
+		awsSecretResolver: awsSecretResolver,
+		cognitoCache:      utilcache.NewLRUExpireCache(cognitoCacheMaxSize),
 	}
 }
 
 type defaultModule struct {
-	cache cache.Cache
+	cache             cache.Cache
+	cloud             aws.CloudAPI
+	oidcDiscoverer    OIDCDiscoverer
+	awsSecretResolver AWSSecretResolver
+
+	// cache of the Cognito user pools and clients resolved by name, see getUserPoolByName and getUserPoolClient.
+	cognitoCache *utilcache.LRUExpireCache
 }
 
 func (m *defaultModule) Init(controller controller.Controller, ingressChan chan<- event.GenericEvent, serviceChan chan<- event.GenericEvent) error {
@@ -73,6 +106,61 @@ func (m *defaultModule) Init(controller controller.Controller, ingressChan chan<
 	}); err != nil {
 		return err
 	}
+	if err := m.cache.IndexField(&extensions.Ingress{}, FieldAuthOIDCSecretArn, func(obj runtime.Object) []string {
+		ingress := obj.(*extensions.Ingress)
+		return buildOIDCSecretArnIndex(ingress.Annotations)
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&corev1.Service{}, FieldAuthOIDCSecretArn, func(obj runtime.Object) []string {
+		service := obj.(*corev1.Service)
+		return buildOIDCSecretArnIndex(service.Annotations)
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&extensions.Ingress{}, FieldAuthOIDCIssuer, func(obj runtime.Object) []string {
+		ingress := obj.(*extensions.Ingress)
+		return buildOIDCIssuerIndex(ingress.Annotations)
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&corev1.Service{}, FieldAuthOIDCIssuer, func(obj runtime.Object) []string {
+		service := obj.(*corev1.Service)
+		return buildOIDCIssuerIndex(service.Annotations)
+	}); err != nil {
+		return err
+	}
+
+	if err := m.cache.IndexField(&corev1.ConfigMap{}, FieldAuthOIDCSecret, func(obj runtime.Object) []string {
+		configMap := obj.(*corev1.ConfigMap)
+		return buildOIDCSecretIndex(configMap.Namespace, buildAuthProfileAnnotations(configMap))
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&corev1.ConfigMap{}, FieldAuthOIDCSecretArn, func(obj runtime.Object) []string {
+		configMap := obj.(*corev1.ConfigMap)
+		return buildOIDCSecretArnIndex(buildAuthProfileAnnotations(configMap))
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&corev1.ConfigMap{}, FieldAuthOIDCIssuer, func(obj runtime.Object) []string {
+		configMap := obj.(*corev1.ConfigMap)
+		return buildOIDCIssuerIndex(buildAuthProfileAnnotations(configMap))
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&extensions.Ingress{}, FieldAuthProfile, func(obj runtime.Object) []string {
+		ingress := obj.(*extensions.Ingress)
+		return buildAuthProfileIndex(ingress.Namespace, ingress.Annotations)
+	}); err != nil {
+		return err
+	}
+	if err := m.cache.IndexField(&corev1.Service{}, FieldAuthProfile, func(obj runtime.Object) []string {
+		service := obj.(*corev1.Service)
+		return buildAuthProfileIndex(service.Namespace, service.Annotations)
+	}); err != nil {
+		return err
+	}
 
 	if err := controller.Watch(&source.Kind{Type: &corev1.Secret{}}, &EnqueueRequestsForSecretEvent{
 		IngressChan: ingressChan,
@@ -81,17 +169,23 @@ func (m *defaultModule) Init(controller controller.Controller, ingressChan chan<
 	}); err != nil {
 		return err
 	}
+	m.awsSecretResolver.OnChange(func(secretArn string) {
+		enqueueImpactedObjects(m.cache, FieldAuthOIDCSecretArn, secretArn, ingressChan, serviceChan)
+	})
+	m.awsSecretResolver.InUse(func(secretArn string) bool {
+		return isReferenced(m.cache, FieldAuthOIDCSecretArn, secretArn)
+	})
+	m.oidcDiscoverer.OnChange(func(issuer string) {
+		enqueueImpactedObjects(m.cache, FieldAuthOIDCIssuer, issuer, ingressChan, serviceChan)
+	})
+	m.oidcDiscoverer.InUse(func(issuer string) bool {
+		return isReferenced(m.cache, FieldAuthOIDCIssuer, issuer)
+	})
 
 	return nil
 }
 
-func (m *defaultModule) NewConfig(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, protocol string) (Config, error) {
-	if protocol != elbv2.ProtocolEnumHttps {
-		return Config{
-			Type: TypeNone,
-		}, nil
-	}
-
+func (m *defaultModule) NewConfig(ctx context.Context, ingress *extensions.Ingress, backend extensions.IngressBackend, pathName string) (Config, error) {
 	cfg := Config{
 		Type:                     DefaultAuthType,
 		OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
@@ -113,17 +207,37 @@ func (m *defaultModule) NewConfig(ctx context.Context, ingress *extensions.Ingre
 		}
 		serviceAnnos = service.Annotations
 	}
-	_ = annotations.LoadStringAnnotation(AnnotationAuthType, (*string)(&cfg.Type), serviceAnnos, ingressAnnos)
-	_ = annotations.LoadStringAnnotation(AnnotationAuthOnUnauthenticatedRequest, (*string)(&cfg.OnUnauthenticatedRequest), serviceAnnos, ingressAnnos)
-	_ = annotations.LoadStringAnnotation(AnnotationAuthScope, &cfg.Scope, serviceAnnos, ingressAnnos)
-	_ = annotations.LoadStringAnnotation(AnnotationAuthSessionCookie, &cfg.SessionCookie, serviceAnnos, ingressAnnos)
-	if _, err := annotations.LoadInt64Annotation(AnnotationAuthSessionTimeout, &cfg.SessionTimeout, serviceAnnos, ingressAnnos); err != nil {
+	ingressProfile, err := m.loadAuthProfile(ctx, ingress.Namespace, ingressAnnos)
+	if err != nil {
+		return Config{}, err
+	}
+	serviceProfile, err := m.loadAuthProfile(ctx, ingress.Namespace, serviceAnnos)
+	if err != nil {
+		return Config{}, err
+	}
+	sources := []annotationSource{
+		{annotations: buildPathAnnotations(ingressAnnos, pathName), namespace: ingress.Namespace},
+		{annotations: serviceAnnos, namespace: ingress.Namespace},
+		serviceProfile,
+		{annotations: ingressAnnos, namespace: ingress.Namespace},
+		ingressProfile,
+	}
+	annos := make([]map[string]string, 0, len(sources))
+	for _, annoSource := range sources {
+		annos = append(annos, annoSource.annotations)
+	}
+
+	_ = annotations.LoadStringAnnotation(AnnotationAuthType, (*string)(&cfg.Type), annos...)
+	_ = annotations.LoadStringAnnotation(AnnotationAuthOnUnauthenticatedRequest, (*string)(&cfg.OnUnauthenticatedRequest), annos...)
+	_ = annotations.LoadStringAnnotation(AnnotationAuthScope, &cfg.Scope, annos...)
+	_ = annotations.LoadStringAnnotation(AnnotationAuthSessionCookie, &cfg.SessionCookie, annos...)
+	if _, err := annotations.LoadInt64Annotation(AnnotationAuthSessionTimeout, &cfg.SessionTimeout, annos...); err != nil {
 		return Config{}, err
 	}
 	switch cfg.Type {
 	case TypeCognito:
 		{
-			exists, err := annotations.LoadJSONAnnotation(AnnotationAuthIDPCognito, &cfg.IDPCognito, serviceAnnos, ingressAnnos)
+			exists, err := m.loadIDPCognito(ctx, &cfg.IDPCognito, ingress, pathName, annos)
 			if err != nil {
 				return Config{}, err
 			}
@@ -133,7 +247,7 @@ func (m *defaultModule) NewConfig(ctx context.Context, ingress *extensions.Ingre
 		}
 	case TypeOIDC:
 		{
-			exists, err := m.loadIDPOIDC(ctx, &cfg.IDPOIDC, ingress.Namespace, serviceAnnos, ingressAnnos)
+			exists, err := m.loadIDPOIDC(ctx, &cfg.IDPOIDC, lookupNamespace(sources, AnnotationAuthIDPOIDC), annos)
 			if err != nil {
 				return Config{}, err
 			}
@@ -146,9 +260,10 @@ func (m *defaultModule) NewConfig(ctx context.Context, ingress *extensions.Ingre
 	return cfg, nil
 }
 
-func (m *defaultModule) loadIDPOIDC(ctx context.Context, idpOIDC *IDPOIDC, namespace string, serviceAnnos map[string]string, ingressAnnos map[string]string) (bool, error) {
+// loadIDPOIDC loads IDP OIDC from annos by priority, whose k8s secret is in namespace.
+func (m *defaultModule) loadIDPOIDC(ctx context.Context, idpOIDC *IDPOIDC, namespace string, annos []map[string]string) (bool, error) {
 	annoIDPOIDC := AnnotationSchemaIDPOIDC{}
-	exists, err := annotations.LoadJSONAnnotation(AnnotationAuthIDPOIDC, &annoIDPOIDC, serviceAnnos, ingressAnnos)
+	exists, err := annotations.LoadJSONAnnotation(AnnotationAuthIDPOIDC, &annoIDPOIDC, annos...)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to load configuration for IDP OIDC")
 	}
@@ -156,16 +271,45 @@ func (m *defaultModule) loadIDPOIDC(ctx context.Context, idpOIDC *IDPOIDC, names
 		return false, nil
 	}
 
-	secretKey := types.NamespacedName{
-		Namespace: namespace,
-		Name:      annoIDPOIDC.SecretName,
+	var clientId, clientSecret string
+	if annoIDPOIDC.SecretArn != "" {
+		if annoIDPOIDC.SecretName != "" {
+			return true, errors.New("only one of SecretName and SecretArn can be specified for IDP OIDC")
+		}
+		credentials, err := m.awsSecretResolver.Resolve(ctx, annoIDPOIDC.SecretArn)
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to load client credentials for IDP OIDC")
+		}
+		clientId, clientSecret = credentials.ClientId, credentials.ClientSecret
+	} else {
+		secretKey := types.NamespacedName{
+			Namespace: namespace,
+			Name:      annoIDPOIDC.SecretName,
+		}
+		k8sSecret := corev1.Secret{}
+		if err := m.cache.Get(ctx, secretKey, &k8sSecret); err != nil {
+			return true, errors.Wrapf(err, "failed to load k8s secret: %v", secretKey)
+		}
+		clientId = strings.TrimRightFunc(string(k8sSecret.Data["clientId"]), unicode.IsSpace)
+		clientSecret = string(k8sSecret.Data["clientSecret"])
 	}
-	k8sSecret := corev1.Secret{}
-	if err := m.cache.Get(ctx, secretKey, &k8sSecret); err != nil {
-		return true, errors.Wrapf(err, "failed to load k8s secret: %v", secretKey)
+
+	// endpoints omitted from the annotation are discovered from the issuer
+	if annoIDPOIDC.needsDiscovery() {
+		metadata, err := m.oidcDiscoverer.Discover(ctx, annoIDPOIDC.Issuer)
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to discover configuration for IDP OIDC")
+		}
+		if annoIDPOIDC.AuthorizationEndpoint == "" {
+			annoIDPOIDC.AuthorizationEndpoint = metadata.AuthorizationEndpoint
+		}
+		if annoIDPOIDC.TokenEndpoint == "" {
+			annoIDPOIDC.TokenEndpoint = metadata.TokenEndpoint
+		}
+		if annoIDPOIDC.UserInfoEndpoint == "" {
+			annoIDPOIDC.UserInfoEndpoint = metadata.UserInfoEndpoint
+		}
 	}
-	clientId := strings.TrimRightFunc(string(k8sSecret.Data["clientId"]), unicode.IsSpace)
-	clientSecret := string(k8sSecret.Data["clientSecret"])
 	*idpOIDC = IDPOIDC{
 		AuthenticationRequestExtraParams: annoIDPOIDC.AuthenticationRequestExtraParams,
 		AuthorizationEndpoint:            annoIDPOIDC.AuthorizationEndpoint,
@@ -178,16 +322,74 @@ func (m *defaultModule) loadIDPOIDC(ctx context.Context, idpOIDC *IDPOIDC, names
 	return true, nil
 }
 
-func buildOIDCSecretIndex(namespace string, annos map[string]string) []string {
-	annoIDPOIDC := AnnotationSchemaIDPOIDC{}
-	exists, err := annotations.LoadJSONAnnotation(AnnotationAuthIDPOIDC, &annoIDPOIDC, annos)
-	if !exists || err != nil {
+// buildPathAnnotations returns the auth annotations of ingress bound to the path named pathName, like
+// `auth-type.public`, keyed by the annotation they override.
+func buildPathAnnotations(ingressAnnos map[string]string, pathName string) map[string]string {
+	if pathName == "" {
 		return nil
 	}
+	pathAnnos := make(map[string]string)
+	for _, annotation := range authAnnotations {
+		if value, ok := ingressAnnos[parser.GetAnnotationWithPrefix(fmt.Sprintf("%v.%v", annotation, pathName))]; ok {
+			pathAnnos[parser.GetAnnotationWithPrefix(annotation)] = value
+		}
+	}
+	return pathAnnos
+}
+
+// loadIDPOIDCAnnotations loads the IDP OIDC annotation and the ones bound to paths, skipping invalid ones.
+func loadIDPOIDCAnnotations(annos map[string]string) []AnnotationSchemaIDPOIDC {
+	key := parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC)
+	var keys []string
+	for k := range annos {
+		if k == key || strings.HasPrefix(k, key+".") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var result []AnnotationSchemaIDPOIDC
+	for _, k := range keys {
+		annoIDPOIDC := AnnotationSchemaIDPOIDC{}
+		if err := json.Unmarshal([]byte(annos[k]), &annoIDPOIDC); err != nil {
+			continue
+		}
+		result = append(result, annoIDPOIDC)
+	}
+	return result
+}
+
+func buildOIDCSecretIndex(namespace string, annos map[string]string) []string {
+	var secretKeys []string
+	for _, annoIDPOIDC := range loadIDPOIDCAnnotations(annos) {
+		if annoIDPOIDC.SecretName == "" {
+			continue
+		}
+		secretKey := types.NamespacedName{
+			Namespace: namespace,
+			Name:      annoIDPOIDC.SecretName,
+		}.String()
+		secretKeys = append(secretKeys, secretKey)
+	}
+	return secretKeys
+}
+
+func buildOIDCIssuerIndex(annos map[string]string) []string {
+	var issuers []string
+	for _, annoIDPOIDC := range loadIDPOIDCAnnotations(annos) {
+		if annoIDPOIDC.needsDiscovery() {
+			issuers = append(issuers, annoIDPOIDC.Issuer)
+		}
+	}
+	return issuers
+}
 
-	secretKey := types.NamespacedName{
-		Namespace: namespace,
-		Name:      annoIDPOIDC.SecretName,
-	}.String()
-	return []string{secretKey}
+func buildOIDCSecretArnIndex(annos map[string]string) []string {
+	var secretArns []string
+	for _, annoIDPOIDC := range loadIDPOIDCAnnotations(annos) {
+		if annoIDPOIDC.SecretArn != "" {
+			secretArns = append(secretArns, annoIDPOIDC.SecretArn)
+		}
+	}
+	return secretArns
 }
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// annotationSource is a set of auth annotations, along with the namespace of the k8s secrets they refer to.
+type annotationSource struct {
+	annotations map[string]string
+	namespace   string
+}
+
+// lookupNamespace returns the namespace of the first source that configures annotation.
+func lookupNamespace(sources []annotationSource, annotation string) string {
+	key := parser.GetAnnotationWithPrefix(annotation)
+	for _, annoSource := range sources {
+		if _, ok := annoSource.annotations[key]; ok {
+			return annoSource.namespace
+		}
+	}
+	return ""
+}
+
+// AnnotationAuthProfileAllowedNamespaces is the annotation of an auth profile listing the other namespaces that may reference it,
+// or `*` for any namespace.
+const AnnotationAuthProfileAllowedNamespaces = "auth-profile-allowed-namespaces"
+
+// loadAuthProfile loads the auth profile referenced from annos of an object in namespace.
+// An auth profile is a ConfigMap whose data holds auth annotations without prefix, like `auth-type: oidc`,
+// it's referenced by `name` in the same namespace or by `namespace/name`, when the auth profile allows namespace.
+func (m *defaultModule) loadAuthProfile(ctx context.Context, namespace string, annos map[string]string) (annotationSource, error) {
+	var ref string
+	if !annotations.LoadStringAnnotation(AnnotationAuthProfile, &ref, annos) {
+		return annotationSource{}, nil
+	}
+	profileKey := authProfileKey(namespace, ref)
+	configMap := corev1.ConfigMap{}
+	if err := m.cache.Get(ctx, profileKey, &configMap); err != nil {
+		return annotationSource{}, errors.Wrapf(err, "failed to load auth profile %v", profileKey)
+	}
+	if !authProfileAllowsNamespace(&configMap, namespace) {
+		return annotationSource{}, errors.Errorf("auth profile %v doesn't allow namespace %v, it must be listed in annotation %v of the auth profile",
+			profileKey, namespace, parser.GetAnnotationWithPrefix(AnnotationAuthProfileAllowedNamespaces))
+	}
+	return annotationSource{
+		annotations: buildAuthProfileAnnotations(&configMap),
+		namespace:   configMap.Namespace,
+	}, nil
+}
+
+// authProfileKey returns the key of auth profile referenced by ref from an object in namespace.
+func authProfileKey(namespace string, ref string) types.NamespacedName {
+	if parts := strings.SplitN(ref, "/", 2); len(parts) == 2 {
+		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref}
+}
+
+// authProfileAllowsNamespace tells whether objects in namespace may use the auth profile configMap.
+// Objects in the namespace of the auth profile always may, as the secrets it refers to are in their namespace as well.
+func authProfileAllowsNamespace(configMap *corev1.ConfigMap, namespace string) bool {
+	if configMap.Namespace == namespace {
+		return true
+	}
+	var allowedNamespaces []string
+	_ = annotations.LoadStringSliceAnnotation(AnnotationAuthProfileAllowedNamespaces, &allowedNamespaces, configMap.Annotations)
+	for _, allowedNamespace := range allowedNamespaces {
+		if allowedNamespace == "*" || allowedNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAuthProfileAnnotations returns the auth annotations configured in the data of configMap, keyed by their prefixed name.
+func buildAuthProfileAnnotations(configMap *corev1.ConfigMap) map[string]string {
+	profileAnnos := make(map[string]string)
+	for _, annotation := range authAnnotations {
+		if value, ok := configMap.Data[annotation]; ok {
+			profileAnnos[parser.GetAnnotationWithPrefix(annotation)] = value
+		}
+	}
+	return profileAnnos
+}
+
+func buildAuthProfileIndex(namespace string, annos map[string]string) []string {
+	var ref string
+	if !annotations.LoadStringAnnotation(AnnotationAuthProfile, &ref, annos) {
+		return nil
+	}
+	return []string{authProfileKey(namespace, ref).String()}
+}
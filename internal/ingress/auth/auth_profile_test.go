@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDefaultModule_NewConfig_AuthProfile(t *testing.T) {
+	profile := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "auth",
+			Name:      "profile",
+			Annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfileAllowedNamespaces): "team-a, namespace",
+			},
+		},
+		Data: map[string]string{
+			AnnotationAuthType:          "oidc",
+			AnnotationAuthScope:         "email",
+			AnnotationAuthSessionCookie: "profile-cookie",
+			AnnotationAuthIDPOIDC:       `{"Issuer":"https://example.com","AuthorizationEndpoint":"https://authorization.example.com","TokenEndpoint":"https://token.example.com","UserInfoEndpoint":"https://userinfo.example.com","SecretName":"secret"}`,
+			"unrelated":                 "value",
+		},
+	}
+	secret := corev1.Secret{
+		Data: map[string][]byte{
+			"clientId":     []byte("clientId"),
+			"clientSecret": []byte("clientSecret"),
+		},
+	}
+
+	for _, tc := range []struct {
+		name              string
+		ingressAnnos      map[string]string
+		serviceAnnos      map[string]string
+		profileErr        error
+		allowedNamespaces string
+		expectedSecretKey types.NamespacedName
+		expectedCfg       Config
+		expectedErr       string
+	}{
+		{
+			name: "ingress refers to profile in another namespace",
+			ingressAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+			},
+			expectedSecretKey: types.NamespacedName{Namespace: "auth", Name: "secret"},
+			expectedCfg: Config{
+				Type: TypeOIDC,
+				IDPOIDC: IDPOIDC{
+					Issuer:                "https://example.com",
+					AuthorizationEndpoint: "https://authorization.example.com",
+					TokenEndpoint:         "https://token.example.com",
+					UserInfoEndpoint:      "https://userinfo.example.com",
+					ClientId:              "clientId",
+					ClientSecret:          "clientSecret",
+				},
+				Scope:                    "email",
+				SessionCookie:            "profile-cookie",
+				SessionTimeout:           DefaultAuthSessionTimeout,
+				OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
+			},
+		},
+		{
+			name: "service annotations override profile of service, which overrides ingress annotations",
+			ingressAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthSessionCookie):  "ingress-cookie",
+				parser.GetAnnotationWithPrefix(AnnotationAuthSessionTimeout): "60",
+			},
+			serviceAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+				parser.GetAnnotationWithPrefix(AnnotationAuthScope):   "openid",
+			},
+			expectedSecretKey: types.NamespacedName{Namespace: "auth", Name: "secret"},
+			expectedCfg: Config{
+				Type: TypeOIDC,
+				IDPOIDC: IDPOIDC{
+					Issuer:                "https://example.com",
+					AuthorizationEndpoint: "https://authorization.example.com",
+					TokenEndpoint:         "https://token.example.com",
+					UserInfoEndpoint:      "https://userinfo.example.com",
+					ClientId:              "clientId",
+					ClientSecret:          "clientSecret",
+				},
+				Scope:                    "openid",
+				SessionCookie:            "profile-cookie",
+				SessionTimeout:           60,
+				OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
+			},
+		},
+		{
+			name: "profile doesn't allow namespace of ingress",
+			ingressAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+			},
+			allowedNamespaces: "team-a",
+			expectedErr:       "auth profile auth/profile doesn't allow namespace namespace, it must be listed in annotation alb.ingress.kubernetes.io/auth-profile-allowed-namespaces of the auth profile",
+		},
+		{
+			name: "profile allows any namespace",
+			ingressAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+			},
+			allowedNamespaces: "*",
+			expectedSecretKey: types.NamespacedName{Namespace: "auth", Name: "secret"},
+			expectedCfg: Config{
+				Type: TypeOIDC,
+				IDPOIDC: IDPOIDC{
+					Issuer:                "https://example.com",
+					AuthorizationEndpoint: "https://authorization.example.com",
+					TokenEndpoint:         "https://token.example.com",
+					UserInfoEndpoint:      "https://userinfo.example.com",
+					ClientId:              "clientId",
+					ClientSecret:          "clientSecret",
+				},
+				Scope:                    "email",
+				SessionCookie:            "profile-cookie",
+				SessionTimeout:           DefaultAuthSessionTimeout,
+				OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
+			},
+		},
+		{
+			name: "profile doesn't exist",
+			ingressAnnos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+			},
+			profileErr:  errors.New("not found"),
+			expectedErr: "failed to load auth profile auth/profile: not found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "service"}, gomock.Any()).
+				SetArg(2, corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.serviceAnnos}})
+			if tc.profileErr != nil {
+				mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "auth", Name: "profile"}, gomock.Any()).
+					Return(tc.profileErr)
+			} else {
+				profile := *profile.DeepCopy()
+				if tc.allowedNamespaces != "" {
+					profile.Annotations[parser.GetAnnotationWithPrefix(AnnotationAuthProfileAllowedNamespaces)] = tc.allowedNamespaces
+				}
+				mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "auth", Name: "profile"}, gomock.Any()).
+					SetArg(2, profile)
+				if tc.expectedErr == "" {
+					mockCache.EXPECT().Get(gomock.Any(), tc.expectedSecretKey, gomock.Any()).
+						SetArg(2, secret)
+				}
+			}
+			module := &defaultModule{
+				cache: mockCache,
+			}
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "namespace",
+					Name:        "ingress",
+					Annotations: tc.ingressAnnos,
+				},
+			}
+			backend := extensions.IngressBackend{
+				ServiceName: "service",
+				ServicePort: intstr.FromInt(80),
+			}
+
+			var events []string
+			authCfg, err := module.NewConfig(recordEvents(&events), ingress, backend, "")
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCfg, authCfg)
+			}
+		})
+	}
+}
+
+func TestAuthProfileAllowsNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		allowedNamespaces *string
+		namespace         string
+		expected          bool
+	}{
+		{
+			name:      "same namespace",
+			namespace: "auth",
+			expected:  true,
+		},
+		{
+			name:      "another namespace without opt-in",
+			namespace: "team-a",
+			expected:  false,
+		},
+		{
+			name:              "another namespace listed",
+			allowedNamespaces: aws.String("team-b, team-a"),
+			namespace:         "team-a",
+			expected:          true,
+		},
+		{
+			name:              "another namespace not listed",
+			allowedNamespaces: aws.String("team-b"),
+			namespace:         "team-a",
+			expected:          false,
+		},
+		{
+			name:              "any namespace",
+			allowedNamespaces: aws.String("*"),
+			namespace:         "team-a",
+			expected:          true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "auth", Name: "profile"}}
+			if tc.allowedNamespaces != nil {
+				configMap.Annotations = map[string]string{
+					parser.GetAnnotationWithPrefix(AnnotationAuthProfileAllowedNamespaces): *tc.allowedNamespaces,
+				}
+			}
+			assert.Equal(t, tc.expected, authProfileAllowsNamespace(configMap, tc.namespace))
+		})
+	}
+}
+
+func TestBuildAuthProfileIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		annos    map[string]string
+		expected []string
+	}{
+		{
+			name:     "no auth profile",
+			annos:    map[string]string{},
+			expected: nil,
+		},
+		{
+			name: "auth profile in same namespace",
+			annos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "profile",
+			},
+			expected: []string{"namespace/profile"},
+		},
+		{
+			name: "auth profile in another namespace",
+			annos: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthProfile): "auth/profile",
+			},
+			expected: []string{"auth/profile"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildAuthProfileIndex("namespace", tc.annos))
+		})
+	}
+}
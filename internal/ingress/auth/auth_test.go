@@ -2,10 +2,13 @@ package auth
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
 	mock_controller "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/controller"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +30,15 @@ func TestDefaultModule_Init(t *testing.T) {
 	mockCache := mock_cache.NewMockCache(ctrl)
 	mockCache.EXPECT().IndexField(&extensions.Ingress{}, FieldAuthOIDCSecret, gomock.Any())
 	mockCache.EXPECT().IndexField(&corev1.Service{}, FieldAuthOIDCSecret, gomock.Any())
+	mockCache.EXPECT().IndexField(&extensions.Ingress{}, FieldAuthOIDCSecretArn, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.Service{}, FieldAuthOIDCSecretArn, gomock.Any())
+	mockCache.EXPECT().IndexField(&extensions.Ingress{}, FieldAuthOIDCIssuer, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.Service{}, FieldAuthOIDCIssuer, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.ConfigMap{}, FieldAuthOIDCSecret, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.ConfigMap{}, FieldAuthOIDCSecretArn, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.ConfigMap{}, FieldAuthOIDCIssuer, gomock.Any())
+	mockCache.EXPECT().IndexField(&extensions.Ingress{}, FieldAuthProfile, gomock.Any())
+	mockCache.EXPECT().IndexField(&corev1.Service{}, FieldAuthProfile, gomock.Any())
 	mockController := mock_controller.NewMockController(ctrl)
 	mockController.EXPECT().Watch(&source.Kind{Type: &corev1.Secret{}}, &EnqueueRequestsForSecretEvent{
 		IngressChan: ingressChan,
@@ -34,7 +46,11 @@ func TestDefaultModule_Init(t *testing.T) {
 		Cache:       mockCache,
 	})
 
-	module := &defaultModule{mockCache}
+	module := &defaultModule{
+		cache:             mockCache,
+		oidcDiscoverer:    NewOIDCDiscoverer(http.DefaultClient, time.Minute),
+		awsSecretResolver: NewAWSSecretResolver(&mocks.CloudAPI{}, time.Minute, testSecretNamePrefix),
+	}
 	assert.NoError(t, module.Init(mockController, ingressChan, serviceChan))
 }
 
@@ -59,6 +75,16 @@ func TestBuildOIDCSecretIndex(t *testing.T) {
 			},
 			expectedIndexes: []string{"namespace/oidc-secret"},
 		},
+		{
+			name:      "ingress use OIDC auth on paths",
+			namespace: "namespace",
+			annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC):             "{\"SecretName\": \"oidc-secret\"}",
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC + ".public"): "{\"SecretName\": \"path-oidc-secret\"}",
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC + "-other"):  "{\"SecretName\": \"other-secret\"}",
+			},
+			expectedIndexes: []string{"namespace/oidc-secret", "namespace/path-oidc-secret"},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			actualIndexes := buildOIDCSecretIndex(tc.namespace, tc.annotations)
@@ -74,7 +100,7 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 		backend         extensions.IngressBackend
 		service         *corev1.Service
 		secret          *corev1.Secret
-		protocol        string
+		pathName        string
 		expectedAuthCfg Config
 		expectedErr     error
 	}{
@@ -100,7 +126,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					Name:      "service",
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeCognito,
 				IDPCognito: IDPCognito{
@@ -140,7 +165,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					},
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeCognito,
 				IDPCognito: IDPCognito{
@@ -190,7 +214,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					"clientSecret": []byte("clientSecret"),
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeOIDC,
 				IDPOIDC: IDPOIDC{
@@ -243,7 +266,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					"clientSecret": []byte("clientSecret"),
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeOIDC,
 				IDPOIDC: IDPOIDC{
@@ -296,7 +318,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					"clientSecret": []byte("clientSecret"),
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeOIDC,
 				IDPOIDC: IDPOIDC{
@@ -343,7 +364,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					Name:      "service",
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeCognito,
 				IDPCognito: IDPCognito{
@@ -386,7 +406,6 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					},
 				},
 			},
-			protocol: "HTTPS",
 			expectedAuthCfg: Config{
 				Type: TypeCognito,
 				IDPCognito: IDPCognito{
@@ -401,14 +420,15 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 			},
 		},
 		{
-			name: "http don't support auth",
+			name: "path exempted from ingress auth",
 			ingress: &extensions.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "namespace",
 					Name:      "ingress",
 					Annotations: map[string]string{
-						parser.GetAnnotationWithPrefix(AnnotationAuthType):       "cognito",
-						parser.GetAnnotationWithPrefix(AnnotationAuthIDPCognito): "{\"UserPoolArn\": \"UserPoolArn\",\"UserPoolClientId\": \"UserPoolClientId\",\"UserPoolDomain\": \"UserPoolDomain\"}",
+						parser.GetAnnotationWithPrefix(AnnotationAuthType):             "cognito",
+						parser.GetAnnotationWithPrefix(AnnotationAuthIDPCognito):       "{\"UserPoolArn\": \"UserPoolArn\",\"UserPoolClientId\": \"UserPoolClientId\",\"UserPoolDomain\": \"UserPoolDomain\"}",
+						parser.GetAnnotationWithPrefix(AnnotationAuthType + ".public"): "none",
 					},
 				},
 			},
@@ -416,9 +436,61 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 				ServiceName: "service",
 				ServicePort: intstr.FromInt(80),
 			},
-			protocol: "HTTP",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "service",
+				},
+			},
+			pathName: "public",
 			expectedAuthCfg: Config{
-				Type: TypeNone,
+				Type:                     TypeNone,
+				Scope:                    DefaultAuthScope,
+				SessionCookie:            DefaultAuthSessionCookie,
+				SessionTimeout:           DefaultAuthSessionTimeout,
+				OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
+			},
+		},
+		{
+			name: "path auth annotations take precedence over service annotations",
+			ingress: &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "ingress",
+					Annotations: map[string]string{
+						parser.GetAnnotationWithPrefix(AnnotationAuthType):                    "cognito",
+						parser.GetAnnotationWithPrefix(AnnotationAuthIDPCognito):              "{\"UserPoolArn\": \"UserPoolArn\",\"UserPoolClientId\": \"UserPoolClientId\",\"UserPoolDomain\": \"UserPoolDomain\"}",
+						parser.GetAnnotationWithPrefix(AnnotationAuthScope + ".login"):        "email openid",
+						parser.GetAnnotationWithPrefix(AnnotationAuthScope + ".home"):         "profile openid",
+						parser.GetAnnotationWithPrefix(AnnotationAuthSessionTimeout + ".log"): "60",
+					},
+				},
+			},
+			backend: extensions.IngressBackend{
+				ServiceName: "service",
+				ServicePort: intstr.FromInt(80),
+			},
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "service",
+					Annotations: map[string]string{
+						parser.GetAnnotationWithPrefix(AnnotationAuthScope): "openid",
+					},
+				},
+			},
+			pathName: "login",
+			expectedAuthCfg: Config{
+				Type: TypeCognito,
+				IDPCognito: IDPCognito{
+					UserPoolArn:      "UserPoolArn",
+					UserPoolClientId: "UserPoolClientId",
+					UserPoolDomain:   "UserPoolDomain",
+				},
+				Scope:                    "email openid",
+				SessionCookie:            DefaultAuthSessionCookie,
+				SessionTimeout:           DefaultAuthSessionTimeout,
+				OnUnauthenticatedRequest: DefaultAuthOnUnauthenticatedRequest,
 			},
 		},
 	} {
@@ -439,9 +511,9 @@ func TestDefaultModule_NewConfig(t *testing.T) {
 					Name:      tc.secret.Name,
 				}, gomock.Any()).SetArg(2, *tc.secret)
 			}
-			module := &defaultModule{mockCache}
+			module := &defaultModule{cache: mockCache}
 
-			authCfg, err := module.NewConfig(context.Background(), tc.ingress, tc.backend, tc.protocol)
+			authCfg, err := module.NewConfig(context.Background(), tc.ingress, tc.backend, tc.pathName)
 			assert.Equal(t, authCfg, tc.expectedAuthCfg)
 			assert.Equal(t, err, tc.expectedErr)
 		})
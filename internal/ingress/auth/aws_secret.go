@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// ClientCredentials is the OIDC client credentials stored in AWS as JSON like `{"clientId": "xxx", "clientSecret": "xxx"}`
+type ClientCredentials struct {
+	ClientId     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// AWSSecretResolver resolves client credentials stored in a Secrets Manager secret or a SSM SecureString parameter.
+type AWSSecretResolver interface {
+	manager.Runnable
+
+	// Resolve returns the client credentials stored in secretArn, fetching it on first use.
+	Resolve(ctx context.Context, secretArn string) (ClientCredentials, error)
+
+	// OnChange registers a function to be invoked with the secretArn whenever a periodic refresh detects a rotation
+	// or fails.
+	OnChange(fn func(secretArn string))
+
+	// InUse registers a function that tells whether a secretArn is still used, the credentials of secrets no longer
+	// used are evicted on refresh.
+	InUse(fn func(secretArn string) bool)
+}
+
+// NewAWSSecretResolver constructs new AWSSecretResolver that checks the resolved secrets for rotation every refreshInterval.
+// Only secrets and parameters whose name starts with namePrefix can be resolved, an empty namePrefix allows any name.
+func NewAWSSecretResolver(cloud aws.CloudAPI, refreshInterval time.Duration, namePrefix string) AWSSecretResolver {
+	r := &defaultAWSSecretResolver{
+		cloud:      cloud,
+		namePrefix: namePrefix,
+	}
+	r.refresher = newRefresher("secret", refreshInterval, func(ctx context.Context, secretArn string) (interface{}, error) {
+		return r.fetch(ctx, secretArn)
+	}, func(secretArn string, cached interface{}, refreshed interface{}) bool {
+		cachedSecret, refreshedSecret := cached.(awsSecret), refreshed.(awsSecret)
+		if cachedSecret.version != refreshedSecret.version {
+			glog.Infof("detected rotation of secret %v from version %v to %v", secretArn, cachedSecret.version, refreshedSecret.version)
+		}
+		return cachedSecret != refreshedSecret
+	})
+	return r
+}
+
+type awsSecret struct {
+	credentials ClientCredentials
+
+	// version identifies the version of secret the credentials are from.
+	version string
+}
+
+type defaultAWSSecretResolver struct {
+	*refresher
+
+	cloud      aws.CloudAPI
+	namePrefix string
+}
+
+func (r *defaultAWSSecretResolver) Resolve(ctx context.Context, secretArn string) (ClientCredentials, error) {
+	entry, err := r.get(ctx, secretArn)
+	if err != nil {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to resolve secret %v: %v", secretArn, err)
+		return ClientCredentials{}, err
+	}
+	if entry.refreshErr != nil {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to refresh secret %v, using cached credentials: %v", secretArn, entry.refreshErr)
+	}
+	return entry.value.(awsSecret).credentials, nil
+}
+
+func (r *defaultAWSSecretResolver) fetch(ctx context.Context, secretArn string) (awsSecret, error) {
+	parsedArn, err := arn.Parse(secretArn)
+	if err != nil {
+		return awsSecret{}, errors.Wrapf(err, "invalid secret ARN %v", secretArn)
+	}
+
+	var value, version string
+	switch parsedArn.Service {
+	case secretsmanager.ServiceName:
+		if err := r.validateName(strings.TrimPrefix(parsedArn.Resource, "secret:")); err != nil {
+			return awsSecret{}, errors.Wrapf(err, "invalid secret ARN %v", secretArn)
+		}
+		resp, err := r.cloud.GetSecretValue(ctx, secretArn)
+		if err != nil {
+			return awsSecret{}, errors.Wrapf(err, "failed to get secret value of %v", secretArn)
+		}
+		value, version = awssdk.StringValue(resp.SecretString), awssdk.StringValue(resp.VersionId)
+	case ssm.ServiceName:
+		if err := r.validateName(strings.TrimPrefix(ssmParameterName(parsedArn), "/")); err != nil {
+			return awsSecret{}, errors.Wrapf(err, "invalid secret ARN %v", secretArn)
+		}
+		parameter, err := r.cloud.GetSecureStringParameter(ctx, ssmParameterName(parsedArn))
+		if err != nil {
+			return awsSecret{}, errors.Wrapf(err, "failed to get parameter %v", secretArn)
+		}
+		value, version = awssdk.StringValue(parameter.Value), fmt.Sprintf("%v", awssdk.Int64Value(parameter.Version))
+	default:
+		return awsSecret{}, errors.Errorf("secret ARN %v must be a %v secret or a %v parameter", secretArn, secretsmanager.ServiceName, ssm.ServiceName)
+	}
+
+	credentials := ClientCredentials{}
+	if err := json.Unmarshal([]byte(value), &credentials); err != nil {
+		return awsSecret{}, errors.Wrapf(err, "failed to parse secret %v", secretArn)
+	}
+	credentials.ClientId = strings.TrimRightFunc(credentials.ClientId, unicode.IsSpace)
+	if credentials.ClientId == "" || credentials.ClientSecret == "" {
+		return awsSecret{}, errors.Errorf("secret %v must contain both clientId and clientSecret", secretArn)
+	}
+	return awsSecret{
+		credentials: credentials,
+		version:     version,
+	}, nil
+}
+
+// validateName checks the name of a secret or parameter against the allowed name prefix,
+// so that ingresses can't make the controller read arbitrary secrets the IAM role has access to.
+func (r *defaultAWSSecretResolver) validateName(name string) error {
+	if !strings.HasPrefix(name, r.namePrefix) {
+		return errors.Errorf("name %v must start with %v", name, r.namePrefix)
+	}
+	return nil
+}
+
+// ssmParameterName returns the parameter name from ARN like arn:aws:ssm:region:account:parameter/name
+// The leading slash of hierarchical parameter names is omitted from ARN.
+func ssmParameterName(parsedArn arn.ARN) string {
+	name := strings.TrimPrefix(parsedArn.Resource, "parameter/")
+	if strings.Contains(name, "/") {
+		return "/" + name
+	}
+	return name
+}
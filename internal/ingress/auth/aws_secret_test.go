@@ -0,0 +1,343 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	testSecretsManagerArn = "arn:aws:secretsmanager:us-west-2:123456789012:secret:alb-ingress/oidc-AbCdEf"
+	testSSMParameterArn   = "arn:aws:ssm:us-west-2:123456789012:parameter/alb-ingress/oidc"
+	testSecretNamePrefix  = "alb-ingress/"
+)
+
+type GetSecretValueCall struct {
+	Output *secretsmanager.GetSecretValueOutput
+	Err    error
+}
+
+type GetSecureStringParameterCall struct {
+	Name   string
+	Output *ssm.Parameter
+	Err    error
+}
+
+func TestDefaultAWSSecretResolver_Resolve(t *testing.T) {
+	for _, tc := range []struct {
+		name                         string
+		secretArn                    string
+		namePrefix                   string
+		getSecretValueCall           *GetSecretValueCall
+		getSecureStringParameterCall *GetSecureStringParameterCall
+		expected                     ClientCredentials
+		expectedErr                  string
+		expectedEvents               int
+	}{
+		{
+			name:      "secrets manager secret",
+			secretArn: testSecretsManagerArn,
+			getSecretValueCall: &GetSecretValueCall{
+				Output: &secretsmanager.GetSecretValueOutput{
+					SecretString: aws.String(`{"clientId": "clientId\n", "clientSecret": "clientSecret"}`),
+					VersionId:    aws.String("v1"),
+				},
+			},
+			expected: ClientCredentials{ClientId: "clientId", ClientSecret: "clientSecret"},
+		},
+		{
+			name:      "ssm parameter",
+			secretArn: testSSMParameterArn,
+			getSecureStringParameterCall: &GetSecureStringParameterCall{
+				Name: "/alb-ingress/oidc",
+				Output: &ssm.Parameter{
+					Value:   aws.String(`{"clientId": "clientId", "clientSecret": "clientSecret"}`),
+					Version: aws.Int64(3),
+				},
+			},
+			expected: ClientCredentials{ClientId: "clientId", ClientSecret: "clientSecret"},
+		},
+		{
+			name:       "non-hierarchical ssm parameter",
+			secretArn:  "arn:aws:ssm:us-west-2:123456789012:parameter/alb-oidc",
+			namePrefix: "alb-",
+			getSecureStringParameterCall: &GetSecureStringParameterCall{
+				Name: "alb-oidc",
+				Output: &ssm.Parameter{
+					Value:   aws.String(`{"clientId": "clientId", "clientSecret": "clientSecret"}`),
+					Version: aws.Int64(1),
+				},
+			},
+			expected: ClientCredentials{ClientId: "clientId", ClientSecret: "clientSecret"},
+		},
+		{
+			name:           "invalid ARN",
+			secretArn:      "alb/oidc",
+			expectedErr:    "invalid secret ARN alb/oidc: arn: invalid prefix",
+			expectedEvents: 1,
+		},
+		{
+			name:           "unsupported service",
+			secretArn:      "arn:aws:s3:::bucket/oidc",
+			expectedErr:    "secret ARN arn:aws:s3:::bucket/oidc must be a secretsmanager secret or a ssm parameter",
+			expectedEvents: 1,
+		},
+		{
+			name:           "secret outside of the name prefix",
+			secretArn:      "arn:aws:secretsmanager:us-west-2:123456789012:secret:rds/password-AbCdEf",
+			expectedErr:    "invalid secret ARN arn:aws:secretsmanager:us-west-2:123456789012:secret:rds/password-AbCdEf: name rds/password-AbCdEf must start with alb-ingress/",
+			expectedEvents: 1,
+		},
+		{
+			name:           "ssm parameter outside of the name prefix",
+			secretArn:      "arn:aws:ssm:us-west-2:123456789012:parameter/alb-ingress-oidc",
+			expectedErr:    "invalid secret ARN arn:aws:ssm:us-west-2:123456789012:parameter/alb-ingress-oidc: name alb-ingress-oidc must start with alb-ingress/",
+			expectedEvents: 1,
+		},
+		{
+			name:      "secret without clientSecret",
+			secretArn: testSecretsManagerArn,
+			getSecretValueCall: &GetSecretValueCall{
+				Output: &secretsmanager.GetSecretValueOutput{
+					SecretString: aws.String(`{"clientId": "clientId"}`),
+					VersionId:    aws.String("v1"),
+				},
+			},
+			expectedErr:    "secret " + testSecretsManagerArn + " must contain both clientId and clientSecret",
+			expectedEvents: 1,
+		},
+		{
+			name:      "secret isn't JSON",
+			secretArn: testSecretsManagerArn,
+			getSecretValueCall: &GetSecretValueCall{
+				Output: &secretsmanager.GetSecretValueOutput{
+					SecretString: aws.String(`clientSecret`),
+					VersionId:    aws.String("v1"),
+				},
+			},
+			expectedErr:    "failed to parse secret " + testSecretsManagerArn + ": invalid character 'c' looking for beginning of value",
+			expectedEvents: 1,
+		},
+		{
+			name:      "GetSecretValue fails",
+			secretArn: testSecretsManagerArn,
+			getSecretValueCall: &GetSecretValueCall{
+				Err: errors.New("AccessDeniedException"),
+			},
+			expectedErr:    "failed to get secret value of " + testSecretsManagerArn + ": AccessDeniedException",
+			expectedEvents: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := &mocks.CloudAPI{}
+			if tc.getSecretValueCall != nil {
+				cloud.On("GetSecretValue", mock.Anything, tc.secretArn).Return(tc.getSecretValueCall.Output, tc.getSecretValueCall.Err)
+			}
+			if tc.getSecureStringParameterCall != nil {
+				cloud.On("GetSecureStringParameter", mock.Anything, tc.getSecureStringParameterCall.Name).Return(tc.getSecureStringParameterCall.Output, tc.getSecureStringParameterCall.Err)
+			}
+
+			namePrefix := testSecretNamePrefix
+			if tc.namePrefix != "" {
+				namePrefix = tc.namePrefix
+			}
+			var events []string
+			resolver := NewAWSSecretResolver(cloud, time.Minute, namePrefix)
+			credentials, err := resolver.Resolve(recordEvents(&events), tc.secretArn)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, credentials)
+			}
+			assert.Len(t, events, tc.expectedEvents)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultAWSSecretResolver_Rotation(t *testing.T) {
+	cloud := &mocks.CloudAPI{}
+	resolver := NewAWSSecretResolver(cloud, time.Minute, testSecretNamePrefix).(*defaultAWSSecretResolver)
+	var changedSecretArns []string
+	resolver.OnChange(func(secretArn string) {
+		changedSecretArns = append(changedSecretArns, secretArn)
+	})
+	var events []string
+	ctx := recordEvents(&events)
+
+	cloud.On("GetSecretValue", mock.Anything, testSecretsManagerArn).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId": "clientId", "clientSecret": "secret-v1"}`),
+		VersionId:    aws.String("v1"),
+	}, nil).Twice()
+	credentials, err := resolver.Resolve(ctx, testSecretsManagerArn)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-v1", credentials.ClientSecret)
+	_, _ = resolver.Resolve(ctx, testSecretsManagerArn)
+
+	// an unchanged secret doesn't notify
+	resolver.refresh()
+	assert.Empty(t, changedSecretArns)
+
+	// a rotated secret notifies and updates the cache
+	cloud.On("GetSecretValue", mock.Anything, testSecretsManagerArn).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId": "clientId", "clientSecret": "secret-v2"}`),
+		VersionId:    aws.String("v2"),
+	}, nil).Once()
+	resolver.refresh()
+	assert.Equal(t, []string{testSecretsManagerArn}, changedSecretArns)
+	credentials, err = resolver.Resolve(ctx, testSecretsManagerArn)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-v2", credentials.ClientSecret)
+
+	// a failed refresh notifies and keeps the cached credentials, with an event on use
+	cloud.On("GetSecretValue", mock.Anything, testSecretsManagerArn).Return(nil, errors.New("ThrottlingException")).Once()
+	resolver.refresh()
+	assert.Equal(t, []string{testSecretsManagerArn, testSecretsManagerArn}, changedSecretArns)
+	credentials, err = resolver.Resolve(ctx, testSecretsManagerArn)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-v2", credentials.ClientSecret)
+	assert.Len(t, events, 1)
+
+	cloud.AssertExpectations(t)
+}
+
+func TestDefaultAWSSecretResolver_Eviction(t *testing.T) {
+	cloud := &mocks.CloudAPI{}
+	resolver := NewAWSSecretResolver(cloud, time.Minute, testSecretNamePrefix).(*defaultAWSSecretResolver)
+	inUse := true
+	resolver.InUse(func(secretArn string) bool {
+		return inUse
+	})
+	cloud.On("GetSecretValue", mock.Anything, testSecretsManagerArn).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId": "clientId", "clientSecret": "clientSecret"}`),
+		VersionId:    aws.String("v1"),
+	}, nil).Twice()
+	_, err := resolver.Resolve(context.Background(), testSecretsManagerArn)
+	assert.NoError(t, err)
+
+	// a secret in use is refreshed, however long ago it was resolved
+	resolver.refresh()
+	assert.Len(t, resolver.entries, 1)
+
+	// a secret no longer in use is evicted
+	inUse = false
+	resolver.refresh()
+	assert.Empty(t, resolver.entries)
+	cloud.AssertExpectations(t)
+}
+
+func TestDefaultModule_NewConfig_AWSSecret(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotation      string
+		expectedIDPOIDC IDPOIDC
+		expectedErr     string
+	}{
+		{
+			name:       "client credentials from secrets manager",
+			annotation: `{"Issuer": "Issuer","AuthorizationEndpoint": "AuthorizationEndpoint","TokenEndpoint": "TokenEndpoint","UserInfoEndpoint": "UserInfoEndpoint","SecretArn": "` + testSecretsManagerArn + `"}`,
+			expectedIDPOIDC: IDPOIDC{
+				Issuer:                "Issuer",
+				AuthorizationEndpoint: "AuthorizationEndpoint",
+				TokenEndpoint:         "TokenEndpoint",
+				UserInfoEndpoint:      "UserInfoEndpoint",
+				ClientId:              "clientId",
+				ClientSecret:          "clientSecret",
+			},
+		},
+		{
+			name:        "both SecretName and SecretArn",
+			annotation:  `{"Issuer": "Issuer","AuthorizationEndpoint": "AuthorizationEndpoint","TokenEndpoint": "TokenEndpoint","UserInfoEndpoint": "UserInfoEndpoint","SecretName": "oidc-secret","SecretArn": "` + testSecretsManagerArn + `"}`,
+			expectedErr: "only one of SecretName and SecretArn can be specified for IDP OIDC",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "service"}, gomock.Any()).
+				SetArg(2, corev1.Service{})
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetSecretValue", mock.Anything, testSecretsManagerArn).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"clientId": "clientId", "clientSecret": "clientSecret"}`),
+				VersionId:    aws.String("v1"),
+			}, nil)
+			module := &defaultModule{
+				cache:             mockCache,
+				awsSecretResolver: NewAWSSecretResolver(cloud, time.Minute, testSecretNamePrefix),
+			}
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "ingress",
+					Annotations: map[string]string{
+						parser.GetAnnotationWithPrefix(AnnotationAuthType):    "oidc",
+						parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): tc.annotation,
+					},
+				},
+			}
+			backend := extensions.IngressBackend{
+				ServiceName: "service",
+				ServicePort: intstr.FromInt(80),
+			}
+
+			authCfg, err := module.NewConfig(context.Background(), ingress, backend, "")
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIDPOIDC, authCfg.IDPOIDC)
+			}
+		})
+	}
+}
+
+func TestBuildOIDCSecretArnIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotations     map[string]string
+		expectedIndexes []string
+	}{
+		{
+			name:            "ingress/service don't use OIDC auth",
+			annotations:     nil,
+			expectedIndexes: nil,
+		},
+		{
+			name: "ingress/service use k8s secret",
+			annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): `{"SecretName": "oidc-secret"}`,
+			},
+			expectedIndexes: nil,
+		},
+		{
+			name: "ingress/service use AWS secret",
+			annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): `{"SecretArn": "` + testSSMParameterArn + `"}`,
+			},
+			expectedIndexes: []string{testSSMParameterArn},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedIndexes, buildOIDCSecretArnIndex(tc.annotations))
+		})
+	}
+}
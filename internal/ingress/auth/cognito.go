@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// cognitoCallbackPath is the path ALB receives the authorization code from the IDP on, the user pool client
+// must allow callback URLs like https://host/oauth2/idpresponse for each host served by the load balancer.
+const cognitoCallbackPath = "/oauth2/idpresponse"
+
+const (
+	cognitoCacheMaxSize = 1024
+	// cognitoCacheTTL is how long resolved user pools and clients are cached, so that changes of their domain
+	// or callback URLs are picked up after at most a TTL.
+	cognitoCacheTTL = 10 * time.Minute
+)
+
+// loadIDPCognito loads IDP Cognito from annos by priority for the path named pathName of ingress.
+func (m *defaultModule) loadIDPCognito(ctx context.Context, idpCognito *IDPCognito, ingress *extensions.Ingress, pathName string, annos []map[string]string) (bool, error) {
+	annoIDPCognito := AnnotationSchemaIDPCognito{}
+	exists, err := annotations.LoadJSONAnnotation(AnnotationAuthIDPCognito, &annoIDPCognito, annos...)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to load configuration for IDP Cognito")
+	}
+	if !exists {
+		return false, nil
+	}
+
+	if annoIDPCognito.needsResolution() {
+		if err := m.resolveIDPCognito(ctx, &annoIDPCognito, ingressHosts(ingress, pathName)); err != nil {
+			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to resolve IDP Cognito: %v", err)
+			return true, errors.Wrapf(err, "failed to resolve IDP Cognito")
+		}
+	}
+	*idpCognito = IDPCognito{
+		AuthenticationRequestExtraParams: annoIDPCognito.AuthenticationRequestExtraParams,
+		UserPoolArn:                      annoIDPCognito.UserPoolArn,
+		UserPoolClientId:                 annoIDPCognito.UserPoolClientId,
+		UserPoolDomain:                   annoIDPCognito.UserPoolDomain,
+	}
+	return true, nil
+}
+
+// resolveIDPCognito resolves the user pool and client referred by name in annoIDPCognito,
+// and validates the client allows the callback URLs of hosts.
+func (m *defaultModule) resolveIDPCognito(ctx context.Context, annoIDPCognito *AnnotationSchemaIDPCognito, hosts []string) error {
+	if annoIDPCognito.UserPoolName != "" {
+		if annoIDPCognito.UserPoolArn != "" {
+			return errors.New("only one of UserPoolArn and UserPoolName can be specified")
+		}
+		userPool, err := m.getUserPoolByName(ctx, annoIDPCognito.UserPoolName)
+		if err != nil {
+			return err
+		}
+		annoIDPCognito.UserPoolArn = awssdk.StringValue(userPool.Arn)
+		if annoIDPCognito.UserPoolDomain == "" {
+			annoIDPCognito.UserPoolDomain = awssdk.StringValue(userPool.CustomDomain)
+		}
+		if annoIDPCognito.UserPoolDomain == "" {
+			annoIDPCognito.UserPoolDomain = awssdk.StringValue(userPool.Domain)
+		}
+		if annoIDPCognito.UserPoolDomain == "" {
+			return errors.Errorf("user pool %v has no domain", annoIDPCognito.UserPoolName)
+		}
+	}
+
+	userPoolID, err := userPoolIDFromArn(annoIDPCognito.UserPoolArn)
+	if err != nil {
+		return err
+	}
+	if annoIDPCognito.UserPoolClientName != "" && annoIDPCognito.UserPoolClientId != "" {
+		return errors.New("only one of UserPoolClientId and UserPoolClientName can be specified")
+	}
+	if annoIDPCognito.UserPoolClientName == "" && annoIDPCognito.UserPoolClientId == "" {
+		return errors.New("one of UserPoolClientId and UserPoolClientName must be specified")
+	}
+	client, err := m.getUserPoolClient(ctx, userPoolID, annoIDPCognito.UserPoolClientName, annoIDPCognito.UserPoolClientId)
+	if err != nil {
+		return err
+	}
+	annoIDPCognito.UserPoolClientId = awssdk.StringValue(client.ClientId)
+
+	missingCallbackURLs := missingCognitoCallbackURLs(awssdk.StringValueSlice(client.CallbackURLs), hosts)
+	if len(missingCallbackURLs) != 0 {
+		return errors.Errorf("client %v of user pool %v doesn't allow callback URLs %v",
+			awssdk.StringValue(client.ClientName), userPoolID, strings.Join(missingCallbackURLs, ", "))
+	}
+	return nil
+}
+
+// getUserPoolByName returns the user pool named userPoolName, from cache if it was resolved within the TTL.
+func (m *defaultModule) getUserPoolByName(ctx context.Context, userPoolName string) (*cognitoidentityprovider.UserPoolType, error) {
+	cacheKey := "userpool/" + userPoolName
+	if cached, ok := m.cognitoCache.Get(cacheKey); ok {
+		return cached.(*cognitoidentityprovider.UserPoolType), nil
+	}
+	userPool, err := m.cloud.GetUserPoolByName(ctx, userPoolName)
+	if err != nil {
+		return nil, err
+	}
+	m.cognitoCache.Add(cacheKey, userPool, cognitoCacheTTL)
+	return userPool, nil
+}
+
+// getUserPoolClient returns the client of user pool userPoolID named clientName, or with clientID if clientName is empty,
+// from cache if it was resolved within the TTL.
+func (m *defaultModule) getUserPoolClient(ctx context.Context, userPoolID string, clientName string, clientID string) (*cognitoidentityprovider.UserPoolClientType, error) {
+	cacheKey := fmt.Sprintf("client/%v/name/%v", userPoolID, clientName)
+	if clientName == "" {
+		cacheKey = fmt.Sprintf("client/%v/id/%v", userPoolID, clientID)
+	}
+	if cached, ok := m.cognitoCache.Get(cacheKey); ok {
+		return cached.(*cognitoidentityprovider.UserPoolClientType), nil
+	}
+	var client *cognitoidentityprovider.UserPoolClientType
+	var err error
+	if clientName != "" {
+		client, err = m.cloud.GetUserPoolClientByName(ctx, userPoolID, clientName)
+	} else {
+		client, err = m.cloud.DescribeUserPoolClient(ctx, userPoolID, clientID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.cognitoCache.Add(cacheKey, client, cognitoCacheTTL)
+	return client, nil
+}
+
+// userPoolIDFromArn returns the user pool ID from ARN like arn:aws:cognito-idp:region:account:userpool/id
+func userPoolIDFromArn(userPoolArn string) (string, error) {
+	parsedArn, err := arn.Parse(userPoolArn)
+	if err != nil || !strings.HasPrefix(parsedArn.Resource, "userpool/") {
+		return "", errors.Errorf("invalid user pool ARN %v", userPoolArn)
+	}
+	return strings.TrimPrefix(parsedArn.Resource, "userpool/"), nil
+}
+
+// missingCognitoCallbackURLs returns the callback URLs of hosts that are not allowed by callbackURLs
+func missingCognitoCallbackURLs(callbackURLs []string, hosts []string) []string {
+	allowedHosts := sets.NewString()
+	for _, callbackURL := range callbackURLs {
+		u, err := url.Parse(callbackURL)
+		if err != nil || u.Scheme != "https" || u.Path != cognitoCallbackPath {
+			continue
+		}
+		allowedHosts.Insert(strings.ToLower(u.Host))
+	}
+
+	var missing []string
+	for _, host := range hosts {
+		// the callback URLs of wildcard hosts can't be validated, as Cognito requires them to be listed one by one
+		if strings.Contains(host, "*") {
+			continue
+		}
+		if !allowedHosts.Has(strings.ToLower(host)) {
+			missing = append(missing, fmt.Sprintf("https://%v%v", host, cognitoCallbackPath))
+		}
+	}
+	return missing
+}
+
+// ingressHosts returns the hosts of the ingress rule the path named pathName belongs to,
+// or the hosts of all ingress rules if pathName is empty.
+func ingressHosts(ingress *extensions.Ingress, pathName string) []string {
+	hosts := sets.NewString()
+	if host, ok := parser.GetPathNameHost(ingress, pathName); ok {
+		if host != "" {
+			hosts.Insert(host)
+		}
+		return hosts.List()
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			hosts.Insert(rule.Host)
+		}
+	}
+	return hosts.List()
+}
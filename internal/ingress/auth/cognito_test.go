@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const testUserPoolArn = "arn:aws:cognito-idp:us-west-2:123456789012:userpool/us-west-2_AbCdEf"
+
+func TestDefaultModule_NewConfig_Cognito(t *testing.T) {
+	userPool := &cognitoidentityprovider.UserPoolType{
+		Id:     aws.String("us-west-2_AbCdEf"),
+		Arn:    aws.String(testUserPoolArn),
+		Name:   aws.String("pool"),
+		Domain: aws.String("pool-domain"),
+	}
+	client := &cognitoidentityprovider.UserPoolClientType{
+		ClientId:     aws.String("clientId"),
+		ClientName:   aws.String("client"),
+		CallbackURLs: aws.StringSlice([]string{"https://a.example.com/oauth2/idpresponse", "https://B.example.com/oauth2/idpresponse"}),
+	}
+
+	for _, tc := range []struct {
+		name               string
+		annotation         string
+		hosts              []string
+		pathName           string
+		pathNames          string
+		expectedIDPCognito IDPCognito
+		expectedErr        string
+		expectedEvents     int
+	}{
+		{
+			name:       "user pool and client referred by name",
+			annotation: `{"UserPoolName": "pool", "UserPoolClientName": "client"}`,
+			hosts:      []string{"a.example.com", "b.example.com"},
+			expectedIDPCognito: IDPCognito{
+				UserPoolArn:      testUserPoolArn,
+				UserPoolClientId: "clientId",
+				UserPoolDomain:   "pool-domain",
+			},
+		},
+		{
+			name:       "user pool referred by name with explicit domain and client id",
+			annotation: `{"UserPoolName": "pool", "UserPoolClientId": "clientId", "UserPoolDomain": "auth.example.com"}`,
+			hosts:      []string{"a.example.com", "*.example.com"},
+			expectedIDPCognito: IDPCognito{
+				UserPoolArn:      testUserPoolArn,
+				UserPoolClientId: "clientId",
+				UserPoolDomain:   "auth.example.com",
+			},
+		},
+		{
+			name:       "client referred by name only validates the host of the path's rule",
+			annotation: `{"UserPoolArn": "` + testUserPoolArn + `", "UserPoolClientName": "client", "UserPoolDomain": "pool-domain"}`,
+			hosts:      []string{"c.example.com", "a.example.com"},
+			pathName:   "login",
+			pathNames:  `{"login": "a.example.com/login"}`,
+			expectedIDPCognito: IDPCognito{
+				UserPoolArn:      testUserPoolArn,
+				UserPoolClientId: "clientId",
+				UserPoolDomain:   "pool-domain",
+			},
+		},
+		{
+			name:           "client doesn't allow callback URL of host",
+			annotation:     `{"UserPoolName": "pool", "UserPoolClientName": "client"}`,
+			hosts:          []string{"a.example.com", "c.example.com"},
+			expectedErr:    "failed to resolve IDP Cognito: client client of user pool us-west-2_AbCdEf doesn't allow callback URLs https://c.example.com/oauth2/idpresponse",
+			expectedEvents: 1,
+		},
+		{
+			name:           "both UserPoolArn and UserPoolName",
+			annotation:     `{"UserPoolArn": "` + testUserPoolArn + `", "UserPoolName": "pool", "UserPoolClientName": "client"}`,
+			expectedErr:    "failed to resolve IDP Cognito: only one of UserPoolArn and UserPoolName can be specified",
+			expectedEvents: 1,
+		},
+		{
+			name:           "both UserPoolClientId and UserPoolClientName",
+			annotation:     `{"UserPoolName": "pool", "UserPoolClientId": "clientId", "UserPoolClientName": "client"}`,
+			expectedErr:    "failed to resolve IDP Cognito: only one of UserPoolClientId and UserPoolClientName can be specified",
+			expectedEvents: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "service"}, gomock.Any()).
+				SetArg(2, corev1.Service{})
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetUserPoolByName", mock.Anything, "pool").Return(userPool, nil)
+			cloud.On("GetUserPoolClientByName", mock.Anything, "us-west-2_AbCdEf", "client").Return(client, nil)
+			cloud.On("DescribeUserPoolClient", mock.Anything, "us-west-2_AbCdEf", "clientId").Return(client, nil)
+			module := &defaultModule{
+				cache:        mockCache,
+				cloud:        cloud,
+				cognitoCache: utilcache.NewLRUExpireCache(cognitoCacheMaxSize),
+			}
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "ingress",
+					Annotations: map[string]string{
+						parser.GetAnnotationWithPrefix(AnnotationAuthType):       "cognito",
+						parser.GetAnnotationWithPrefix(AnnotationAuthIDPCognito): tc.annotation,
+					},
+				},
+			}
+			if tc.pathNames != "" {
+				ingress.Annotations[parser.GetAnnotationWithPrefix("path-names")] = tc.pathNames
+			}
+			for _, host := range tc.hosts {
+				ingress.Spec.Rules = append(ingress.Spec.Rules, extensions.IngressRule{Host: host})
+			}
+			backend := extensions.IngressBackend{
+				ServiceName: "service",
+				ServicePort: intstr.FromInt(80),
+			}
+
+			var events []string
+			authCfg, err := module.NewConfig(recordEvents(&events), ingress, backend, tc.pathName)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIDPCognito, authCfg.IDPCognito)
+			}
+			assert.Len(t, events, tc.expectedEvents)
+		})
+	}
+}
+
+func TestDefaultModule_resolveIDPCognito_Cache(t *testing.T) {
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetUserPoolByName", mock.Anything, "pool").Return(&cognitoidentityprovider.UserPoolType{
+		Arn:    aws.String(testUserPoolArn),
+		Domain: aws.String("pool-domain"),
+	}, nil).Once()
+	cloud.On("GetUserPoolClientByName", mock.Anything, "us-west-2_AbCdEf", "client").Return(&cognitoidentityprovider.UserPoolClientType{
+		ClientId:     aws.String("clientId"),
+		CallbackURLs: aws.StringSlice([]string{"https://a.example.com/oauth2/idpresponse"}),
+	}, nil).Once()
+	cloud.On("DescribeUserPoolClient", mock.Anything, "us-west-2_AbCdEf", "clientId").Return(&cognitoidentityprovider.UserPoolClientType{
+		ClientId:     aws.String("clientId"),
+		CallbackURLs: aws.StringSlice([]string{"https://a.example.com/oauth2/idpresponse"}),
+	}, nil).Once()
+	module := &defaultModule{
+		cloud:        cloud,
+		cognitoCache: utilcache.NewLRUExpireCache(cognitoCacheMaxSize),
+	}
+
+	// pools and clients are resolved once, whatever hosts are validated against them
+	for _, hosts := range [][]string{{"a.example.com"}, {"a.example.com"}, {"b.example.com"}} {
+		annoIDPCognito := AnnotationSchemaIDPCognito{UserPoolName: "pool", UserPoolClientName: "client"}
+		err := module.resolveIDPCognito(context.Background(), &annoIDPCognito, hosts)
+		if hosts[0] == "b.example.com" {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, "clientId", annoIDPCognito.UserPoolClientId)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		annoIDPCognito := AnnotationSchemaIDPCognito{UserPoolArn: testUserPoolArn, UserPoolClientId: "clientId", UserPoolDomain: "pool-domain"}
+		assert.NoError(t, module.resolveIDPCognito(context.Background(), &annoIDPCognito, []string{"a.example.com"}))
+	}
+	cloud.AssertExpectations(t)
+}
+
+func TestMissingCognitoCallbackURLs(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		callbackURLs []string
+		hosts        []string
+		expected     []string
+	}{
+		{
+			name:         "no hosts",
+			callbackURLs: nil,
+			hosts:        nil,
+			expected:     nil,
+		},
+		{
+			name:         "callback URLs of other paths or schemes",
+			callbackURLs: []string{"http://a.example.com/oauth2/idpresponse", "https://a.example.com/callback"},
+			hosts:        []string{"a.example.com"},
+			expected:     []string{"https://a.example.com/oauth2/idpresponse"},
+		},
+		{
+			name:         "callback URLs of all hosts",
+			callbackURLs: []string{"https://a.example.com/oauth2/idpresponse", "https://b.example.com/oauth2/idpresponse"},
+			hosts:        []string{"a.example.com", "B.example.com"},
+			expected:     nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, missingCognitoCallbackURLs(tc.callbackURLs, tc.hosts))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// the maximum size of an OpenID provider configuration document we'll read
+	oidcDiscoveryMaxResponseBytes = 1 << 20
+)
+
+// OIDCProviderMetadata is the subset of OpenID provider metadata used to configure ALB authentication.
+// See https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type OIDCProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// OIDCDiscoverer discovers the OpenID provider metadata of issuers.
+type OIDCDiscoverer interface {
+	manager.Runnable
+
+	// Discover returns the provider metadata of issuer, fetching it on first use.
+	Discover(ctx context.Context, issuer string) (OIDCProviderMetadata, error)
+
+	// OnChange registers a function to be invoked with the issuer whenever a periodic refresh changes its
+	// provider metadata or fails.
+	OnChange(fn func(issuer string))
+
+	// InUse registers a function that tells whether an issuer is still used, the metadata of issuers no longer used
+	// is evicted on refresh.
+	InUse(fn func(issuer string) bool)
+}
+
+// NewOIDCDiscoveryHTTPClient constructs a http client to fetch OpenID provider configurations with, which refuses to
+// connect to loopback, private and link-local addresses, like the instance metadata endpoint, and to follow redirects to them.
+// Issuers come from annotations, so that they mustn't reach internal services from the controller.
+// The client bypasses proxies, so that the addresses it connects to can be checked.
+func NewOIDCDiscoveryHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return validateOIDCDiscoveryIP(net.ParseIP(host))
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return validateOIDCDiscoveryURL(req.URL)
+		},
+	}
+}
+
+// validateOIDCDiscoveryIP checks that ip is a public address.
+func validateOIDCDiscoveryIP(ip net.IP) error {
+	if ip == nil {
+		return errors.New("invalid address")
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() || isPrivateIP(ip) {
+		return errors.Errorf("address %v isn't public", ip)
+	}
+	return nil
+}
+
+var privateIPNets = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+func isPrivateIP(ip net.IP) bool {
+	for _, ipNet := range privateIPNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// validateOIDCDiscoveryURL checks that u is a https URL with a host, whose addresses are checked on connection.
+func validateOIDCDiscoveryURL(u *url.URL) error {
+	if u.Scheme != "https" {
+		return errors.Errorf("scheme of %v must be https", u)
+	}
+	if u.Hostname() == "" {
+		return errors.Errorf("%v has no host", u)
+	}
+	return nil
+}
+
+// NewOIDCDiscoverer constructs new OIDCDiscoverer that refreshes discovered metadata every refreshInterval.
+// Metadata of issuers that are no longer in use is evicted rather than refreshed.
+func NewOIDCDiscoverer(httpClient *http.Client, refreshInterval time.Duration) OIDCDiscoverer {
+	d := &defaultOIDCDiscoverer{
+		httpClient: httpClient,
+	}
+	d.refresher = newRefresher("OIDC configuration", refreshInterval, func(ctx context.Context, issuer string) (interface{}, error) {
+		return d.fetch(ctx, issuer)
+	}, func(_ string, cached interface{}, refreshed interface{}) bool {
+		return cached.(OIDCProviderMetadata) != refreshed.(OIDCProviderMetadata)
+	})
+	return d
+}
+
+type defaultOIDCDiscoverer struct {
+	*refresher
+
+	httpClient *http.Client
+}
+
+func (d *defaultOIDCDiscoverer) Discover(ctx context.Context, issuer string) (OIDCProviderMetadata, error) {
+	entry, err := d.get(ctx, issuer)
+	if err != nil {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to discover OIDC configuration of %v: %v", issuer, err)
+		return OIDCProviderMetadata{}, err
+	}
+	if entry.refreshErr != nil {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to refresh OIDC configuration of %v, using cached endpoints: %v", issuer, entry.refreshErr)
+	}
+	return entry.value.(OIDCProviderMetadata), nil
+}
+
+func (d *defaultOIDCDiscoverer) fetch(ctx context.Context, issuer string) (OIDCProviderMetadata, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + oidcDiscoveryPath
+	req, err := http.NewRequest(http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "invalid issuer %v", issuer)
+	}
+	if err := validateOIDCDiscoveryURL(req.URL); err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "invalid issuer %v", issuer)
+	}
+	resp, err := d.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "failed to fetch %v", discoveryURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return OIDCProviderMetadata{}, errors.Errorf("failed to fetch %v, got status %v", discoveryURL, resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oidcDiscoveryMaxResponseBytes))
+	if err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "failed to read %v", discoveryURL)
+	}
+
+	metadata := OIDCProviderMetadata{}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "failed to parse %v", discoveryURL)
+	}
+	if err := metadata.validate(issuer); err != nil {
+		return OIDCProviderMetadata{}, errors.Wrapf(err, "invalid OIDC configuration from %v", discoveryURL)
+	}
+	return metadata, nil
+}
+
+func (m OIDCProviderMetadata) validate(issuer string) error {
+	if strings.TrimSuffix(m.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return errors.Errorf("issuer %v doesn't match %v", m.Issuer, issuer)
+	}
+	for _, endpoint := range []struct {
+		name  string
+		value string
+	}{
+		{"authorization_endpoint", m.AuthorizationEndpoint},
+		{"token_endpoint", m.TokenEndpoint},
+		{"userinfo_endpoint", m.UserInfoEndpoint},
+	} {
+		if endpoint.value == "" {
+			return errors.Errorf("%v is missing", endpoint.name)
+		}
+		if u, err := url.Parse(endpoint.value); err != nil || !u.IsAbs() {
+			return errors.Errorf("%v %v is not an absolute URL", endpoint.name, endpoint.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// oidcProviderStub serves an OpenID provider configuration document.
+type oidcProviderStub struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	status   int
+	metadata map[string]string
+	requests int
+}
+
+func newOIDCProviderStub() *oidcProviderStub {
+	stub := &oidcProviderStub{status: http.StatusOK}
+	stub.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mutex.Lock()
+		defer stub.mutex.Unlock()
+		stub.requests++
+		if r.URL.Path != oidcDiscoveryPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(stub.status)
+		_ = json.NewEncoder(w).Encode(stub.metadata)
+	}))
+	stub.metadata = map[string]string{
+		"issuer":                 stub.URL,
+		"authorization_endpoint": stub.URL + "/authorize",
+		"token_endpoint":         stub.URL + "/token",
+		"userinfo_endpoint":      stub.URL + "/userinfo",
+	}
+	return stub
+}
+
+func (s *oidcProviderStub) set(fn func(s *oidcProviderStub)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fn(s)
+}
+
+func (s *oidcProviderStub) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func (s *oidcProviderStub) expectedMetadata() OIDCProviderMetadata {
+	return OIDCProviderMetadata{
+		Issuer:                s.URL,
+		AuthorizationEndpoint: s.URL + "/authorize",
+		TokenEndpoint:         s.URL + "/token",
+		UserInfoEndpoint:      s.URL + "/userinfo",
+	}
+}
+
+// recordEvents returns a context that records the messages of events emitted with it.
+func recordEvents(events *[]string) context.Context {
+	return albctx.SetEventf(context.Background(), func(eventType string, reason string, format string, args ...interface{}) {
+		*events = append(*events, fmt.Sprintf("%v %v: %v", eventType, reason, fmt.Sprintf(format, args...)))
+	})
+}
+
+func TestDefaultOIDCDiscoverer_Discover(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		stubStatus     int
+		stubMetadata   func(url string) map[string]string
+		issuer         func(url string) string
+		expectedErr    func(url string) string
+		expectedEvents int
+	}{
+		{
+			name: "discover metadata",
+		},
+		{
+			name:   "discover metadata with trailing slash in issuer",
+			issuer: func(url string) string { return url + "/" },
+		},
+		{
+			name:       "provider responds with error status",
+			stubStatus: http.StatusInternalServerError,
+			expectedErr: func(url string) string {
+				return "failed to fetch " + url + oidcDiscoveryPath + ", got status 500 Internal Server Error"
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "issuer mismatch",
+			stubMetadata: func(url string) map[string]string {
+				return map[string]string{
+					"issuer":                 "https://other.example.com",
+					"authorization_endpoint": url + "/authorize",
+					"token_endpoint":         url + "/token",
+					"userinfo_endpoint":      url + "/userinfo",
+				}
+			},
+			expectedErr: func(url string) string {
+				return "invalid OIDC configuration from " + url + oidcDiscoveryPath + ": issuer https://other.example.com doesn't match " + url
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "missing endpoint",
+			stubMetadata: func(url string) map[string]string {
+				return map[string]string{
+					"issuer":                 url,
+					"authorization_endpoint": url + "/authorize",
+					"userinfo_endpoint":      url + "/userinfo",
+				}
+			},
+			expectedErr: func(url string) string {
+				return "invalid OIDC configuration from " + url + oidcDiscoveryPath + ": token_endpoint is missing"
+			},
+			expectedEvents: 1,
+		},
+		{
+			name:   "issuer without https",
+			issuer: func(url string) string { return "http://169.254.169.254/latest/meta-data" },
+			expectedErr: func(url string) string {
+				return "invalid issuer http://169.254.169.254/latest/meta-data: scheme of http://169.254.169.254/latest/meta-data" + oidcDiscoveryPath + " must be https"
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "relative endpoint",
+			stubMetadata: func(url string) map[string]string {
+				return map[string]string{
+					"issuer":                 url,
+					"authorization_endpoint": "/authorize",
+					"token_endpoint":         url + "/token",
+					"userinfo_endpoint":      url + "/userinfo",
+				}
+			},
+			expectedErr: func(url string) string {
+				return "invalid OIDC configuration from " + url + oidcDiscoveryPath + ": authorization_endpoint /authorize is not an absolute URL"
+			},
+			expectedEvents: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := newOIDCProviderStub()
+			defer stub.Close()
+			stub.set(func(s *oidcProviderStub) {
+				if tc.stubStatus != 0 {
+					s.status = tc.stubStatus
+				}
+				if tc.stubMetadata != nil {
+					s.metadata = tc.stubMetadata(s.URL)
+				}
+			})
+			issuer := stub.URL
+			if tc.issuer != nil {
+				issuer = tc.issuer(stub.URL)
+			}
+
+			var events []string
+			discoverer := NewOIDCDiscoverer(stub.Client(), time.Minute)
+			metadata, err := discoverer.Discover(recordEvents(&events), issuer)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr(stub.URL))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, stub.expectedMetadata(), metadata)
+			}
+			assert.Len(t, events, tc.expectedEvents)
+		})
+	}
+}
+
+func TestDefaultOIDCDiscoverer_Cache(t *testing.T) {
+	stub := newOIDCProviderStub()
+	defer stub.Close()
+
+	discoverer := NewOIDCDiscoverer(stub.Client(), time.Minute).(*defaultOIDCDiscoverer)
+	var changedIssuers []string
+	discoverer.OnChange(func(issuer string) {
+		changedIssuers = append(changedIssuers, issuer)
+	})
+	var events []string
+	ctx := recordEvents(&events)
+
+	metadata, err := discoverer.Discover(ctx, stub.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.expectedMetadata(), metadata)
+	_, _ = discoverer.Discover(ctx, stub.URL)
+	assert.Equal(t, 1, stub.requestCount(), "discovered metadata should be cached")
+
+	// an unchanged refresh doesn't notify
+	discoverer.refresh()
+	assert.Equal(t, 2, stub.requestCount())
+	assert.Empty(t, changedIssuers)
+
+	// a changed refresh notifies and updates the cache
+	stub.set(func(s *oidcProviderStub) {
+		s.metadata["token_endpoint"] = s.URL + "/oauth2/token"
+	})
+	discoverer.refresh()
+	assert.Equal(t, []string{stub.URL}, changedIssuers)
+	metadata, err = discoverer.Discover(ctx, stub.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.URL+"/oauth2/token", metadata.TokenEndpoint)
+	assert.Empty(t, events)
+
+	// a failed refresh notifies once and keeps the cached metadata, with an event on use
+	stub.set(func(s *oidcProviderStub) {
+		s.status = http.StatusServiceUnavailable
+	})
+	discoverer.refresh()
+	discoverer.refresh()
+	assert.Equal(t, []string{stub.URL, stub.URL}, changedIssuers)
+	metadata, err = discoverer.Discover(ctx, stub.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.URL+"/oauth2/token", metadata.TokenEndpoint)
+	assert.Len(t, events, 1)
+
+	// a successful refresh after a failed one notifies
+	stub.set(func(s *oidcProviderStub) {
+		s.status = http.StatusOK
+	})
+	discoverer.refresh()
+	assert.Equal(t, []string{stub.URL, stub.URL, stub.URL}, changedIssuers)
+	_, _ = discoverer.Discover(ctx, stub.URL)
+	assert.Len(t, events, 1)
+}
+
+func TestNewOIDCDiscoveryHTTPClient(t *testing.T) {
+	stub := newOIDCProviderStub()
+	defer stub.Close()
+
+	// the stub listens on a loopback address
+	discoverer := NewOIDCDiscoverer(NewOIDCDiscoveryHTTPClient(time.Second), time.Minute)
+	_, err := discoverer.Discover(context.Background(), stub.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "isn't public")
+	}
+	assert.Equal(t, 0, stub.requestCount())
+
+	for _, ip := range []string{"127.0.0.1", "169.254.169.254", "10.0.0.1", "172.16.0.1", "192.168.1.1", "::1", "fd00::1"} {
+		assert.Error(t, validateOIDCDiscoveryIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		assert.NoError(t, validateOIDCDiscoveryIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestDefaultOIDCDiscoverer_Eviction(t *testing.T) {
+	stub := newOIDCProviderStub()
+	defer stub.Close()
+
+	discoverer := NewOIDCDiscoverer(stub.Client(), time.Minute).(*defaultOIDCDiscoverer)
+	inUse := true
+	discoverer.InUse(func(issuer string) bool {
+		return inUse
+	})
+	_, err := discoverer.Discover(context.Background(), stub.URL)
+	assert.NoError(t, err)
+
+	// an issuer in use is refreshed, however long ago it was discovered
+	discoverer.refresh()
+	assert.Equal(t, 2, stub.requestCount())
+	assert.Len(t, discoverer.entries, 1)
+
+	// an issuer no longer in use is evicted
+	inUse = false
+	discoverer.refresh()
+	assert.Equal(t, 2, stub.requestCount())
+	assert.Empty(t, discoverer.entries)
+}
+
+func TestDefaultModule_NewConfig_OIDCDiscovery(t *testing.T) {
+	stub := newOIDCProviderStub()
+	defer stub.Close()
+
+	for _, tc := range []struct {
+		name                 string
+		annotation           string
+		expectedIDPOIDC      IDPOIDC
+		expectedErrorPattern string
+	}{
+		{
+			name:       "endpoints are discovered from issuer",
+			annotation: fmt.Sprintf(`{"Issuer": "%v", "SecretName": "oidc-secret"}`, stub.URL),
+			expectedIDPOIDC: IDPOIDC{
+				Issuer:                stub.URL,
+				AuthorizationEndpoint: stub.URL + "/authorize",
+				TokenEndpoint:         stub.URL + "/token",
+				UserInfoEndpoint:      stub.URL + "/userinfo",
+				ClientId:              "clientId",
+				ClientSecret:          "clientSecret",
+			},
+		},
+		{
+			name:       "explicit endpoints take precedence over discovered ones",
+			annotation: fmt.Sprintf(`{"Issuer": "%v", "TokenEndpoint": "https://token.example.com", "SecretName": "oidc-secret"}`, stub.URL),
+			expectedIDPOIDC: IDPOIDC{
+				Issuer:                stub.URL,
+				AuthorizationEndpoint: stub.URL + "/authorize",
+				TokenEndpoint:         "https://token.example.com",
+				UserInfoEndpoint:      stub.URL + "/userinfo",
+				ClientId:              "clientId",
+				ClientSecret:          "clientSecret",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "service"}, gomock.Any()).
+				SetArg(2, corev1.Service{})
+			mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "oidc-secret"}, gomock.Any()).
+				SetArg(2, corev1.Secret{
+					Data: map[string][]byte{
+						"clientId":     []byte("clientId"),
+						"clientSecret": []byte("clientSecret"),
+					},
+				})
+			module := &defaultModule{
+				cache:          mockCache,
+				oidcDiscoverer: NewOIDCDiscoverer(stub.Client(), time.Minute),
+			}
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "ingress",
+					Annotations: map[string]string{
+						parser.GetAnnotationWithPrefix(AnnotationAuthType):    "oidc",
+						parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): tc.annotation,
+					},
+				},
+			}
+			backend := extensions.IngressBackend{
+				ServiceName: "service",
+				ServicePort: intstr.FromInt(80),
+			}
+
+			authCfg, err := module.NewConfig(context.Background(), ingress, backend, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIDPOIDC, authCfg.IDPOIDC)
+		})
+	}
+}
+
+func TestBuildOIDCIssuerIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotations     map[string]string
+		expectedIndexes []string
+	}{
+		{
+			name:            "ingress/service don't use OIDC auth",
+			annotations:     nil,
+			expectedIndexes: nil,
+		},
+		{
+			name: "ingress/service specify all endpoints",
+			annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): `{"Issuer": "https://example.com","AuthorizationEndpoint": "https://example.com/authorize","TokenEndpoint": "https://example.com/token","UserInfoEndpoint": "https://example.com/userinfo"}`,
+			},
+			expectedIndexes: nil,
+		},
+		{
+			name: "ingress/service discover endpoints",
+			annotations: map[string]string{
+				parser.GetAnnotationWithPrefix(AnnotationAuthIDPOIDC): `{"Issuer": "https://example.com", "SecretName": "oidc-secret"}`,
+			},
+			expectedIndexes: []string{"https://example.com"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedIndexes, buildOIDCIssuerIndex(tc.annotations))
+		})
+	}
+}
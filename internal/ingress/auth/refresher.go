@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// refresher caches values fetched by key, like the metadata of OIDC issuers, and refreshes them periodically,
+// so that objects using a value can be reconciled again when it changes.
+type refresher struct {
+	// kind describes the values in logs, like "OIDC configuration".
+	kind     string
+	interval time.Duration
+
+	// fetch fetches the current value of key.
+	fetch func(ctx context.Context, key string) (interface{}, error)
+	// changed tells whether a refreshed value of key differs from the cached one.
+	changed func(key string, cached interface{}, refreshed interface{}) bool
+
+	mutex    sync.RWMutex
+	entries  map[string]refresherEntry
+	onChange []func(key string)
+	inUse    func(key string) bool
+}
+
+type refresherEntry struct {
+	value interface{}
+
+	// refreshErr is the error of the latest refresh, the value is from the last successful one.
+	refreshErr error
+}
+
+func newRefresher(kind string, interval time.Duration, fetch func(ctx context.Context, key string) (interface{}, error),
+	changed func(key string, cached interface{}, refreshed interface{}) bool) *refresher {
+	return &refresher{
+		kind:     kind,
+		interval: interval,
+		fetch:    fetch,
+		changed:  changed,
+		entries:  make(map[string]refresherEntry),
+	}
+}
+
+// get returns the cached entry of key, fetching it on first use.
+func (r *refresher) get(ctx context.Context, key string) (refresherEntry, error) {
+	r.mutex.RLock()
+	entry, ok := r.entries[key]
+	r.mutex.RUnlock()
+	if ok {
+		return entry, nil
+	}
+
+	value, err := r.fetch(ctx, key)
+	if err != nil {
+		return refresherEntry{}, err
+	}
+	entry = refresherEntry{value: value}
+	r.mutex.Lock()
+	r.entries[key] = entry
+	r.mutex.Unlock()
+	return entry, nil
+}
+
+// OnChange registers a function to be invoked with the key whenever a refresh changes its value or fails.
+func (r *refresher) OnChange(fn func(key string)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// InUse registers a function that tells whether a key is still used. Values of keys no longer used are evicted
+// rather than refreshed, without such function they are kept.
+func (r *refresher) InUse(fn func(key string) bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.inUse = fn
+}
+
+// Start refreshes the cached values periodically until stop is closed.
+func (r *refresher) Start(stop <-chan struct{}) error {
+	wait.Until(r.refresh, r.interval, stop)
+	return nil
+}
+
+// refresh fetches the values of keys in use, and evicts the others, such as the ones of deleted ingresses.
+// Keys are only evicted once nothing uses them, rather than once they weren't fetched for a while, since ingresses
+// without changes are only reconciled at the sync period, and their values would be evicted before any refresh.
+func (r *refresher) refresh() {
+	r.mutex.RLock()
+	keys := make([]string, 0, len(r.entries))
+	for key := range r.entries {
+		keys = append(keys, key)
+	}
+	inUse := r.inUse
+	r.mutex.RUnlock()
+
+	for _, key := range keys {
+		if inUse != nil && !inUse(key) {
+			r.mutex.Lock()
+			delete(r.entries, key)
+			r.mutex.Unlock()
+			continue
+		}
+
+		value, err := r.fetch(context.Background(), key)
+		if err != nil {
+			glog.Warningf("failed to refresh %v of %v due to %v", r.kind, key, err)
+		}
+
+		r.mutex.Lock()
+		entry := r.entries[key]
+		changed := false
+		if err != nil {
+			changed = entry.refreshErr == nil
+			entry.refreshErr = err
+		} else {
+			changed = entry.refreshErr != nil || r.changed(key, entry.value, value)
+			entry = refresherEntry{value: value}
+		}
+		r.entries[key] = entry
+		onChange := r.onChange
+		r.mutex.Unlock()
+
+		if changed {
+			for _, fn := range onChange {
+				fn(key)
+			}
+		}
+	}
+}
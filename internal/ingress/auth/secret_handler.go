@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -48,30 +49,66 @@ func (h *EnqueueRequestsForSecretEvent) enqueueImpactedObjects(secret *corev1.Se
 		Namespace: secret.Namespace,
 		Name:      secret.Name,
 	}.String()
+	enqueueImpactedObjects(h.Cache, FieldAuthOIDCSecret, secretKey, h.IngressChan, h.ServiceChan)
+}
 
+// enqueueImpactedObjects sends ingresses and services indexed under value of field to ingressChan and serviceChan,
+// including the ones referencing auth profiles indexed under value of field.
+func enqueueImpactedObjects(c cache.Cache, field string, value string, ingressChan chan<- event.GenericEvent, serviceChan chan<- event.GenericEvent) {
 	ingressList := &extensions.IngressList{}
-	if err := h.Cache.List(context.TODO(), client.MatchingField(FieldAuthOIDCSecret, secretKey), ingressList); err != nil {
-		glog.Errorf("failed to fetch impacted ingresses by %v due to %v", FieldAuthOIDCSecret, err)
+	if err := c.List(context.TODO(), client.MatchingField(field, value), ingressList); err != nil {
+		glog.Errorf("failed to fetch impacted ingresses by %v due to %v", field, err)
 		return
 	}
 	for index := range ingressList.Items {
 		meta, _ := meta.Accessor(&ingressList.Items[index])
-		h.IngressChan <- event.GenericEvent{
+		ingressChan <- event.GenericEvent{
 			Meta:   meta,
 			Object: &ingressList.Items[index],
 		}
 	}
 
 	serviceList := &corev1.ServiceList{}
-	if err := h.Cache.List(context.TODO(), client.MatchingField(FieldAuthOIDCSecret, secretKey), serviceList); err != nil {
-		glog.Errorf("failed to fetch impacted services by %v due to %v", FieldAuthOIDCSecret, err)
+	if err := c.List(context.TODO(), client.MatchingField(field, value), serviceList); err != nil {
+		glog.Errorf("failed to fetch impacted services by %v due to %v", field, err)
 		return
 	}
 	for index := range serviceList.Items {
 		meta, _ := meta.Accessor(&serviceList.Items[index])
-		h.ServiceChan <- event.GenericEvent{
+		serviceChan <- event.GenericEvent{
 			Meta:   meta,
 			Object: &serviceList.Items[index],
 		}
 	}
+
+	if field == FieldAuthProfile {
+		return
+	}
+	configMapList := &corev1.ConfigMapList{}
+	if err := c.List(context.TODO(), client.MatchingField(field, value), configMapList); err != nil {
+		glog.Errorf("failed to fetch impacted auth profiles by %v due to %v", field, err)
+		return
+	}
+	for _, configMap := range configMapList.Items {
+		profileKey := types.NamespacedName{
+			Namespace: configMap.Namespace,
+			Name:      configMap.Name,
+		}.String()
+		enqueueImpactedObjects(c, FieldAuthProfile, profileKey, ingressChan, serviceChan)
+	}
+}
+
+// isReferenced tells whether any ingress, service or auth profile is indexed under value of field.
+// When the cache can't be listed, value is considered referenced.
+func isReferenced(c cache.Cache, field string, value string) bool {
+	for _, list := range []runtime.Object{&extensions.IngressList{}, &corev1.ServiceList{}, &corev1.ConfigMapList{}} {
+		if err := c.List(context.TODO(), client.MatchingField(field, value), list); err != nil {
+			glog.Errorf("failed to fetch objects referencing %v by %v due to %v", value, field, err)
+			return true
+		}
+		if meta.LenList(list) != 0 {
+			return true
+		}
+	}
+	return false
 }
@@ -21,6 +21,9 @@ func TestEnqueueRequestsForSecretEvent_enqueueImpactedObjects(t *testing.T) {
 		secret            corev1.Secret
 		ingressIndexes    map[string]extensions.IngressList
 		serviceIndexes    map[string]corev1.ServiceList
+		configMapIndexes  map[string]corev1.ConfigMapList
+		profileIngresses  map[string]extensions.IngressList
+		profileServices   map[string]corev1.ServiceList
 		expectedIngresses sets.String
 		expectedServices  sets.String
 	}{
@@ -38,6 +41,9 @@ func TestEnqueueRequestsForSecretEvent_enqueueImpactedObjects(t *testing.T) {
 			serviceIndexes: map[string]corev1.ServiceList{
 				"namespace/secret": {},
 			},
+			configMapIndexes: map[string]corev1.ConfigMapList{
+				"namespace/secret": {},
+			},
 			expectedIngresses: sets.NewString(),
 			expectedServices:  sets.NewString(),
 		},
@@ -85,9 +91,65 @@ func TestEnqueueRequestsForSecretEvent_enqueueImpactedObjects(t *testing.T) {
 					},
 				},
 			},
+			configMapIndexes: map[string]corev1.ConfigMapList{
+				"namespace/secret": {},
+			},
 			expectedIngresses: sets.NewString("namespace/ingress1", "namespace/ingress2"),
 			expectedServices:  sets.NewString("namespace/service1", "namespace/service2"),
 		},
+		{
+			name: "secret are referenced by auth profile of ingresses & services",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "secret",
+				},
+			},
+			ingressIndexes: map[string]extensions.IngressList{
+				"namespace/secret": {},
+			},
+			serviceIndexes: map[string]corev1.ServiceList{
+				"namespace/secret": {},
+			},
+			configMapIndexes: map[string]corev1.ConfigMapList{
+				"namespace/secret": {
+					Items: []corev1.ConfigMap{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "namespace",
+								Name:      "profile",
+							},
+						},
+					},
+				},
+			},
+			profileIngresses: map[string]extensions.IngressList{
+				"namespace/profile": {
+					Items: []extensions.Ingress{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "other-namespace",
+								Name:      "ingress1",
+							},
+						},
+					},
+				},
+			},
+			profileServices: map[string]corev1.ServiceList{
+				"namespace/profile": {
+					Items: []corev1.Service{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "namespace",
+								Name:      "service1",
+							},
+						},
+					},
+				},
+			},
+			expectedIngresses: sets.NewString("other-namespace/ingress1"),
+			expectedServices:  sets.NewString("namespace/service1"),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -100,6 +162,15 @@ func TestEnqueueRequestsForSecretEvent_enqueueImpactedObjects(t *testing.T) {
 			for index, value := range tc.serviceIndexes {
 				mockCache.EXPECT().List(gomock.Any(), client.MatchingField(FieldAuthOIDCSecret, index), gomock.Any()).SetArg(2, value)
 			}
+			for index, value := range tc.configMapIndexes {
+				mockCache.EXPECT().List(gomock.Any(), client.MatchingField(FieldAuthOIDCSecret, index), gomock.Any()).SetArg(2, value)
+			}
+			for index, value := range tc.profileIngresses {
+				mockCache.EXPECT().List(gomock.Any(), client.MatchingField(FieldAuthProfile, index), gomock.Any()).SetArg(2, value)
+			}
+			for index, value := range tc.profileServices {
+				mockCache.EXPECT().List(gomock.Any(), client.MatchingField(FieldAuthProfile, index), gomock.Any()).SetArg(2, value)
+			}
 
 			actualIngresses := sets.NewString()
 			actualServices := sets.NewString()
@@ -138,3 +209,63 @@ func TestEnqueueRequestsForSecretEvent_enqueueImpactedObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReferenced(t *testing.T) {
+	ingresses := extensions.IngressList{Items: []extensions.Ingress{{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}}}
+	configMaps := corev1.ConfigMapList{Items: []corev1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "profile"}}}}
+	for _, tc := range []struct {
+		name       string
+		ingresses  *extensions.IngressList
+		services   *corev1.ServiceList
+		configMaps *corev1.ConfigMapList
+		listErr    error
+		expected   bool
+	}{
+		{
+			name:      "referenced by an ingress",
+			ingresses: &ingresses,
+			expected:  true,
+		},
+		{
+			name:       "referenced by an auth profile",
+			ingresses:  &extensions.IngressList{},
+			services:   &corev1.ServiceList{},
+			configMaps: &configMaps,
+			expected:   true,
+		},
+		{
+			name:       "not referenced",
+			ingresses:  &extensions.IngressList{},
+			services:   &corev1.ServiceList{},
+			configMaps: &corev1.ConfigMapList{},
+			expected:   false,
+		},
+		{
+			name:     "failed to list",
+			listErr:  fmt.Errorf("cache not synced"),
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			opts := client.MatchingField(FieldAuthOIDCIssuer, "https://issuer.example.com")
+			if tc.listErr != nil {
+				mockCache.EXPECT().List(gomock.Any(), opts, gomock.Any()).Return(tc.listErr)
+			}
+			if tc.ingresses != nil {
+				mockCache.EXPECT().List(gomock.Any(), opts, gomock.AssignableToTypeOf(&extensions.IngressList{})).SetArg(2, *tc.ingresses)
+			}
+			if tc.services != nil {
+				mockCache.EXPECT().List(gomock.Any(), opts, gomock.AssignableToTypeOf(&corev1.ServiceList{})).SetArg(2, *tc.services)
+			}
+			if tc.configMaps != nil {
+				mockCache.EXPECT().List(gomock.Any(), opts, gomock.AssignableToTypeOf(&corev1.ConfigMapList{})).SetArg(2, *tc.configMaps)
+			}
+
+			assert.Equal(t, isReferenced(mockCache, FieldAuthOIDCIssuer, "https://issuer.example.com"), tc.expected)
+		})
+	}
+}
@@ -52,9 +52,30 @@ type Config struct {
 	IDPOIDC    IDPOIDC
 }
 
+// the annotation schema for configuring IDPCognito
+// Instead of UserPoolArn and UserPoolClientId, the user pool and its client can be referred by UserPoolName and UserPoolClientName,
+// UserPoolDomain defaults to the domain of the user pool referred by name.
+type AnnotationSchemaIDPCognito struct {
+	AuthenticationRequestExtraParams AuthenticationRequestExtraParams
+	UserPoolArn                      string
+	UserPoolClientId                 string
+	UserPoolDomain                   string
+
+	UserPoolName       string
+	UserPoolClientName string
+}
+
+// needsResolution returns true if the user pool or its client is referred by name
+func (s AnnotationSchemaIDPCognito) needsResolution() bool {
+	return s.UserPoolName != "" || s.UserPoolClientName != ""
+}
+
 // the annotation schema for configuring IDPOIDC
 // You can specify clientId & ClientSecret directly, or configure it as k8s secret
 // The secret should be in same namespace as ingress/service and configured as "clientId: base64(ClientId) clientSecret: base64(ClientSecret)"
+// AuthorizationEndpoint, TokenEndpoint and UserInfoEndpoint can be omitted, they'll be discovered from the Issuer's /.well-known/openid-configuration
+// Alternatively, the clientId & ClientSecret can be stored in AWS as a Secrets Manager secret or a SSM SecureString parameter referenced by SecretArn,
+// whose value is configured as "{"clientId": "ClientId", "clientSecret": "ClientSecret"}"
 type AnnotationSchemaIDPOIDC struct {
 	AuthenticationRequestExtraParams AuthenticationRequestExtraParams
 	AuthorizationEndpoint            string
@@ -63,4 +84,10 @@ type AnnotationSchemaIDPOIDC struct {
 	UserInfoEndpoint                 string
 
 	SecretName string
+	SecretArn  string
+}
+
+// needsDiscovery returns true if any endpoint should be discovered from the Issuer
+func (s AnnotationSchemaIDPOIDC) needsDiscovery() bool {
+	return s.Issuer != "" && (s.AuthorizationEndpoint == "" || s.TokenEndpoint == "" || s.UserInfoEndpoint == "")
 }
@@ -27,7 +27,9 @@ import (
 	api "k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
@@ -41,6 +43,7 @@ const (
 type EndpointResolver interface {
 	Resolve(*extensions.Ingress, *extensions.IngressBackend, string) ([]*elbv2.TargetDescription, error)
 	ReverseResolve(*extensions.Ingress, *extensions.IngressBackend, []*elbv2.TargetDescription) ([]*corev1.Pod, error)
+	ResolvePods(*extensions.Ingress, *extensions.IngressBackend) ([]*corev1.Pod, error)
 }
 
 // NewEndpointResolver constructs a new EndpointResolver
@@ -69,10 +72,9 @@ func (resolver *endpointResolver) ReverseResolve(ingress *extensions.Ingress, ba
 	if err != nil {
 		return nil, err
 	}
-	serviceKey := ingress.Namespace + "/" + service.Name
-	eps, err := resolver.store.GetServiceEndpoints(serviceKey)
+	svcEndpoints, err := resolver.findServiceEndpoints(service, servicePort)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to find service endpoints for %s: %v", serviceKey, err.Error())
+		return nil, err
 	}
 
 	targetsMap := map[string]int{}
@@ -81,32 +83,49 @@ func (resolver *endpointResolver) ReverseResolve(ingress *extensions.Ingress, ba
 	}
 
 	result := make([]*corev1.Pod, len(targets))
-	for _, epSubset := range eps.Subsets {
-		for _, epPort := range epSubset.Ports {
-			// servicePort.Name is optional if there is only one port
-			if servicePort.Name != "" && servicePort.Name != epPort.Name {
-				continue
-			}
-			for _, epAddr := range append(epSubset.Addresses, epSubset.NotReadyAddresses...) {
-				if epAddr.TargetRef == nil || epAddr.TargetRef.Kind != "Pod" {
-					continue
-				}
-
-				podKey := ingress.Namespace + "/" + epAddr.TargetRef.Name
-				pod, err := resolver.store.GetPod(podKey)
-				if err != nil {
-					continue
-				}
-
-				if i, ok := targetsMap[pod.Status.PodIP]; ok {
-					result[i] = pod
-				}
-			}
+	for _, svcEndpoint := range svcEndpoints {
+		if svcEndpoint.TargetRef == nil || svcEndpoint.TargetRef.Kind != "Pod" {
+			continue
+		}
+
+		podKey := ingress.Namespace + "/" + svcEndpoint.TargetRef.Name
+		pod, err := resolver.store.GetPod(podKey)
+		if err != nil {
+			continue
+		}
+
+		if i, ok := targetsMap[pod.Status.PodIP]; ok {
+			result[i] = pod
 		}
 	}
 	return result, nil
 }
 
+// ResolvePods returns the pods of the endpoints of the ingress backend, whether they are ready or not.
+func (resolver *endpointResolver) ResolvePods(ingress *extensions.Ingress, backend *extensions.IngressBackend) ([]*corev1.Pod, error) {
+	service, servicePort, err := findServiceAndPort(resolver.store, ingress.Namespace, backend.ServiceName, backend.ServicePort)
+	if err != nil {
+		return nil, err
+	}
+	svcEndpoints, err := resolver.findServiceEndpoints(service, servicePort)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*corev1.Pod
+	for _, svcEndpoint := range svcEndpoints {
+		if svcEndpoint.TargetRef == nil || svcEndpoint.TargetRef.Kind != "Pod" {
+			continue
+		}
+		pod, err := resolver.store.GetPod(ingress.Namespace + "/" + svcEndpoint.TargetRef.Name)
+		if err != nil {
+			continue
+		}
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
 func (resolver *endpointResolver) resolveInstance(ingress *extensions.Ingress, backend *extensions.IngressBackend) ([]*elbv2.TargetDescription, error) {
 	service, servicePort, err := findServiceAndPort(resolver.store, ingress.Namespace, backend.ServiceName, backend.ServicePort)
 	if err != nil {
@@ -117,11 +136,39 @@ func (resolver *endpointResolver) resolveInstance(ingress *extensions.Ingress, b
 	}
 	nodePort := servicePort.NodePort
 
+	ingressAnnos, err := resolver.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ingressAnnotation due to %v", err)
+	}
+	serviceAnnos, err := resolver.store.GetServiceAnnotations(ingress.Namespace+"/"+service.Name, ingressAnnos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load serviceAnnotation due to %v", err)
+	}
+	targetNodeSelector := serviceAnnos.TargetGroup.TargetNodeSelector
+
+	// with externalTrafficPolicy Local, kube-proxy only forwards traffic to the endpoints on the same node,
+	// so only the nodes hosting ready endpoints are registered.
+	var localNodeNames sets.String
+	if service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		if localNodeNames, err = resolver.findReadyEndpointNodeNames(service, servicePort); err != nil {
+			return nil, err
+		}
+	}
+
 	var result []*elbv2.TargetDescription
 	for _, node := range resolver.store.ListNodes() {
 		if !IsNodeSuitableAsTrafficProxy(node) {
 			continue
 		}
+		if _, tainted := FindNodeExclusionTaint(node, resolver.store.GetConfig().NodeExclusionTaints); tainted {
+			continue
+		}
+		if localNodeNames != nil && !localNodeNames.Has(node.Name) {
+			continue
+		}
+		if targetNodeSelector != nil && !targetNodeSelector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
 		instanceID, err := resolver.store.GetNodeInstanceID(node)
 		if err != nil {
 			return nil, err
@@ -134,15 +181,41 @@ func (resolver *endpointResolver) resolveInstance(ingress *extensions.Ingress, b
 	return result, nil
 }
 
+// findReadyEndpointNodeNames returns the names of nodes hosting ready endpoints of servicePort of service.
+// If none of the endpoints are ready, the nodes hosting endpoints still serving while terminating are returned,
+// like kube-proxy falls back to them.
+func (resolver *endpointResolver) findReadyEndpointNodeNames(service *corev1.Service, servicePort *corev1.ServicePort) (sets.String, error) {
+	svcEndpoints, err := resolver.findServiceEndpoints(service, servicePort)
+	if err != nil {
+		return nil, err
+	}
+
+	readyNodeNames := sets.NewString()
+	servingNodeNames := sets.NewString()
+	for _, svcEndpoint := range svcEndpoints {
+		if svcEndpoint.NodeName == nil {
+			continue
+		}
+		if svcEndpoint.Ready {
+			readyNodeNames.Insert(*svcEndpoint.NodeName)
+		} else if svcEndpoint.Serving {
+			servingNodeNames.Insert(*svcEndpoint.NodeName)
+		}
+	}
+	if readyNodeNames.Len() == 0 {
+		return servingNodeNames, nil
+	}
+	return readyNodeNames, nil
+}
+
 func (resolver *endpointResolver) resolveIP(ingress *extensions.Ingress, backend *extensions.IngressBackend) ([]*elbv2.TargetDescription, error) {
 	service, servicePort, err := findServiceAndPort(resolver.store, ingress.Namespace, backend.ServiceName, backend.ServicePort)
 	if err != nil {
 		return nil, err
 	}
-	serviceKey := ingress.Namespace + "/" + service.Name
-	eps, err := resolver.store.GetServiceEndpoints(serviceKey)
+	svcEndpoints, err := resolver.findServiceEndpoints(service, servicePort)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to find service endpoints for %s: %v", serviceKey, err.Error())
+		return nil, err
 	}
 
 	readinessConditionTypes := []api.PodConditionType{
@@ -150,52 +223,46 @@ func (resolver *endpointResolver) resolveIP(ingress *extensions.Ingress, backend
 		AnyLBTGReadyConditionType,
 	}
 	var result []*elbv2.TargetDescription
-	for _, epSubset := range eps.Subsets {
-		for _, epPort := range epSubset.Ports {
-			// servicePort.Name is optional if there is only one port
-			if servicePort.Name != "" && servicePort.Name != epPort.Name {
-				continue
-			}
-
-			addresses := epSubset.Addresses
-
-			// we need to loop over all unready pods to check if the ALB readiness gate is the condition preventing the pod from being ready;
-			// if this is the case, we return the pod as a desired target although its not in `Addresses`
-			for _, epAddr := range epSubset.NotReadyAddresses {
-				if epAddr.TargetRef == nil || epAddr.TargetRef.Kind != "Pod" {
-					continue
-				}
-
-				podKey := ingress.Namespace + "/" + epAddr.TargetRef.Name
-				pod, err := resolver.store.GetPod(podKey)
-				if err != nil || !IsPodSuitableAsIPTarget(pod) {
-					continue
-				}
-
-				// check if pod has a readiness gate for this ingress and backend
-				foundAnyReadinessGate := false
-				for _, conditionType := range readinessConditionTypes {
-					if PodHasReadinessGate(pod, conditionType) {
-						foundAnyReadinessGate = true
-						break
-					}
-				}
-				if foundAnyReadinessGate {
-					addresses = append(addresses, epAddr)
-				}
-			}
-			for _, epAddr := range addresses {
-				result = append(result, &elbv2.TargetDescription{
-					Id:   aws.String(epAddr.IP),
-					Port: aws.Int64(int64(epPort.Port)),
-				})
-			}
+	for _, svcEndpoint := range svcEndpoints {
+		// terminating endpoints are deregistered right away, so they drain while the pod shuts down
+		if svcEndpoint.Terminating {
+			continue
 		}
+		// we need to check all unready pods if the ALB readiness gate is the condition preventing the pod from being ready;
+		// if this is the case, we return the pod as a desired target although it's not ready
+		if !svcEndpoint.Ready && !resolver.isPodPendingReadinessGate(ingress.Namespace, svcEndpoint, readinessConditionTypes) {
+			continue
+		}
+		result = append(result, &elbv2.TargetDescription{
+			Id:   aws.String(svcEndpoint.IP),
+			Port: aws.Int64(int64(svcEndpoint.Port)),
+		})
 	}
 
 	return result, nil
 }
 
+// isPodPendingReadinessGate checks whether the not ready svcEndpoint is a pod with all containers ready, which has one of the readinessConditionTypes as readiness gate.
+func (resolver *endpointResolver) isPodPendingReadinessGate(namespace string, svcEndpoint serviceEndpoint, readinessConditionTypes []api.PodConditionType) bool {
+	if svcEndpoint.TargetRef == nil || svcEndpoint.TargetRef.Kind != "Pod" {
+		return false
+	}
+
+	podKey := namespace + "/" + svcEndpoint.TargetRef.Name
+	pod, err := resolver.store.GetPod(podKey)
+	if err != nil || !IsPodSuitableAsIPTarget(pod) {
+		return false
+	}
+
+	// check if pod has a readiness gate for this ingress and backend
+	for _, conditionType := range readinessConditionTypes {
+		if PodHasReadinessGate(pod, conditionType) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsNodeSuitableAsTrafficProxy check whether node is suitable as a traffic proxy.
 // mimic the logic of serviceController: https://github.com/kubernetes/kubernetes/blob/b6b494b4484b51df8dc6b692fab234573da30ab4/pkg/controller/service/controller.go#L605
 func IsNodeSuitableAsTrafficProxy(node *corev1.Node) bool {
@@ -218,9 +285,25 @@ func IsNodeSuitableAsTrafficProxy(node *corev1.Node) bool {
 	return false
 }
 
+// FindNodeExclusionTaint returns the first taint of node whose key is in taintKeys, like the taint put by a spot
+// interruption handler on nodes about to be terminated.
+func FindNodeExclusionTaint(node *corev1.Node, taintKeys []string) (corev1.Taint, bool) {
+	for _, taint := range node.Spec.Taints {
+		for _, taintKey := range taintKeys {
+			if taint.Key == taintKey {
+				return taint, true
+			}
+		}
+	}
+	return corev1.Taint{}, false
+}
+
 // IsPodSuitableAsIPTarget check whether pod is suitable as a TargetGroup's target
-// (currently tested: are all pod's containers ready?).
+// (currently tested: is the pod not terminating and are all pod's containers ready?).
 func IsPodSuitableAsIPTarget(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
 	for _, condition := range pod.Status.Conditions {
 		if condition.Type == api.ContainersReady {
 			return condition.Status == api.ConditionTrue
@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 
@@ -32,6 +33,7 @@ import (
 	extensions "k8s.io/api/extensions/v1beta1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -44,12 +46,14 @@ func TestResolveWithModeInstance(t *testing.T) {
 	const nodePort = 8888
 
 	for _, tc := range []struct {
-		name            string
-		ingress         *extensions.Ingress
-		service         *api_v1.Service
-		nodes           []*api_v1.Node
-		expectedTargets []*elbv2.TargetDescription
-		expectedError   bool
+		name               string
+		ingress            *extensions.Ingress
+		service            *api_v1.Service
+		endpoints          *api_v1.Endpoints
+		targetNodeSelector string
+		nodes              []*api_v1.Node
+		expectedTargets    []*elbv2.TargetDescription
+		expectedError      bool
 	}{
 		{
 			name: "success scenario by numeric service port",
@@ -215,6 +219,308 @@ func TestResolveWithModeInstance(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "success scenario with externalTrafficPolicy Local",
+			ingress: &extensions.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+				},
+			},
+			service: &api_v1.Service{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: api_v1.ServiceSpec{
+					Type:                  api_v1.ServiceTypeLoadBalancer,
+					ExternalTrafficPolicy: api_v1.ServiceExternalTrafficPolicyTypeLocal,
+					Ports: []api_v1.ServicePort{
+						{
+							Name:     "http",
+							NodePort: nodePort,
+						},
+					},
+				},
+			},
+			endpoints: &api_v1.Endpoints{
+				Subsets: []api_v1.EndpointSubset{
+					{
+						Addresses: []api_v1.EndpointAddress{
+							{
+								IP:       "192.168.1.1",
+								NodeName: aws.String(nodeName1),
+							},
+						},
+						NotReadyAddresses: []api_v1.EndpointAddress{
+							{
+								IP:       "192.168.1.2",
+								NodeName: aws.String(nodeName3),
+							},
+						},
+						Ports: []api_v1.EndpointPort{
+							{
+								Name: "http",
+								Port: 8080,
+							},
+						},
+					},
+					{
+						Addresses: []api_v1.EndpointAddress{
+							{
+								IP:       "192.168.1.3",
+								NodeName: aws.String(nodeName3),
+							},
+						},
+						Ports: []api_v1.EndpointPort{
+							{
+								Name: "https",
+								Port: 8443,
+							},
+						},
+					},
+				},
+			},
+			nodes: []*api_v1.Node{
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name: nodeName1,
+					},
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName1,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name: nodeName2,
+					},
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName2,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name: nodeName3,
+					},
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName3,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+			},
+			expectedTargets: []*elbv2.TargetDescription{
+				{
+					Id:   &nodeName1,
+					Port: aws.Int64(nodePort),
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "success scenario with target-node-labels",
+			ingress: &extensions.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+				},
+			},
+			service: &api_v1.Service{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: api_v1.ServiceSpec{
+					Type: api_v1.ServiceTypeNodePort,
+					Ports: []api_v1.ServicePort{
+						{
+							Name:     "http",
+							NodePort: nodePort,
+						},
+					},
+				},
+			},
+			targetNodeSelector: "node-pool=ingress",
+			nodes: []*api_v1.Node{
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Labels: map[string]string{"node-pool": "ingress"},
+					},
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName1,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Labels: map[string]string{"node-pool": "default"},
+					},
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName2,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName3,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+			},
+			expectedTargets: []*elbv2.TargetDescription{
+				{
+					Id:   &nodeName1,
+					Port: aws.Int64(nodePort),
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "success scenario with cordoned and tainted nodes",
+			ingress: &extensions.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("http"),
+					},
+				},
+			},
+			service: &api_v1.Service{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: api_v1.ServiceSpec{
+					Type: api_v1.ServiceTypeNodePort,
+					Ports: []api_v1.ServicePort{
+						{
+							Name:     "http",
+							NodePort: nodePort,
+						},
+					},
+				},
+			},
+			nodes: []*api_v1.Node{
+				{
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName1,
+						Taints: []api_v1.Taint{
+							{
+								Key:    "dedicated",
+								Effect: api_v1.TaintEffectNoSchedule,
+							},
+						},
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					Spec: api_v1.NodeSpec{
+						ProviderID:    nodeName2,
+						Unschedulable: true,
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+				{
+					Spec: api_v1.NodeSpec{
+						ProviderID: nodeName3,
+						Taints: []api_v1.Taint{
+							{
+								Key:    "aws-node-termination-handler/spot-itn",
+								Effect: api_v1.TaintEffectNoSchedule,
+							},
+						},
+					},
+					Status: api_v1.NodeStatus{
+						Conditions: []api_v1.NodeCondition{
+							{
+								Type:   api_v1.NodeReady,
+								Status: api_v1.ConditionTrue,
+							},
+						},
+					},
+				},
+			},
+			expectedTargets: []*elbv2.TargetDescription{
+				{
+					Id:   &nodeName1,
+					Port: aws.Int64(nodePort),
+				},
+			},
+			expectedError: false,
+		},
 		{
 			name: "failure scenario by service not found",
 			ingress: &extensions.Ingress{
@@ -313,9 +619,23 @@ func TestResolveWithModeInstance(t *testing.T) {
 			store.ListNodesFunc = func() []*api_v1.Node {
 				return tc.nodes
 			}
+			store.GetServiceEndpointsFunc = func(string) (*api_v1.Endpoints, error) {
+				if tc.endpoints != nil {
+					return tc.endpoints, nil
+				}
+				return nil, fmt.Errorf("No such endpoints")
+			}
 			store.GetNodeInstanceIDFunc = func(node *api_v1.Node) (string, error) {
 				return node.Spec.ProviderID, nil
 			}
+			store.SetConfig(&config.Configuration{
+				NodeExclusionTaints: []string{"aws-node-termination-handler/spot-itn"},
+			})
+			if tc.targetNodeSelector != "" {
+				selector, err := labels.Parse(tc.targetNodeSelector)
+				assert.NoError(t, err)
+				store.GetServiceAnnotationsResponse.TargetGroup.TargetNodeSelector = selector
+			}
 
 			//  tc.nodeHealthProbe
 
@@ -394,6 +714,33 @@ func TestResolveWithModeIP(t *testing.T) {
 				},
 			},
 		},
+
+		// terminating pod with all containers ready
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "pod3",
+				Namespace:         api_v1.NamespaceDefault,
+				DeletionTimestamp: &v1.Time{},
+				Labels: map[string]string{
+					"app": "my-app",
+				},
+			},
+			Spec: api_v1.PodSpec{
+				ReadinessGates: []api_v1.PodReadinessGate{
+					{
+						ConditionType: api_v1.PodConditionType("target-health.alb.ingress.k8s.aws/ingress_service_https"),
+					},
+				},
+			},
+			Status: api_v1.PodStatus{
+				Conditions: []api_v1.PodCondition{
+					{
+						Type:   api_v1.ContainersReady,
+						Status: api_v1.ConditionTrue,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range []struct {
@@ -702,6 +1049,68 @@ func TestResolveWithModeIP(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "not ready addresses of terminating pods are ignored",
+			ingress: &extensions.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: extensions.IngressSpec{
+					Backend: &extensions.IngressBackend{
+						ServiceName: "service",
+						ServicePort: intstr.FromString("https"),
+					},
+				},
+			},
+			service: &api_v1.Service{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: api_v1.NamespaceDefault,
+				},
+				Spec: api_v1.ServiceSpec{
+					Type: api_v1.ServiceTypeClusterIP,
+					Ports: []api_v1.ServicePort{
+						{
+							Name: "https",
+						},
+					},
+				},
+			},
+			endpoints: &api_v1.Endpoints{
+				Subsets: []api_v1.EndpointSubset{
+					{
+						Addresses: []api_v1.EndpointAddress{
+							{
+								IP: ip1,
+							},
+						},
+						NotReadyAddresses: []api_v1.EndpointAddress{
+							{
+								IP: ip2,
+								TargetRef: &api_v1.ObjectReference{
+									Kind: "Pod",
+									Name: "pod3",
+								},
+							},
+						},
+						Ports: []api_v1.EndpointPort{
+							{
+								Name: "https",
+								Port: portHTTPS,
+							},
+						},
+					},
+				},
+			},
+			expectedTargets: []*elbv2.TargetDescription{
+				{
+					Id:   aws.String(ip1),
+					Port: aws.Int64(portHTTPS),
+				},
+			},
+			expectedError: false,
+		},
 		{
 			name: "failure scenario by no endpoint found",
 			ingress: &extensions.Ingress{
@@ -1229,6 +1638,85 @@ func TestReverseResolve(t *testing.T) {
 	}
 }
 
+func TestResolvePods(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "ingress", Namespace: api_v1.NamespaceDefault},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(8080)},
+		},
+	}
+	service := &api_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "service", Namespace: api_v1.NamespaceDefault},
+		Spec: api_v1.ServiceSpec{
+			Type:  api_v1.ServiceTypeClusterIP,
+			Ports: []api_v1.ServicePort{{Port: 8080}},
+		},
+	}
+	pod1 := &api_v1.Pod{ObjectMeta: v1.ObjectMeta{Name: "pod1", Namespace: api_v1.NamespaceDefault}}
+	pod2 := &api_v1.Pod{ObjectMeta: v1.ObjectMeta{Name: "pod2", Namespace: api_v1.NamespaceDefault}}
+
+	for _, tc := range []struct {
+		name          string
+		endpoints     *api_v1.Endpoints
+		expectedPods  []*api_v1.Pod
+		expectedError bool
+	}{
+		{
+			name: "pods of ready and not ready endpoints are resolved",
+			endpoints: &api_v1.Endpoints{
+				Subsets: []api_v1.EndpointSubset{
+					{
+						Addresses: []api_v1.EndpointAddress{
+							{IP: "192.168.1.1", TargetRef: &api_v1.ObjectReference{Kind: "Pod", Name: "pod1"}},
+							{IP: "192.168.1.3", TargetRef: &api_v1.ObjectReference{Kind: "Pod", Name: "unknown-pod"}},
+							{IP: "192.168.1.4"},
+						},
+						NotReadyAddresses: []api_v1.EndpointAddress{
+							{IP: "192.168.1.2", TargetRef: &api_v1.ObjectReference{Kind: "Pod", Name: "pod2"}},
+						},
+						Ports: []api_v1.EndpointPort{{Port: 8080}},
+					},
+				},
+			},
+			expectedPods: []*api_v1.Pod{pod1, pod2},
+		},
+		{
+			name:          "endpoints not found",
+			expectedError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.NewDummy()
+			s.GetServiceFunc = func(string) (*api_v1.Service, error) {
+				return service, nil
+			}
+			s.GetServiceEndpointsFunc = func(string) (*api_v1.Endpoints, error) {
+				if tc.endpoints != nil {
+					return tc.endpoints, nil
+				}
+				return nil, fmt.Errorf("No such endpoints")
+			}
+			s.GetPodFunc = func(key string) (*api_v1.Pod, error) {
+				for _, pod := range []*api_v1.Pod{pod1, pod2} {
+					if fmt.Sprintf("%s/%s", pod.Namespace, pod.Name) == key {
+						return pod, nil
+					}
+				}
+				return nil, store.NotExistsError(key)
+			}
+
+			resolver := NewEndpointResolver(s, &mocks.CloudAPI{})
+			pods, err := resolver.ResolvePods(ingress, ingress.Spec.Backend)
+			if !reflect.DeepEqual(tc.expectedPods, pods) {
+				t.Errorf("expected pods:%#v, actual pods: %#v", tc.expectedPods, pods)
+			}
+			if (err != nil) != tc.expectedError {
+				t.Errorf("expected error:%v, actual err:%v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
 func Test_IsNodeSuitableAsTrafficProxy(t *testing.T) {
 	tests := []struct {
 		name string
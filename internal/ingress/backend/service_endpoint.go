@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// endpointSliceAddressTypeIPv4 is the address type of EndpointSlices whose addresses can be registered as targets
+const endpointSliceAddressTypeIPv4 = "IPv4"
+
+// serviceEndpoint is an endpoint address of a service port, resolved from either EndpointSlices or Endpoints.
+type serviceEndpoint struct {
+	IP        string
+	Port      int32
+	NodeName  *string
+	TargetRef *corev1.ObjectReference
+
+	// Ready, Serving and Terminating are the conditions of the endpoint,
+	// Endpoints don't report terminating endpoints, so they're always serving if ready and never terminating.
+	Ready       bool
+	Serving     bool
+	Terminating bool
+}
+
+// findServiceEndpoints returns the endpoints of servicePort of service.
+// They're resolved from EndpointSlices if enabled, or from Endpoints otherwise.
+func (resolver *endpointResolver) findServiceEndpoints(service *corev1.Service, servicePort *corev1.ServicePort) ([]serviceEndpoint, error) {
+	serviceKey := service.Namespace + "/" + service.Name
+	if resolver.store.GetConfig().EnableEndpointSlices {
+		return resolver.findServiceEndpointsFromEndpointSlices(serviceKey, servicePort)
+	}
+	return resolver.findServiceEndpointsFromEndpoints(serviceKey, servicePort)
+}
+
+func (resolver *endpointResolver) findServiceEndpointsFromEndpoints(serviceKey string, servicePort *corev1.ServicePort) ([]serviceEndpoint, error) {
+	eps, err := resolver.store.GetServiceEndpoints(serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to find service endpoints for %s: %v", serviceKey, err.Error())
+	}
+
+	var result []serviceEndpoint
+	for _, epSubset := range eps.Subsets {
+		for _, epPort := range epSubset.Ports {
+			// servicePort.Name is optional if there is only one port
+			if servicePort.Name != "" && servicePort.Name != epPort.Name {
+				continue
+			}
+			for _, epAddr := range epSubset.Addresses {
+				result = append(result, serviceEndpoint{
+					IP:        epAddr.IP,
+					Port:      epPort.Port,
+					NodeName:  epAddr.NodeName,
+					TargetRef: epAddr.TargetRef,
+					Ready:     true,
+					Serving:   true,
+				})
+			}
+			for _, epAddr := range epSubset.NotReadyAddresses {
+				result = append(result, serviceEndpoint{
+					IP:        epAddr.IP,
+					Port:      epPort.Port,
+					NodeName:  epAddr.NodeName,
+					TargetRef: epAddr.TargetRef,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (resolver *endpointResolver) findServiceEndpointsFromEndpointSlices(serviceKey string, servicePort *corev1.ServicePort) ([]serviceEndpoint, error) {
+	endpointSlices, err := resolver.store.ListServiceEndpointSlices(serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to find service endpoint slices for %s: %v", serviceKey, err.Error())
+	}
+
+	var readyEndpoints, notReadyEndpoints []serviceEndpoint
+	// an endpoint can be transiently part of multiple slices while it's moved between them
+	seenEndpoints := sets.NewString()
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType != endpointSliceAddressTypeIPv4 {
+			continue
+		}
+		for _, port := range endpointSlice.Ports {
+			// servicePort.Name is optional if there is only one port
+			if servicePort.Name != "" && servicePort.Name != aws.StringValue(port.Name) {
+				continue
+			}
+			if port.Port == nil {
+				continue
+			}
+			for _, endpoint := range endpointSlice.Endpoints {
+				// consumers should only use the first address of an endpoint, the others are duplicates
+				if len(endpoint.Addresses) == 0 {
+					continue
+				}
+				endpointKey := fmt.Sprintf("%v:%v", endpoint.Addresses[0], *port.Port)
+				if seenEndpoints.Has(endpointKey) {
+					continue
+				}
+				seenEndpoints.Insert(endpointKey)
+
+				svcEndpoint := serviceEndpoint{
+					IP:          endpoint.Addresses[0],
+					Port:        *port.Port,
+					NodeName:    endpoint.NodeName,
+					TargetRef:   endpoint.TargetRef,
+					Ready:       endpoint.Conditions.IsReady(),
+					Serving:     endpoint.Conditions.IsServing(),
+					Terminating: endpoint.Conditions.IsTerminating(),
+				}
+				if svcEndpoint.Ready {
+					readyEndpoints = append(readyEndpoints, svcEndpoint)
+				} else {
+					notReadyEndpoints = append(notReadyEndpoints, svcEndpoint)
+				}
+			}
+		}
+	}
+	return append(readyEndpoints, notReadyEndpoints...), nil
+}
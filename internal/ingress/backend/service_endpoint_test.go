@@ -0,0 +1,225 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newTestEndpoint(ip string, podName string, nodeName string, ready bool, serving bool, terminating bool) k8s.Endpoint {
+	return k8s.Endpoint{
+		Addresses: []string{ip},
+		Conditions: k8s.EndpointConditions{
+			Ready:       aws.Bool(ready),
+			Serving:     aws.Bool(serving),
+			Terminating: aws.Bool(terminating),
+		},
+		TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: podName},
+		NodeName:  aws.String(nodeName),
+	}
+}
+
+func TestFindServiceEndpointsFromEndpointSlices(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "service"},
+	}
+	httpPorts := []k8s.EndpointPort{{Name: aws.String("http"), Port: aws.Int32(8080)}}
+
+	for _, tc := range []struct {
+		name             string
+		servicePort      *corev1.ServicePort
+		endpointSlices   []*k8s.EndpointSlice
+		expectedEndpoint []serviceEndpoint
+	}{
+		{
+			name:        "ready endpoints first, along with conditions",
+			servicePort: &corev1.ServicePort{Name: "http"},
+			endpointSlices: []*k8s.EndpointSlice{
+				{
+					AddressType: "IPv4",
+					Ports:       httpPorts,
+					Endpoints: []k8s.Endpoint{
+						newTestEndpoint("10.0.0.1", "terminating", "node-a", false, true, true),
+						newTestEndpoint("10.0.0.2", "ready", "node-b", true, true, false),
+						{Addresses: []string{"10.0.0.3"}},
+					},
+				},
+			},
+			expectedEndpoint: []serviceEndpoint{
+				{IP: "10.0.0.2", Port: 8080, NodeName: aws.String("node-b"), TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "ready"}, Ready: true, Serving: true},
+				{IP: "10.0.0.3", Port: 8080, Ready: true, Serving: true},
+				{IP: "10.0.0.1", Port: 8080, NodeName: aws.String("node-a"), TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "terminating"}, Serving: true, Terminating: true},
+			},
+		},
+		{
+			name:        "other ports, non IPv4 slices and duplicate endpoints are ignored",
+			servicePort: &corev1.ServicePort{Name: "http"},
+			endpointSlices: []*k8s.EndpointSlice{
+				{
+					AddressType: "IPv4",
+					Ports:       append(httpPorts, k8s.EndpointPort{Name: aws.String("https"), Port: aws.Int32(8443)}),
+					Endpoints:   []k8s.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+				},
+				{
+					AddressType: "IPv4",
+					Ports:       httpPorts,
+					Endpoints:   []k8s.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+				},
+				{
+					AddressType: "IPv6",
+					Ports:       httpPorts,
+					Endpoints:   []k8s.Endpoint{{Addresses: []string{"fd00::1"}}},
+				},
+			},
+			expectedEndpoint: []serviceEndpoint{
+				{IP: "10.0.0.1", Port: 8080, Ready: true, Serving: true},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.NewDummy()
+			s.SetConfig(&config.Configuration{EnableEndpointSlices: true})
+			s.ListServiceEndpointSlicesFunc = func(key string) ([]*k8s.EndpointSlice, error) {
+				assert.Equal(t, "namespace/service", key)
+				return tc.endpointSlices, nil
+			}
+			resolver := &endpointResolver{store: s}
+			svcEndpoints, err := resolver.findServiceEndpoints(service, tc.servicePort)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEndpoint, svcEndpoints)
+		})
+	}
+}
+
+func TestResolveWithEndpointSlices(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "ingress"},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromString("http")},
+		},
+	}
+	readinessGate := corev1.PodReadinessGate{ConditionType: "target-health.alb.ingress.k8s.aws/ingress_service_http"}
+	pods := map[string]*corev1.Pod{
+		"default/ready": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "ready"},
+			Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+		},
+		"default/pending-readiness-gate": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "pending-readiness-gate"},
+			Spec:       corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{readinessGate}},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.2",
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		},
+		"default/terminating": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "terminating"},
+			Spec:       corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{readinessGate}},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.3",
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		},
+	}
+
+	s := store.NewDummy()
+	s.SetConfig(&config.Configuration{EnableEndpointSlices: true})
+	s.GetServiceFunc = func(string) (*corev1.Service, error) {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "service"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeClusterIP,
+				Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}, nil
+	}
+	s.ListServiceEndpointSlicesFunc = func(string) ([]*k8s.EndpointSlice, error) {
+		return []*k8s.EndpointSlice{
+			{
+				AddressType: "IPv4",
+				Ports:       []k8s.EndpointPort{{Name: aws.String("http"), Port: aws.Int32(8080)}},
+				Endpoints: []k8s.Endpoint{
+					newTestEndpoint("10.0.0.1", "ready", "node-a", true, true, false),
+					newTestEndpoint("10.0.0.2", "pending-readiness-gate", "node-a", false, false, false),
+					newTestEndpoint("10.0.0.3", "terminating", "node-b", false, true, true),
+				},
+			},
+		}, nil
+	}
+	s.GetPodFunc = func(key string) (*corev1.Pod, error) {
+		if pod, ok := pods[key]; ok {
+			return pod, nil
+		}
+		return nil, fmt.Errorf("no such pod")
+	}
+	resolver := NewEndpointResolver(s, &mocks.CloudAPI{})
+
+	targets, err := resolver.Resolve(ingress, ingress.Spec.Backend, elbv2.TargetTypeEnumIp)
+	assert.NoError(t, err)
+	assert.Equal(t, []*elbv2.TargetDescription{
+		{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)},
+		{Id: aws.String("10.0.0.2"), Port: aws.Int64(8080)},
+	}, targets)
+
+	// terminating pods are still resolved from their targets, until they're removed from the EndpointSlice
+	resolvedPods, err := resolver.ReverseResolve(ingress, ingress.Spec.Backend, []*elbv2.TargetDescription{
+		{Id: aws.String("10.0.0.3"), Port: aws.Int64(8080)},
+		{Id: aws.String("10.0.0.4"), Port: aws.Int64(8080)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*corev1.Pod{pods["default/terminating"], nil}, resolvedPods)
+}
+
+func TestFindReadyEndpointNodeNamesWithEndpointSlices(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "service"},
+	}
+	ports := []k8s.EndpointPort{{Port: aws.Int32(8080)}}
+
+	for _, tc := range []struct {
+		name              string
+		endpoints         []k8s.Endpoint
+		expectedNodeNames []string
+	}{
+		{
+			name: "nodes hosting ready endpoints",
+			endpoints: []k8s.Endpoint{
+				newTestEndpoint("10.0.0.1", "ready", "node-a", true, true, false),
+				newTestEndpoint("10.0.0.2", "terminating", "node-b", false, true, true),
+				newTestEndpoint("10.0.0.3", "not-ready", "node-c", false, false, false),
+			},
+			expectedNodeNames: []string{"node-a"},
+		},
+		{
+			name: "nodes hosting serving endpoints if none is ready",
+			endpoints: []k8s.Endpoint{
+				newTestEndpoint("10.0.0.2", "terminating", "node-b", false, true, true),
+				newTestEndpoint("10.0.0.3", "not-ready", "node-c", false, false, false),
+			},
+			expectedNodeNames: []string{"node-b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.NewDummy()
+			s.SetConfig(&config.Configuration{EnableEndpointSlices: true})
+			s.ListServiceEndpointSlicesFunc = func(string) ([]*k8s.EndpointSlice, error) {
+				return []*k8s.EndpointSlice{{AddressType: "IPv4", Ports: ports, Endpoints: tc.endpoints}}, nil
+			}
+			resolver := &endpointResolver{store: s}
+			nodeNames, err := resolver.findReadyEndpointNodeNames(service, &corev1.ServicePort{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNodeNames, nodeNames.List())
+		})
+	}
+}
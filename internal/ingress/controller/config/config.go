@@ -6,6 +6,7 @@ import (
 	"hash/crc32"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/golang/glog"
@@ -24,10 +25,28 @@ const (
 	defaultRestrictSchemeNamespace = corev1.NamespaceDefault
 	defaultSyncRateLimit           = 0.3
 	defaultMaxConcurrentReconciles = 1
+	defaultEnableEndpointSlices    = true
+
+	defaultOIDCDiscoveryRefreshInterval = 15 * time.Minute
+	defaultAuthSecretRefreshInterval    = 5 * time.Minute
+	defaultAuthSecretNamePrefix         = "alb-ingress/"
+	defaultLambdaFunctionNamePrefix     = "alb-ingress-"
+
+	defaultTargetGroupReplacementHealthyFraction = 1.0
+	defaultTargetGroupReplacementTimeout         = 5 * time.Minute
 )
 
 var (
 	defaultDefaultTags = map[string]string{}
+
+	// defaultNodeExclusionTaints are the taints of nodes about to be terminated by the
+	// aws-node-termination-handler or the cluster-autoscaler
+	defaultNodeExclusionTaints = []string{
+		"aws-node-termination-handler/spot-itn",
+		"aws-node-termination-handler/asg-lifecycle-termination",
+		"aws-node-termination-handler/scheduled-maintenance",
+		"ToBeDeletedByClusterAutoscaler",
+	}
 )
 
 // Configuration contains all the settings required by an Ingress controller
@@ -49,6 +68,32 @@ type Configuration struct {
 	RestrictScheme          bool
 	RestrictSchemeNamespace string
 
+	// OIDCDiscoveryRefreshInterval is the interval to refresh OIDC configurations discovered from issuers
+	OIDCDiscoveryRefreshInterval time.Duration
+
+	// AuthSecretRefreshInterval is the interval to check OIDC client secrets stored in AWS for rotation
+	AuthSecretRefreshInterval time.Duration
+
+	// AuthSecretNamePrefix is the prefix of the names of OIDC client secrets that can be resolved from AWS
+	AuthSecretNamePrefix string
+
+	// LambdaFunctionNamePrefix is the prefix of the names of lambda functions ingresses can forward to
+	LambdaFunctionNamePrefix string
+
+	// NodeExclusionTaints are the taint keys of nodes to deregister from instance targetGroups
+	NodeExclusionTaints []string
+
+	// EnableEndpointSlices resolves targets from EndpointSlices instead of Endpoints,
+	// it's turned off on clusters not serving EndpointSlices
+	EnableEndpointSlices bool
+
+	// TargetGroupReplacementHealthyFraction is the fraction of targets of a target group replacing another that must be healthy
+	// before rules switch over to it
+	TargetGroupReplacementHealthyFraction float64
+
+	// TargetGroupReplacementTimeout is the time to wait for a target group replacing another to become healthy
+	TargetGroupReplacementTimeout time.Duration
+
 	// InternetFacingIngresses is an dynamic setting that can be updated by configMaps
 	InternetFacingIngresses map[string][]string
 
@@ -88,6 +133,22 @@ func (cfg *Configuration) BindFlags(fs *pflag.FlagSet) {
 		`Restrict the scheme to internal except for whitelisted namespaces`)
 	fs.StringVar(&cfg.RestrictSchemeNamespace, "restrict-scheme-namespace", defaultRestrictSchemeNamespace,
 		`The namespace with the ConfigMap containing the allowed ingresses. Only respected when restrict-scheme is true.`)
+	fs.DurationVar(&cfg.OIDCDiscoveryRefreshInterval, "oidc-discovery-refresh-interval", defaultOIDCDiscoveryRefreshInterval,
+		`The interval to refresh OIDC endpoints discovered from the issuer's well-known configuration`)
+	fs.DurationVar(&cfg.AuthSecretRefreshInterval, "auth-secret-refresh-interval", defaultAuthSecretRefreshInterval,
+		`The interval to check OIDC client secrets stored in AWS Secrets Manager or SSM Parameter Store for rotation`)
+	fs.StringVar(&cfg.AuthSecretNamePrefix, "auth-secret-name-prefix", defaultAuthSecretNamePrefix,
+		`The prefix of the names of Secrets Manager secrets and SSM parameters OIDC client secrets can be resolved from, an empty prefix allows any name`)
+	fs.StringVar(&cfg.LambdaFunctionNamePrefix, "lambda-function-name-prefix", defaultLambdaFunctionNamePrefix,
+		`The prefix of the names of lambda functions ingresses can forward to, an empty prefix allows any name`)
+	fs.StringSliceVar(&cfg.NodeExclusionTaints, "node-exclusion-taints", defaultNodeExclusionTaints,
+		`The taint keys of nodes to deregister from target groups of target-type instance, such as the spot interruption taint`)
+	fs.BoolVar(&cfg.EnableEndpointSlices, "enable-endpoint-slices", defaultEnableEndpointSlices,
+		`Resolve targets from EndpointSlices instead of Endpoints, falls back to Endpoints if the cluster doesn't serve discovery.k8s.io/v1`)
+	fs.Float64Var(&cfg.TargetGroupReplacementHealthyFraction, "target-group-replacement-healthy-fraction", defaultTargetGroupReplacementHealthyFraction,
+		`The fraction of targets of a target group replacing another that must be healthy before rules switch over to it, with the target-group-replacement feature`)
+	fs.DurationVar(&cfg.TargetGroupReplacementTimeout, "target-group-replacement-timeout", defaultTargetGroupReplacementTimeout,
+		`The time to wait for a target group replacing another to become healthy, with the target-group-replacement feature`)
 
 	cfg.FeatureGate.BindFlags(fs)
 }
@@ -130,6 +191,9 @@ func (cfg *Configuration) Validate() error {
 	if len(cfg.ALBNamePrefix) == 0 {
 		cfg.ALBNamePrefix = generateALBNamePrefix(cfg.ClusterName)
 	}
+	if cfg.TargetGroupReplacementHealthyFraction < 0 || cfg.TargetGroupReplacementHealthyFraction > 1 {
+		return fmt.Errorf("targetGroupReplacementHealthyFraction must be within 0-1")
+	}
 
 	// TODO: I know, bad smell here:D
 	parser.AnnotationsPrefix = cfg.AnnotationPrefix
@@ -30,6 +30,19 @@ const (
 	WAF            Feature = "waf"
 	WAFV2          Feature = "wafv2"
 	ShieldAdvanced Feature = "shield"
+
+	// PodDeregistrationSignal annotates terminating pods with the deregistration delay of their target groups of target-type ip
+	PodDeregistrationSignal Feature = "pod-deregistration-signal"
+
+	// InstanceTargetReadinessGate reconciles pod readiness gates of target groups of target-type instance from the health of the nodes forwarding to the pods
+	InstanceTargetReadinessGate Feature = "instance-target-readiness-gate"
+
+	// ReadinessProbeHealthCheck infers the health checks of target groups that aren't annotated from the readinessProbe of the backend pods
+	ReadinessProbeHealthCheck Feature = "readiness-probe-health-check"
+
+	// TargetGroupReplacement waits for target groups replacing others to become healthy before rules switch over to them,
+	// and deletes the replaced target groups once their deregistration delay has passed
+	TargetGroupReplacement Feature = "target-group-replacement"
 )
 
 type FeatureGate interface {
@@ -57,9 +70,13 @@ type defaultFeatureGate struct {
 func NewFeatureGate() FeatureGate {
 	return &defaultFeatureGate{
 		featureState: map[Feature]bool{
-			WAF:            true,
-			WAFV2:          true,
-			ShieldAdvanced: true,
+			WAF:                         true,
+			WAFV2:                       true,
+			ShieldAdvanced:              true,
+			PodDeregistrationSignal:     false,
+			InstanceTargetReadinessGate: false,
+			ReadinessProbeHealthCheck:   false,
+			TargetGroupReplacement:      false,
 		},
 	}
 }
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/golang/glog"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
@@ -18,8 +20,11 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/handlers"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -27,9 +32,29 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const oidcDiscoveryTimeout = 10 * time.Second
+
 func Initialize(config *config.Configuration, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI) error {
-	authModule := auth.NewModule(mgr.GetCache())
-	reconciler, err := newReconciler(config, mgr, mc, cloud, authModule)
+	if config.EnableEndpointSlices {
+		supported, err := k8s.IsEndpointSliceSupported(mgr.GetConfig())
+		if err != nil {
+			return fmt.Errorf("failed to check EndpointSlice support due to %v", err)
+		}
+		if !supported {
+			glog.Infof("EndpointSlices of %v are not served by the cluster, falling back to Endpoints", k8s.EndpointSliceGroupVersion)
+			config.EnableEndpointSlices = false
+		}
+	}
+	oidcDiscoverer := auth.NewOIDCDiscoverer(auth.NewOIDCDiscoveryHTTPClient(oidcDiscoveryTimeout), config.OIDCDiscoveryRefreshInterval)
+	if err := mgr.Add(oidcDiscoverer); err != nil {
+		return err
+	}
+	awsSecretResolver := auth.NewAWSSecretResolver(cloud, config.AuthSecretRefreshInterval, config.AuthSecretNamePrefix)
+	if err := mgr.Add(awsSecretResolver); err != nil {
+		return err
+	}
+	authModule := auth.NewModule(mgr.GetCache(), cloud, oidcDiscoverer, awsSecretResolver)
+	reconciler, targetsQueue, err := newReconciler(config, mgr, mc, cloud, authModule)
 	if err != nil {
 		return err
 	}
@@ -46,23 +71,37 @@ func Initialize(config *config.Configuration, mgr manager.Manager, mc metric.Col
 	if err := authModule.Init(c, ingressChan, serviceChan); err != nil {
 		return fmt.Errorf("failed to init auth module due to %v", err)
 	}
-	if err := watchClusterEvents(c, mgr.GetCache(), ingressChan, serviceChan, config.IngressClass); err != nil {
+	if err := watchClusterEvents(c, mgr.GetCache(), mgr.GetRecorder("alb-ingress-controller"), targetsQueue, ingressChan, serviceChan, config); err != nil {
 		return fmt.Errorf("failed to watch cluster events due to %v", err)
 	}
 
 	return nil
 }
 
-func newReconciler(config *config.Configuration, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI, authModule auth.Module) (reconcile.Reconciler, error) {
+func newReconciler(config *config.Configuration, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI, authModule auth.Module) (reconcile.Reconciler, tg.TargetsQueue, error) {
 	store, err := store.New(mgr, config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	client := mgr.GetClient()
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, nil, err
+	}
 	nameTagGenerator := generator.NewNameTagGenerator(*config)
 	tagsController := tags.NewController(cloud)
 	endpointResolver := backend.NewEndpointResolver(store, cloud)
-	tgGroupController := tg.NewGroupController(cloud, store, nameTagGenerator, tagsController, endpointResolver, client)
+	targetHealthCache := tg.NewTargetHealthCache(cloud, mc)
+	targetHealthController := tg.NewTargetHealthController(cloud, store, endpointResolver, client, kubeClient.CoreV1(), targetHealthCache, mc)
+	targetsController := tg.NewTargetsController(cloud, endpointResolver, targetHealthController, targetHealthCache)
+	targetsQueue := tg.NewTargetsQueue(targetsController, mgr.GetRecorder("alb-ingress-controller"), config.MaxConcurrentReconciles)
+	if err := mgr.Add(targetHealthController); err != nil {
+		return nil, nil, err
+	}
+	if err := mgr.Add(targetsQueue); err != nil {
+		return nil, nil, err
+	}
+	tgGroupController := tg.NewGroupController(cloud, store, nameTagGenerator, tagsController, targetsQueue, endpointResolver, targetHealthCache)
 	lsGroupController := ls.NewGroupController(store, cloud, authModule)
 	sgAssociationController := sg.NewAssociationController(store, cloud, tagsController, nameTagGenerator)
 	lbController := lb.NewController(cloud, store,
@@ -71,14 +110,16 @@ func newReconciler(config *config.Configuration, mgr manager.Manager, mc metric.
 	return &Reconciler{
 		client:          client,
 		cache:           mgr.GetCache(),
+		ingressClient:   kubeClient.ExtensionsV1beta1(),
 		recorder:        mgr.GetRecorder("alb-ingress-controller"),
 		store:           store,
 		lbController:    lbController,
 		metricCollector: mc,
-	}, nil
+	}, targetsQueue, nil
 }
 
-func watchClusterEvents(c controller.Controller, cache cache.Cache, ingressChan <-chan event.GenericEvent, serviceChan <-chan event.GenericEvent, ingressClass string) error {
+func watchClusterEvents(c controller.Controller, cache cache.Cache, recorder record.EventRecorder, targetsQueue tg.TargetsQueue, ingressChan <-chan event.GenericEvent, serviceChan <-chan event.GenericEvent, config *config.Configuration) error {
+	ingressClass := config.IngressClass
 	if err := c.Watch(&source.Kind{Type: &extensions.Ingress{}}, &handlers.EnqueueRequestsForIngressEvent{
 		IngressClass: ingressClass,
 	}); err != nil {
@@ -103,19 +144,44 @@ func watchClusterEvents(c controller.Controller, cache cache.Cache, ingressChan
 		return err
 	}
 
-	if err := c.Watch(&source.Kind{Type: &corev1.Endpoints{}}, &handlers.EnqueueRequestsForEndpointsEvent{
-		IngressClass: ingressClass,
-		Cache:        cache,
-	}); err != nil {
-		return err
+	if config.EnableEndpointSlices {
+		if err := c.Watch(&source.Kind{Type: k8s.NewUnstructuredEndpointSlice()}, &handlers.EnqueueRequestsForEndpointSliceEvent{
+			IngressClass: ingressClass,
+			TargetsQueue: targetsQueue,
+			Cache:        cache,
+		}); err != nil {
+			return err
+		}
+	} else {
+		if err := c.Watch(&source.Kind{Type: &corev1.Endpoints{}}, &handlers.EnqueueRequestsForEndpointsEvent{
+			IngressClass: ingressClass,
+			TargetsQueue: targetsQueue,
+			Cache:        cache,
+		}); err != nil {
+			return err
+		}
 	}
 	if err := c.Watch(&source.Kind{Type: &corev1.Node{}}, &handlers.EnqueueRequestsForNodeEvent{
-		IngressClass: ingressClass,
-		Cache:        cache,
+		IngressClass:        ingressClass,
+		NodeExclusionTaints: config.NodeExclusionTaints,
+		Cache:               cache,
+		Recorder:            recorder,
 	}); err != nil {
 		return err
 	}
 	if err := c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handlers.EnqueueRequestsForPodsEvent{
+		IngressClass:         ingressClass,
+		EnableEndpointSlices: config.EnableEndpointSlices,
+		TargetsQueue:         targetsQueue,
+		Cache:                cache,
+	}); err != nil {
+		return err
+	}
+
+	if err := cache.IndexField(&extensions.Ingress{}, handlers.FieldActionConfigMap, handlers.IndexActionConfigMaps); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handlers.EnqueueRequestsForConfigMapEvent{
 		IngressClass: ingressClass,
 		Cache:        cache,
 	}); err != nil {
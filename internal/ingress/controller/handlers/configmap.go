@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// FieldActionConfigMap indexes ingresses by the ConfigMaps referenced from their action annotations.
+const FieldActionConfigMap = "actionConfigMap"
+
+var _ handler.EventHandler = (*EnqueueRequestsForConfigMapEvent)(nil)
+
+// EnqueueRequestsForConfigMapEvent enqueues the ingresses impacted by a ConfigMap, referenced from action annotations or as auth profile.
+type EnqueueRequestsForConfigMapEvent struct {
+	IngressClass string
+
+	Cache cache.Cache
+}
+
+// Create is called in response to an create event - e.g. Pod Creation.
+func (h *EnqueueRequestsForConfigMapEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueImpactedIngresses(e.Object.(*corev1.ConfigMap), queue)
+}
+
+// Update is called in response to an update event -  e.g. Pod Updated.
+func (h *EnqueueRequestsForConfigMapEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueImpactedIngresses(e.ObjectNew.(*corev1.ConfigMap), queue)
+}
+
+// Delete is called in response to a delete event - e.g. Pod Deleted.
+func (h *EnqueueRequestsForConfigMapEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueImpactedIngresses(e.Object.(*corev1.ConfigMap), queue)
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request - e.g. reconcile Autoscaling, or a Webhook.
+func (h *EnqueueRequestsForConfigMapEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+// enqueueImpactedIngresses enqueues the ingresses referencing configMap from their action annotations or as auth profile,
+// along with the ingresses routing to services referencing it as auth profile.
+func (h *EnqueueRequestsForConfigMapEvent) enqueueImpactedIngresses(configMap *corev1.ConfigMap, queue workqueue.RateLimitingInterface) {
+	configMapKey := types.NamespacedName{
+		Namespace: configMap.Namespace,
+		Name:      configMap.Name,
+	}.String()
+
+	for _, field := range []string{FieldActionConfigMap, auth.FieldAuthProfile} {
+		ingressList := &extensions.IngressList{}
+		if err := h.Cache.List(context.Background(), client.MatchingField(field, configMapKey), ingressList); err != nil {
+			glog.Errorf("failed to fetch impacted ingresses by %v due to %v", field, err)
+			return
+		}
+		h.enqueueIngresses(ingressList.Items, queue)
+	}
+
+	serviceList := &corev1.ServiceList{}
+	if err := h.Cache.List(context.Background(), client.MatchingField(auth.FieldAuthProfile, configMapKey), serviceList); err != nil {
+		glog.Errorf("failed to fetch impacted services by %v due to %v", auth.FieldAuthProfile, err)
+		return
+	}
+	for _, namespace := range serviceNamespaces(serviceList.Items) {
+		ingressList := &extensions.IngressList{}
+		if err := h.Cache.List(context.Background(), client.InNamespace(namespace), ingressList); err != nil {
+			glog.Errorf("failed to fetch impacted ingresses by service due to %v", err)
+			return
+		}
+		h.enqueueIngresses(ingressList.Items, queue)
+	}
+}
+
+func (h *EnqueueRequestsForConfigMapEvent) enqueueIngresses(ingresses []extensions.Ingress, queue workqueue.RateLimitingInterface) {
+	for _, ingress := range ingresses {
+		if !class.IsValidIngress(h.IngressClass, &ingress) {
+			continue
+		}
+		queue.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: ingress.Namespace,
+				Name:      ingress.Name,
+			},
+		})
+	}
+}
+
+func serviceNamespaces(services []corev1.Service) []string {
+	namespaces := sets.NewString()
+	for _, service := range services {
+		namespaces.Insert(service.Namespace)
+	}
+	return namespaces.List()
+}
+
+// IndexActionConfigMaps returns the keys of ConfigMaps referenced from action annotations of an ingress.
+func IndexActionConfigMaps(obj runtime.Object) []string {
+	ingress := obj.(*extensions.Ingress)
+	raw, err := action.NewParser().Parse(ingress)
+	if err != nil {
+		return nil
+	}
+	var configMapKeys []string
+	for _, name := range raw.(*action.Config).ConfigMapNames() {
+		configMapKeys = append(configMapKeys, types.NamespacedName{
+			Namespace: ingress.Namespace,
+			Name:      name,
+		}.String())
+	}
+	return configMapKeys
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/auth"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIndexActionConfigMaps(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		annotations  map[string]string
+		expectedKeys []string
+	}{
+		{
+			name: "fixed response from ConfigMaps",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/actions.maintenance": `{"Type":"fixed-response","FixedResponseConfig":{"StatusCode":"503","ContentTypeFrom":{"Name":"content-types","Key":"html"},"MessageBodyFrom":{"Name":"maintenance","Key":"body.html"}}}`,
+			},
+			expectedKeys: []string{"namespace/content-types", "namespace/maintenance"},
+		},
+		{
+			name: "inline fixed response",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/actions.maintenance": `{"Type":"fixed-response","FixedResponseConfig":{"StatusCode":"503","ContentType":"text/plain","MessageBody":"maintenance"}}`,
+			},
+			expectedKeys: nil,
+		},
+		{
+			name: "invalid action annotation",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/actions.maintenance": `{`,
+			},
+			expectedKeys: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := &extensions.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "ingress",
+					Namespace:   "namespace",
+					Annotations: tc.annotations,
+				},
+			}
+			assert.Equal(t, tc.expectedKeys, IndexActionConfigMaps(ingress))
+		})
+	}
+}
+
+func TestEnqueueRequestsForConfigMapEvent_Update(t *testing.T) {
+	const namespace = "namespace"
+	const configMapKey = "namespace/maintenance"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock_cache.NewMockCache(ctrl)
+	mockCache.EXPECT().List(gomock.Any(), client.MatchingField(FieldActionConfigMap, configMapKey), &extensions.IngressList{}).SetArg(2, extensions.IngressList{
+		Items: []extensions.Ingress{
+			{ObjectMeta: v1.ObjectMeta{Name: "referencing-ingress", Namespace: namespace}},
+			{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "other-class-ingress",
+					Namespace:   namespace,
+					Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+				},
+			},
+		},
+	})
+	mockCache.EXPECT().List(gomock.Any(), client.MatchingField(auth.FieldAuthProfile, configMapKey), &extensions.IngressList{})
+	mockCache.EXPECT().List(gomock.Any(), client.MatchingField(auth.FieldAuthProfile, configMapKey), &corev1.ServiceList{})
+
+	handler := &EnqueueRequestsForConfigMapEvent{
+		Cache: mockCache,
+	}
+
+	queueMock := &mocks.RateLimitingInterface{}
+	queueMock.On("Add", reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      "referencing-ingress",
+		},
+	})
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "maintenance",
+			Namespace: namespace,
+		},
+	}
+	handler.Update(event.UpdateEvent{ObjectOld: configMap, ObjectNew: configMap}, queueMock)
+	queueMock.AssertExpectations(t)
+	queueMock.AssertNumberOfCalls(t, "Add", 1)
+}
@@ -22,7 +22,11 @@ var _ handler.EventHandler = (*EnqueueRequestsForEndpointsEvent)(nil)
 
 type EnqueueRequestsForEndpointsEvent struct {
 	IngressClass string
-	Cache        cache.Cache
+
+	// TargetsQueue reconciles only the targets of the target groups backed by the endpoints, once they're tracked
+	TargetsQueue tg.TargetsQueue
+
+	Cache cache.Cache
 }
 
 // Create is called in response to an create event - e.g. Pod Creation.
@@ -49,16 +53,20 @@ func (h *EnqueueRequestsForEndpointsEvent) Delete(e event.DeleteEvent, queue wor
 func (h *EnqueueRequestsForEndpointsEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
 }
 
-//TODO: this can be further optimized to only reconcile the target group referenced by the endpoints(service) :D
 func (h *EnqueueRequestsForEndpointsEvent) enqueueImpactedIngresses(endpoints *corev1.Endpoints, queue workqueue.RateLimitingInterface) {
+	enqueueIngressesReferencingService(h.Cache, h.TargetsQueue, h.IngressClass, endpoints.Namespace, endpoints.Name, queue)
+}
+
+// enqueueIngressesReferencingService enqueues the target groups of the ingresses of ingressClass in namespace with serviceName as backend.
+func enqueueIngressesReferencingService(cache cache.Cache, targetsQueue tg.TargetsQueue, ingressClass string, namespace string, serviceName string, queue workqueue.RateLimitingInterface) {
 	ingressList := &extensions.IngressList{}
-	if err := h.Cache.List(context.Background(), client.InNamespace(endpoints.Namespace), ingressList); err != nil {
+	if err := cache.List(context.Background(), client.InNamespace(namespace), ingressList); err != nil {
 		glog.Errorf("failed to fetch impacted ingresses by endpoints due to %v", err)
 		return
 	}
 
 	for _, ingress := range ingressList.Items {
-		if !class.IsValidIngress(h.IngressClass, &ingress) {
+		if !class.IsValidIngress(ingressClass, &ingress) {
 			continue
 		}
 
@@ -75,15 +83,23 @@ func (h *EnqueueRequestsForEndpointsEvent) enqueueImpactedIngresses(endpoints *c
 		}
 
 		for _, backend := range backends {
-			if backend.ServiceName == endpoints.Name {
-				queue.Add(reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Namespace: ingress.Namespace,
-						Name:      ingress.Name,
-					},
-				})
+			if backend.ServiceName == serviceName {
+				enqueueIngressTargetGroups(targetsQueue, &ingress, serviceName, queue)
 				break
 			}
 		}
 	}
 }
+
+// enqueueIngressTargetGroups enqueues the target groups of ingress backed by serviceName into targetsQueue,
+// or the ingress into queue if they aren't tracked yet, e.g. the ingress hasn't been reconciled since the controller started.
+func enqueueIngressTargetGroups(targetsQueue tg.TargetsQueue, ingress *extensions.Ingress, serviceName string, queue workqueue.RateLimitingInterface) {
+	ingressKey := types.NamespacedName{
+		Namespace: ingress.Namespace,
+		Name:      ingress.Name,
+	}
+	if targetsQueue != nil && targetsQueue.EnqueueTargetGroups(ingressKey, serviceName) {
+		return
+	}
+	queue.Add(reconcile.Request{NamespacedName: ingressKey})
+}
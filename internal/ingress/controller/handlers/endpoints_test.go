@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
 	corev1 "k8s.io/api/core/v1"
@@ -141,3 +142,59 @@ func TestEnqueueImpactedIngresses(t *testing.T) {
 	},
 		queueMock)
 }
+
+func TestEnqueueImpactedIngressesWithTargetsQueue(t *testing.T) {
+	const namespace = "namespace"
+	const service = "service"
+	newIngress := func(name string) extensions.Ingress {
+		return extensions.Ingress{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: extensions.IngressSpec{
+				Backend: &extensions.IngressBackend{
+					ServiceName: service,
+					ServicePort: intstr.FromInt(80),
+				},
+			},
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock_cache.NewMockCache(ctrl)
+	mockCache.EXPECT().List(gomock.Any(), client.InNamespace(namespace), &extensions.IngressList{}).SetArg(2, extensions.IngressList{
+		Items: []extensions.Ingress{newIngress("reconciled-ingress"), newIngress("new-ingress")},
+	})
+
+	targetsQueue := &tg.MockTargetsQueue{}
+	targetsQueue.On("EnqueueTargetGroups", types.NamespacedName{Namespace: namespace, Name: "reconciled-ingress"}, service).Return(true)
+	targetsQueue.On("EnqueueTargetGroups", types.NamespacedName{Namespace: namespace, Name: "new-ingress"}, service).Return(false)
+
+	handler := EnqueueRequestsForEndpointsEvent{
+		TargetsQueue: targetsQueue,
+		Cache:        mockCache,
+	}
+
+	// only the ingress without tracked target groups is fully reconciled
+	queueMock := &mocks.RateLimitingInterface{}
+	queueMock.On("Add", reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      "new-ingress",
+		},
+	})
+
+	handler.enqueueImpactedIngresses(&corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      service,
+			Namespace: namespace,
+		},
+	},
+		queueMock)
+	targetsQueue.AssertExpectations(t)
+	queueMock.AssertExpectations(t)
+	queueMock.AssertNumberOfCalls(t, "Add", 1)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"reflect"
+
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+var _ handler.EventHandler = (*EnqueueRequestsForEndpointSliceEvent)(nil)
+
+// EnqueueRequestsForEndpointSliceEvent enqueues the target groups of the ingresses referencing the Service of an EndpointSlice when it changes.
+type EnqueueRequestsForEndpointSliceEvent struct {
+	IngressClass string
+
+	// TargetsQueue reconciles only the targets of the target groups backed by the EndpointSlice, once they're tracked
+	TargetsQueue tg.TargetsQueue
+
+	Cache cache.Cache
+}
+
+// Create is called in response to an create event - e.g. Pod Creation.
+func (h *EnqueueRequestsForEndpointSliceEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueImpactedIngresses(e.Object, queue)
+}
+
+// Update is called in response to an update event -  e.g. Pod Updated.
+func (h *EnqueueRequestsForEndpointSliceEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	epsOld, errOld := convertEndpointSlice(e.ObjectOld)
+	epsNew, errNew := convertEndpointSlice(e.ObjectNew)
+	if errOld == nil && errNew == nil && reflect.DeepEqual(epsOld.Endpoints, epsNew.Endpoints) && reflect.DeepEqual(epsOld.Ports, epsNew.Ports) {
+		return
+	}
+	h.enqueueImpactedIngresses(e.ObjectNew, queue)
+}
+
+// Delete is called in response to a delete event - e.g. Pod Deleted.
+func (h *EnqueueRequestsForEndpointSliceEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueImpactedIngresses(e.Object, queue)
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request - e.g. reconcile Autoscaling, or a Webhook.
+func (h *EnqueueRequestsForEndpointSliceEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *EnqueueRequestsForEndpointSliceEvent) enqueueImpactedIngresses(obj runtime.Object, queue workqueue.RateLimitingInterface) {
+	endpointSlice, err := convertEndpointSlice(obj)
+	if err != nil {
+		glog.Errorf("failed to convert endpointSlice due to %v", err)
+		return
+	}
+	serviceName, ok := endpointSlice.Labels[k8s.EndpointSliceServiceNameLabel]
+	if !ok {
+		return
+	}
+	enqueueIngressesReferencingService(h.Cache, h.TargetsQueue, h.IngressClass, endpointSlice.Namespace, serviceName, queue)
+}
+
+func convertEndpointSlice(obj runtime.Object) (*k8s.EndpointSlice, error) {
+	return k8s.ConvertEndpointSlice(obj.(*unstructured.Unstructured))
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	extensions "k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestEnqueueRequestsForEndpointSliceEvent_Update(t *testing.T) {
+	const namespace = "namespace"
+	newEndpointSlice := func(ready bool) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "discovery.k8s.io/v1",
+				"kind":       "EndpointSlice",
+				"metadata": map[string]interface{}{
+					"namespace": namespace,
+					"name":      "service-abcde",
+					"labels": map[string]interface{}{
+						k8s.EndpointSliceServiceNameLabel: "service",
+					},
+				},
+				"addressType": "IPv4",
+				"endpoints": []interface{}{
+					map[string]interface{}{
+						"addresses":  []interface{}{"10.0.0.1"},
+						"conditions": map[string]interface{}{"ready": ready},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		endpointsOld    *unstructured.Unstructured
+		endpointsNew    *unstructured.Unstructured
+		expectedEnqueue bool
+	}{
+		{
+			name:            "endpoint conditions changed",
+			endpointsOld:    newEndpointSlice(false),
+			endpointsNew:    newEndpointSlice(true),
+			expectedEnqueue: true,
+		},
+		{
+			name:            "endpoints unchanged",
+			endpointsOld:    newEndpointSlice(true),
+			endpointsNew:    newEndpointSlice(true),
+			expectedEnqueue: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			queueMock := &mocks.RateLimitingInterface{}
+			if tc.expectedEnqueue {
+				mockCache.EXPECT().List(gomock.Any(), client.InNamespace(namespace), &extensions.IngressList{}).SetArg(2, extensions.IngressList{
+					Items: []extensions.Ingress{
+						{
+							ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: "ingress"},
+							Spec: extensions.IngressSpec{
+								Backend: &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)},
+							},
+						},
+					},
+				})
+				queueMock.On("Add", reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: namespace, Name: "ingress"},
+				})
+			}
+
+			handler := EnqueueRequestsForEndpointSliceEvent{
+				Cache: mockCache,
+			}
+			handler.Update(event.UpdateEvent{ObjectOld: tc.endpointsOld, ObjectNew: tc.endpointsNew}, queueMock)
+			queueMock.AssertExpectations(t)
+			if !tc.expectedEnqueue {
+				queueMock.AssertNumberOfCalls(t, "Add", 0)
+			}
+		})
+	}
+}
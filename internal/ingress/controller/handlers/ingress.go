@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"reflect"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -23,8 +27,16 @@ func (h *EnqueueRequestsForIngressEvent) Create(e event.CreateEvent, queue workq
 
 // Update is called in response to an update event -  e.g. Pod Updated.
 func (h *EnqueueRequestsForIngressEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueIfIngressClassMatched(e.ObjectOld.(*extensions.Ingress), queue)
-	h.enqueueIfIngressClassMatched(e.ObjectNew.(*extensions.Ingress), queue)
+	ingOld := e.ObjectOld.(*extensions.Ingress)
+	ingNew := e.ObjectNew.(*extensions.Ingress)
+
+	// status updates, like the ones of the controller itself, don't need a reconcile;
+	// periodic resyncs don't change the resourceVersion and are still reconciled.
+	if ingOld.ResourceVersion != ingNew.ResourceVersion && !isIngressSpecOrAnnotationsChanged(ingOld, ingNew) {
+		return
+	}
+	h.enqueueIfIngressClassMatched(ingOld, queue)
+	h.enqueueIfIngressClassMatched(ingNew, queue)
 }
 
 // Delete is called in response to a delete event - e.g. Pod Deleted.
@@ -49,3 +61,24 @@ func (h *EnqueueRequestsForIngressEvent) enqueueIfIngressClassMatched(ingress *e
 		},
 	})
 }
+
+func isIngressSpecOrAnnotationsChanged(ingOld *extensions.Ingress, ingNew *extensions.Ingress) bool {
+	return !reflect.DeepEqual(ingOld.Spec, ingNew.Spec) ||
+		!reflect.DeepEqual(reconciledAnnotations(ingOld), reconciledAnnotations(ingNew)) ||
+		!reflect.DeepEqual(ingOld.DeletionTimestamp, ingNew.DeletionTimestamp)
+}
+
+// reconciledAnnotations returns the annotations of ingress, without the canary-status annotation reported by the controller itself.
+func reconciledAnnotations(ingress *extensions.Ingress) map[string]string {
+	statusKey := parser.GetAnnotationWithPrefix(canary.AnnotationStatus)
+	if _, ok := ingress.Annotations[statusKey]; !ok {
+		return ingress.Annotations
+	}
+	annotations := make(map[string]string, len(ingress.Annotations))
+	for k, v := range ingress.Annotations {
+		if k != statusKey {
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
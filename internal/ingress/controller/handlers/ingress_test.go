@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestEnqueueRequestsForIngressEvent_Update(t *testing.T) {
+	newIngress := func(resourceVersion string) *extensions.Ingress {
+		return &extensions.Ingress{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace:       "namespace",
+				Name:            "ingress",
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{"kubernetes.io/ingress.class": "alb"},
+			},
+		}
+	}
+	withStatus := func(ingress *extensions.Ingress) *extensions.Ingress {
+		ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+		return ingress
+	}
+	withAnnotation := func(ingress *extensions.Ingress) *extensions.Ingress {
+		ingress.Annotations["alb.ingress.kubernetes.io/scheme"] = "internet-facing"
+		return ingress
+	}
+	withCanaryStatus := func(ingress *extensions.Ingress) *extensions.Ingress {
+		ingress.Annotations["alb.ingress.kubernetes.io/canary-status"] = `{"service-v1":{"ServiceName":"service-v2","Weight":10}}`
+		return ingress
+	}
+
+	for _, tc := range []struct {
+		name            string
+		ingOld          *extensions.Ingress
+		ingNew          *extensions.Ingress
+		expectedEnqueue bool
+	}{
+		{
+			name:            "status update is ignored",
+			ingOld:          newIngress("1"),
+			ingNew:          withStatus(newIngress("2")),
+			expectedEnqueue: false,
+		},
+		{
+			name:            "annotations update is enqueued",
+			ingOld:          newIngress("1"),
+			ingNew:          withAnnotation(newIngress("2")),
+			expectedEnqueue: true,
+		},
+		{
+			name:            "canary-status update is ignored",
+			ingOld:          newIngress("1"),
+			ingNew:          withCanaryStatus(newIngress("2")),
+			expectedEnqueue: false,
+		},
+		{
+			name:            "annotations update along with canary-status is enqueued",
+			ingOld:          withCanaryStatus(newIngress("1")),
+			ingNew:          withAnnotation(newIngress("2")),
+			expectedEnqueue: true,
+		},
+		{
+			name:            "resync is enqueued",
+			ingOld:          withStatus(newIngress("1")),
+			ingNew:          withStatus(newIngress("1")),
+			expectedEnqueue: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := EnqueueRequestsForIngressEvent{IngressClass: "alb"}
+			queueMock := &mocks.RateLimitingInterface{}
+			queueMock.On("Add", reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "namespace", Name: "ingress"}})
+
+			handler.Update(event.UpdateEvent{ObjectOld: tc.ingOld, ObjectNew: tc.ingNew}, queueMock)
+			if tc.expectedEnqueue {
+				queueMock.AssertCalled(t, "Add", reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "namespace", Name: "ingress"}})
+			} else {
+				queueMock.AssertNumberOfCalls(t, "Add", 0)
+			}
+		})
+	}
+}
@@ -2,14 +2,20 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -22,13 +28,17 @@ var _ handler.EventHandler = (*EnqueueRequestsForNodeEvent)(nil)
 type EnqueueRequestsForNodeEvent struct {
 	IngressClass string
 
-	Cache cache.Cache
+	// NodeExclusionTaints are the taint keys of nodes to deregister from instance targetGroups
+	NodeExclusionTaints []string
+
+	Cache    cache.Cache
+	Recorder record.EventRecorder
 }
 
 // Create is called in response to an create event - e.g. Pod Creation.
 func (h *EnqueueRequestsForNodeEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
 	node := e.Object.(*corev1.Node)
-	if backend.IsNodeSuitableAsTrafficProxy(node) {
+	if h.isNodeSuitableAsTarget(node) {
 		h.enqueueImpactedIngresses(queue)
 	}
 }
@@ -36,7 +46,7 @@ func (h *EnqueueRequestsForNodeEvent) Create(e event.CreateEvent, queue workqueu
 // Delete is called in response to a delete event - e.g. Pod Deleted.
 func (h *EnqueueRequestsForNodeEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
 	node := e.Object.(*corev1.Node)
-	if backend.IsNodeSuitableAsTrafficProxy(node) {
+	if h.isNodeSuitableAsTarget(node) {
 		h.enqueueImpactedIngresses(queue)
 	}
 }
@@ -45,8 +55,13 @@ func (h *EnqueueRequestsForNodeEvent) Delete(e event.DeleteEvent, queue workqueu
 func (h *EnqueueRequestsForNodeEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
 	nodeOld := e.ObjectOld.(*corev1.Node)
 	nodeNew := e.ObjectNew.(*corev1.Node)
-	if backend.IsNodeSuitableAsTrafficProxy(nodeOld) != backend.IsNodeSuitableAsTrafficProxy(nodeNew) {
+	h.recordDrainTransition(nodeOld, nodeNew)
+	if h.isNodeSuitableAsTarget(nodeOld) != h.isNodeSuitableAsTarget(nodeNew) {
 		h.enqueueImpactedIngresses(queue)
+		return
+	}
+	if !reflect.DeepEqual(nodeOld.Labels, nodeNew.Labels) {
+		h.enqueueIngressesSelectingNode(nodeOld, nodeNew, queue)
 	}
 }
 
@@ -55,6 +70,38 @@ func (h *EnqueueRequestsForNodeEvent) Update(e event.UpdateEvent, queue workqueu
 func (h *EnqueueRequestsForNodeEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
 }
 
+// isNodeSuitableAsTarget checks whether node is registered to instance targetGroups.
+func (h *EnqueueRequestsForNodeEvent) isNodeSuitableAsTarget(node *corev1.Node) bool {
+	if !backend.IsNodeSuitableAsTrafficProxy(node) {
+		return false
+	}
+	_, tainted := backend.FindNodeExclusionTaint(node, h.NodeExclusionTaints)
+	return !tainted
+}
+
+// recordDrainTransition records an event on node when it's cordoned or tainted for termination, and when it's back.
+func (h *EnqueueRequestsForNodeEvent) recordDrainTransition(nodeOld *corev1.Node, nodeNew *corev1.Node) {
+	drainReasonOld := h.drainReason(nodeOld)
+	drainReasonNew := h.drainReason(nodeNew)
+	switch {
+	case drainReasonOld == "" && drainReasonNew != "":
+		h.Recorder.Eventf(nodeNew, corev1.EventTypeNormal, "DEREGISTER", "Deregistering node from target groups of target-type instance, it's %v", drainReasonNew)
+	case drainReasonOld != "" && drainReasonNew == "":
+		h.Recorder.Eventf(nodeNew, corev1.EventTypeNormal, "REGISTER", "Registering node to target groups of target-type instance, it's no longer %v", drainReasonOld)
+	}
+}
+
+// drainReason returns why node is drained from instance targetGroups, or empty if it isn't.
+func (h *EnqueueRequestsForNodeEvent) drainReason(node *corev1.Node) string {
+	if node.Spec.Unschedulable {
+		return "cordoned"
+	}
+	if taint, tainted := backend.FindNodeExclusionTaint(node, h.NodeExclusionTaints); tainted {
+		return fmt.Sprintf("tainted with %v", taint.Key)
+	}
+	return ""
+}
+
 // Ideally this should only enqueue ingresses that have changed
 func (h *EnqueueRequestsForNodeEvent) enqueueImpactedIngresses(queue workqueue.RateLimitingInterface) {
 	ingressList := &extensions.IngressList{}
@@ -75,3 +122,59 @@ func (h *EnqueueRequestsForNodeEvent) enqueueImpactedIngresses(queue workqueue.R
 		})
 	}
 }
+
+// enqueueIngressesSelectingNode enqueues the ingresses whose target-node-labels selects either nodeOld or nodeNew, but not both.
+func (h *EnqueueRequestsForNodeEvent) enqueueIngressesSelectingNode(nodeOld *corev1.Node, nodeNew *corev1.Node, queue workqueue.RateLimitingInterface) {
+	ingressList := &extensions.IngressList{}
+	if err := h.Cache.List(context.Background(), nil, ingressList); err != nil {
+		glog.Errorf("failed to fetch impacted ingresses by node due to %v", err)
+		return
+	}
+
+	for _, ingress := range ingressList.Items {
+		if !class.IsValidIngress(h.IngressClass, &ingress) {
+			continue
+		}
+		if h.isNodeSelectionChanged(&ingress, nodeOld, nodeNew) {
+			queue.Add(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ingress.Namespace,
+					Name:      ingress.Name,
+				},
+			})
+		}
+	}
+}
+
+// isNodeSelectionChanged checks whether the target-node-labels of any backend of ingress selects nodeOld and nodeNew differently.
+// The annotation on the backend service takes precedence over the one on ingress.
+func (h *EnqueueRequestsForNodeEvent) isNodeSelectionChanged(ingress *extensions.Ingress, nodeOld *corev1.Node, nodeNew *corev1.Node) bool {
+	backends, _, err := tg.ExtractTargetGroupBackends(ingress)
+	if err != nil {
+		glog.Errorf("Failed to extract backend services from ingress: %v, reconcile the ingress. error: %v", ingress.Name, err)
+		return true
+	}
+	annotation := parser.GetAnnotationWithPrefix("target-node-labels")
+	for _, ingressBackend := range backends {
+		annos := ingress.Annotations
+		service := &corev1.Service{}
+		serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingressBackend.ServiceName}
+		if err := h.Cache.Get(context.Background(), serviceKey, service); err == nil {
+			if _, ok := service.Annotations[annotation]; ok {
+				annos = service.Annotations
+			}
+		}
+		rawSelector, ok := annos[annotation]
+		if !ok {
+			continue
+		}
+		selector, err := labels.Parse(rawSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(nodeOld.Labels)) != selector.Matches(labels.Set(nodeNew.Labels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -1 +1,196 @@
 package handlers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	mock_cache "github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks/controller-runtime/cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestEnqueueRequestsForNodeEvent_UpdateLabels(t *testing.T) {
+	const namespace = "namespace"
+	newIngress := func(name string, serviceName string, annotations map[string]string) extensions.Ingress {
+		return extensions.Ingress{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: annotations,
+			},
+			Spec: extensions.IngressSpec{
+				Backend: &extensions.IngressBackend{
+					ServiceName: serviceName,
+					ServicePort: intstr.FromInt(80),
+				},
+			},
+		}
+	}
+	ingressList := extensions.IngressList{
+		Items: []extensions.Ingress{
+			newIngress("selecting-ingress", "service", map[string]string{
+				"alb.ingress.kubernetes.io/target-node-labels": "node-pool=ingress",
+			}),
+			newIngress("unrelated-selector-ingress", "service", map[string]string{
+				"alb.ingress.kubernetes.io/target-node-labels": "zone=a",
+			}),
+			newIngress("service-selecting-ingress", "selecting-service", map[string]string{
+				"alb.ingress.kubernetes.io/target-node-labels": "zone=a",
+			}),
+			newIngress("no-selector-ingress", "service", nil),
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock_cache.NewMockCache(ctrl)
+	mockCache.EXPECT().List(gomock.Any(), nil, &extensions.IngressList{}).SetArg(2, ingressList)
+	mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "service"}, gomock.Any()).
+		SetArg(2, corev1.Service{}).Times(3)
+	mockCache.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "selecting-service"}, gomock.Any()).
+		SetArg(2, corev1.Service{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					"alb.ingress.kubernetes.io/target-node-labels": "node-pool in (ingress)",
+				},
+			},
+		})
+
+	handler := EnqueueRequestsForNodeEvent{
+		Cache: mockCache,
+	}
+
+	queueMock := &mocks.RateLimitingInterface{}
+	queueMock.On("Add", reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      "selecting-ingress",
+		},
+	})
+	queueMock.On("Add", reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      "service-selecting-ingress",
+		},
+	})
+
+	readyCondition := corev1.NodeCondition{
+		Type:   corev1.NodeReady,
+		Status: corev1.ConditionTrue,
+	}
+	handler.Update(event.UpdateEvent{
+		ObjectOld: &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{
+				Labels: map[string]string{"node-pool": "default", "zone": "a"},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+		},
+		ObjectNew: &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{
+				Labels: map[string]string{"node-pool": "ingress", "zone": "a"},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+		},
+	}, queueMock)
+
+	queueMock.AssertExpectations(t)
+	queueMock.AssertNumberOfCalls(t, "Add", 2)
+}
+
+func TestEnqueueRequestsForNodeEvent_UpdateDrain(t *testing.T) {
+	readyCondition := corev1.NodeCondition{
+		Type:   corev1.NodeReady,
+		Status: corev1.ConditionTrue,
+	}
+	for _, tc := range []struct {
+		name           string
+		nodeOld        *corev1.Node
+		nodeNew        *corev1.Node
+		expectedEvents []string
+	}{
+		{
+			name: "node cordoned",
+			nodeOld: &corev1.Node{
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			nodeNew: &corev1.Node{
+				Spec:   corev1.NodeSpec{Unschedulable: true},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			expectedEvents: []string{"Normal DEREGISTER Deregistering node from target groups of target-type instance, it's cordoned"},
+		},
+		{
+			name: "node tainted for spot interruption",
+			nodeOld: &corev1.Node{
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			nodeNew: &corev1.Node{
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "spot-itn", Effect: corev1.TaintEffectNoSchedule}},
+				},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			expectedEvents: []string{"Normal DEREGISTER Deregistering node from target groups of target-type instance, it's tainted with spot-itn"},
+		},
+		{
+			name: "node uncordoned",
+			nodeOld: &corev1.Node{
+				Spec:   corev1.NodeSpec{Unschedulable: true},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			nodeNew: &corev1.Node{
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			},
+			expectedEvents: []string{"Normal REGISTER Registering node to target groups of target-type instance, it's no longer cordoned"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockCache.EXPECT().List(gomock.Any(), nil, &extensions.IngressList{}).SetArg(2, extensions.IngressList{
+				Items: []extensions.Ingress{
+					{
+						ObjectMeta: v1.ObjectMeta{
+							Name:      "ingress",
+							Namespace: "namespace",
+						},
+					},
+				},
+			})
+			recorder := record.NewFakeRecorder(10)
+			handler := EnqueueRequestsForNodeEvent{
+				NodeExclusionTaints: []string{"spot-itn"},
+				Cache:               mockCache,
+				Recorder:            recorder,
+			}
+
+			queueMock := &mocks.RateLimitingInterface{}
+			queueMock.On("Add", reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "namespace",
+					Name:      "ingress",
+				},
+			})
+
+			handler.Update(event.UpdateEvent{ObjectOld: tc.nodeOld, ObjectNew: tc.nodeNew}, queueMock)
+			close(recorder.Events)
+			var events []string
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			assert.Equal(t, tc.expectedEvents, events)
+			queueMock.AssertExpectations(t)
+		})
+	}
+}
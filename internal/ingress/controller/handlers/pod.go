@@ -7,9 +7,11 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,7 +24,14 @@ var _ handler.EventHandler = (*EnqueueRequestsForPodsEvent)(nil)
 
 type EnqueueRequestsForPodsEvent struct {
 	IngressClass string
-	Cache        cache.Cache
+
+	// EnableEndpointSlices looks pods up in EndpointSlices instead of Endpoints
+	EnableEndpointSlices bool
+
+	// TargetsQueue reconciles only the targets of the target groups backed by the pod, once they're tracked
+	TargetsQueue tg.TargetsQueue
+
+	Cache cache.Cache
 }
 
 // Create is called in response to an create event - e.g. Pod Creation.
@@ -80,29 +89,66 @@ func (h *EnqueueRequestsForPodsEvent) enqueueImpactedIngresses(pod *corev1.Pod,
 			break
 		}
 
+		// the target groups of all backends of a service are enqueued at once
+		checkedServiceNames := sets.NewString()
 		for _, backend := range backends {
-			endpoint := &corev1.Endpoints{}
-			nspname := types.NamespacedName{
-				Namespace: ingress.Namespace,
-				Name:      backend.ServiceName,
+			if checkedServiceNames.Has(backend.ServiceName) {
+				continue
 			}
-			if err = h.Cache.Get(context.Background(), nspname, endpoint); err != nil {
+			checkedServiceNames.Insert(backend.ServiceName)
+			found, err := h.isPodInServiceEndpoints(pod, ingress.Namespace, backend.ServiceName)
+			if err != nil {
 				glog.Errorf("failed to fetch enpoint %s backing ingress %s/%s, ignoring",
 					backend.ServiceName, ingress.Namespace, ingress.Name)
 				continue
 			}
 
-			if h.isPodInEndpoint(pod, endpoint) {
-				queue.Add(reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Namespace: ingress.Namespace,
-						Name:      ingress.Name,
-					},
-				})
-				break
+			if found {
+				enqueueIngressTargetGroups(h.TargetsQueue, &ingress, backend.ServiceName, queue)
+			}
+		}
+	}
+}
+
+// isPodInServiceEndpoints checks whether pod is an endpoint of the service serviceName in namespace.
+func (h *EnqueueRequestsForPodsEvent) isPodInServiceEndpoints(pod *corev1.Pod, namespace string, serviceName string) (bool, error) {
+	if h.EnableEndpointSlices {
+		endpointSliceList := k8s.NewUnstructuredEndpointSliceList()
+		opts := client.InNamespace(namespace).MatchingLabels(map[string]string{k8s.EndpointSliceServiceNameLabel: serviceName})
+		if err := h.Cache.List(context.Background(), opts, endpointSliceList); err != nil {
+			return false, err
+		}
+		for i := range endpointSliceList.Items {
+			endpointSlice, err := k8s.ConvertEndpointSlice(&endpointSliceList.Items[i])
+			if err != nil {
+				return false, err
 			}
+			if h.isPodInEndpointSlice(pod, endpointSlice) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	endpoint := &corev1.Endpoints{}
+	nspname := types.NamespacedName{
+		Namespace: namespace,
+		Name:      serviceName,
+	}
+	if err := h.Cache.Get(context.Background(), nspname, endpoint); err != nil {
+		return false, err
+	}
+	return h.isPodInEndpoint(pod, endpoint), nil
+}
+
+func (h *EnqueueRequestsForPodsEvent) isPodInEndpointSlice(pod *corev1.Pod, endpointSlice *k8s.EndpointSlice) bool {
+	for _, endpoint := range endpointSlice.Endpoints {
+		if len(endpoint.Addresses) == 0 || endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || endpoint.TargetRef.Name != pod.Name {
+			continue
 		}
+		return true
 	}
+	return false
 }
 
 func (h *EnqueueRequestsForPodsEvent) isPodInEndpoint(pod *corev1.Pod, endpoint *corev1.Endpoints) bool {
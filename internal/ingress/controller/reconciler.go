@@ -2,16 +2,23 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/lb"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	ingerrors "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	extensionsclient "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,9 +27,11 @@ import (
 
 // Reconciler reconciles an single ingress object
 type Reconciler struct {
-	client   client.Client
-	cache    cache.Cache
-	recorder record.EventRecorder
+	client client.Client
+	cache  cache.Cache
+	// ingressClient patches ingresses, which the client can't do
+	ingressClient extensionsclient.IngressesGetter
+	recorder      record.EventRecorder
 
 	// TODO: move things out of store, and start to rely on functionality provided by client & cache
 	store store.Storer
@@ -52,6 +61,10 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	}
 
 	if err := r.reconcileIngress(ctx, request.NamespacedName, ingress); err != nil {
+		if requeueAfter, ok := ingerrors.IsRequeueAfter(err); ok {
+			r.metricCollector.IncReconcileCount()
+			return reconcile.Result{RequeueAfter: requeueAfter.Duration}, nil
+		}
 		r.metricCollector.IncReconcileErrorCount(request.NamespacedName.String())
 		return reconcile.Result{}, err
 	}
@@ -63,14 +76,18 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 func (r *Reconciler) reconcileIngress(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) error {
 	ctx = r.buildReconcileContext(ctx, ingressKey, ingress)
 	lbInfo, err := r.lbController.Reconcile(ctx, ingress)
-	if err != nil {
+	if lbInfo == nil {
 		return err
 	}
 	if err := r.updateIngressStatus(ctx, ingress, lbInfo); err != nil {
 		return err
 	}
+	if err := r.updateIngressCanaryStatus(ctx, ingress); err != nil {
+		return err
+	}
 
-	return nil
+	// the load balancer may have to be reconciled again later
+	return err
 }
 
 func (r *Reconciler) deleteIngress(ctx context.Context, ingressKey types.NamespacedName) error {
@@ -95,6 +112,49 @@ func (r *Reconciler) updateIngressStatus(ctx context.Context, ingress *extension
 	return nil
 }
 
+// updateIngressCanaryStatus reports the canaries applied to the load balancer with an annotation,
+// so that tools shifting the canary weight can confirm each step.
+// The Ingress status has no room for it, and updates of the annotation don't trigger another reconcile.
+// The annotation is patched alone, so that it neither conflicts with nor overwrites concurrent edits of the ingress.
+func (r *Reconciler) updateIngressCanaryStatus(ctx context.Context, ingress *extensions.Ingress) error {
+	ingressAnnos, err := r.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	if err != nil {
+		return err
+	}
+	statusKey := parser.GetAnnotationWithPrefix(canary.AnnotationStatus)
+	currentStatus, exists := ingress.Annotations[statusKey]
+	if ingressAnnos.Canary == nil || len(ingressAnnos.Canary.Canaries) == 0 {
+		if !exists {
+			return nil
+		}
+		return r.patchIngressAnnotation(ingress, statusKey, nil)
+	}
+
+	desiredStatus, err := json.Marshal(ingressAnnos.Canary.BuildStatus())
+	if err != nil {
+		return err
+	}
+	if exists && currentStatus == string(desiredStatus) {
+		return nil
+	}
+	albctx.GetLogger(ctx).Infof("applied canaries %v", string(desiredStatus))
+	return r.patchIngressAnnotation(ingress, statusKey, aws.String(string(desiredStatus)))
+}
+
+// patchIngressAnnotation sets the annotation key of ingress to value, or removes it if value is nil.
+func (r *Reconciler) patchIngressAnnotation(ingress *extensions.Ingress, key string, value *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.ingressClient.Ingresses(ingress.Namespace).Patch(ingress.Name, types.StrategicMergePatchType, patch)
+	return err
+}
+
 func (r *Reconciler) buildReconcileContext(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) context.Context {
 	ctx = albctx.SetLogger(ctx, log.New(ingressKey.String()))
 	if ingress != nil {
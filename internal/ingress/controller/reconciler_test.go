@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/canary"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestReconciler_updateIngressCanaryStatus(t *testing.T) {
+	const statusKey = "alb.ingress.kubernetes.io/canary-status"
+	for _, tc := range []struct {
+		name             string
+		canaries         map[string]canary.Canary
+		status           string
+		expectedPatch    string
+		expectedStatus   string
+		expectedNoAction bool
+	}{
+		{
+			name: "canary applied",
+			canaries: map[string]canary.Canary{
+				"service": {ServiceName: aws.String("service-v2"), ServicePort: aws.String("80"), Weight: aws.Int64(20)},
+			},
+			expectedPatch:  `{"metadata":{"annotations":{"alb.ingress.kubernetes.io/canary-status":"{\"service\":{\"ServiceName\":\"service-v2\",\"Weight\":20}}"}}}`,
+			expectedStatus: `{"service":{"ServiceName":"service-v2","Weight":20}}`,
+		},
+		{
+			name: "canary status up to date",
+			canaries: map[string]canary.Canary{
+				"service": {ServiceName: aws.String("service-v2"), ServicePort: aws.String("80"), Weight: aws.Int64(20)},
+			},
+			status:           `{"service":{"ServiceName":"service-v2","Weight":20}}`,
+			expectedNoAction: true,
+		},
+		{
+			name:          "canary removed",
+			status:        `{"service":{"ServiceName":"service-v2","Weight":20}}`,
+			expectedPatch: `{"metadata":{"annotations":{"alb.ingress.kubernetes.io/canary-status":null}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// the reconciled ingress is outdated by a concurrent edit of the user
+			ingress := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "namespace",
+					Name:            "ingress",
+					ResourceVersion: "1",
+					Annotations:     map[string]string{},
+				},
+			}
+			if tc.status != "" {
+				ingress.Annotations[statusKey] = tc.status
+			}
+			editedIngress := ingress.DeepCopy()
+			editedIngress.ResourceVersion = "2"
+			editedIngress.Annotations["edited-by"] = "user"
+			kubeClient := fake.NewSimpleClientset(editedIngress)
+
+			ingressAnnos := annotations.NewIngressDummy()
+			ingressAnnos.Canary = &canary.Config{Canaries: tc.canaries}
+			storer := store.NewDummy()
+			storer.GetIngressAnnotationsResponse = ingressAnnos
+			r := &Reconciler{
+				ingressClient: kubeClient.ExtensionsV1beta1(),
+				store:         storer,
+			}
+
+			assert.NoError(t, r.updateIngressCanaryStatus(context.Background(), ingress))
+			if tc.expectedNoAction {
+				assert.Empty(t, kubeClient.Actions())
+				return
+			}
+			actions := kubeClient.Actions()
+			if assert.Len(t, actions, 1) {
+				assert.Equal(t, tc.expectedPatch, string(actions[0].(k8stesting.PatchAction).GetPatch()))
+			}
+			if tc.expectedStatus == "" {
+				return
+			}
+			patched, err := kubeClient.ExtensionsV1beta1().Ingresses("namespace").Get("ingress", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, patched.Annotations[statusKey])
+			assert.Equal(t, "user", patched.Annotations["edited-by"], "concurrent edit should be kept")
+		})
+	}
+}
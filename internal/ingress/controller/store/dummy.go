@@ -4,6 +4,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 )
@@ -15,10 +16,12 @@ type Dummy struct {
 
 	GetServiceFunc        func(string) (*corev1.Service, error)
 	ListNodesFunc         func() []*corev1.Node
+	GetNodeFunc           func(string) (*corev1.Node, error)
 	GetNodeInstanceIDFunc func(*corev1.Node) (string, error)
 
-	GetServiceEndpointsFunc func(string) (*corev1.Endpoints, error)
-	GetPodFunc              func(string) (*corev1.Pod, error)
+	GetServiceEndpointsFunc       func(string) (*corev1.Endpoints, error)
+	ListServiceEndpointSlicesFunc func(string) ([]*k8s.EndpointSlice, error)
+	GetPodFunc                    func(string) (*corev1.Pod, error)
 }
 
 // GetConfigMap ...
@@ -36,6 +39,11 @@ func (d Dummy) GetServiceEndpoints(key string) (*corev1.Endpoints, error) {
 	return d.GetServiceEndpointsFunc(key)
 }
 
+// ListServiceEndpointSlices ...
+func (d Dummy) ListServiceEndpointSlices(key string) ([]*k8s.EndpointSlice, error) {
+	return d.ListServiceEndpointSlicesFunc(key)
+}
+
 // GetServicePods ...
 func (d Dummy) GetPod(key string) (*corev1.Pod, error) {
 	return d.GetPodFunc(key)
@@ -56,6 +64,11 @@ func (d Dummy) ListNodes() []*corev1.Node {
 	return d.ListNodesFunc()
 }
 
+// GetNode ...
+func (d Dummy) GetNode(key string) (*corev1.Node, error) {
+	return d.GetNodeFunc(key)
+}
+
 // ListIngresses ...
 func (d Dummy) ListIngresses() []*extensions.Ingress {
 	return nil
@@ -97,8 +110,10 @@ func NewDummy() *Dummy {
 	return &Dummy{
 		GetServiceFunc:                func(_ string) (*corev1.Service, error) { return dummy.NewService(), nil },
 		ListNodesFunc:                 func() []*corev1.Node { return nil },
+		GetNodeFunc:                   func(string) (*corev1.Node, error) { return &corev1.Node{}, nil },
 		GetNodeInstanceIDFunc:         func(*corev1.Node) (string, error) { return "", nil },
 		GetServiceEndpointsFunc:       func(string) (*corev1.Endpoints, error) { return nil, nil },
+		ListServiceEndpointSlicesFunc: func(string) ([]*k8s.EndpointSlice, error) { return nil, nil },
 		GetPodFunc:                    func(string) (*corev1.Pod, error) { return &corev1.Pod{}, nil },
 		GetIngressAnnotationsResponse: annotations.NewIngressDummy(),
 		GetServiceAnnotationsResponse: annotations.NewServiceDummy(),
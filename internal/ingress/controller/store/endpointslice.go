@@ -0,0 +1,46 @@
+package store
+
+import (
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// endpointSliceServiceIndex indexes EndpointSlices by the key of the Service they belong to.
+const endpointSliceServiceIndex = "service"
+
+// EndpointSliceLister makes an Indexer that lists EndpointSlices.
+type EndpointSliceLister struct {
+	cache.Indexer
+}
+
+// ByService returns the EndpointSlices of the Service matching key in the local EndpointSlice Indexer.
+func (s *EndpointSliceLister) ByService(key string) ([]*k8s.EndpointSlice, error) {
+	objs, err := s.ByIndex(endpointSliceServiceIndex, key)
+	if err != nil {
+		return nil, err
+	}
+	var endpointSlices []*k8s.EndpointSlice
+	for _, obj := range objs {
+		endpointSlice, err := k8s.ConvertEndpointSlice(obj.(*unstructured.Unstructured))
+		if err != nil {
+			return nil, err
+		}
+		endpointSlices = append(endpointSlices, endpointSlice)
+	}
+	return endpointSlices, nil
+}
+
+// indexEndpointSliceByService returns the key of the Service an EndpointSlice belongs to.
+func indexEndpointSliceByService(obj interface{}) ([]string, error) {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	serviceName, ok := metaObj.GetLabels()[k8s.EndpointSliceServiceNameLabel]
+	if !ok {
+		return nil, nil
+	}
+	return []string{metaObj.GetNamespace() + "/" + serviceName}, nil
+}
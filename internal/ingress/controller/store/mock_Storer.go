@@ -6,6 +6,8 @@ import (
 	annotations "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	config "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 
+	k8s "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+
 	mock "github.com/stretchr/testify/mock"
 
 	v1 "k8s.io/api/core/v1"
@@ -32,6 +34,29 @@ func (_m *MockStorer) GetConfig() *config.Configuration {
 	return r0
 }
 
+// GetConfigMap provides a mock function with given fields: key
+func (_m *MockStorer) GetConfigMap(key string) (*v1.ConfigMap, error) {
+	ret := _m.Called(key)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(string) *v1.ConfigMap); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetIngressAnnotations provides a mock function with given fields: key
 func (_m *MockStorer) GetIngressAnnotations(key string) (*annotations.Ingress, error) {
 	ret := _m.Called(key)
@@ -97,6 +122,29 @@ func (_m *MockStorer) GetNodeInstanceID(node *v1.Node) (string, error) {
 	return r0, r1
 }
 
+// GetNode provides a mock function with given fields: key
+func (_m *MockStorer) GetNode(key string) (*v1.Node, error) {
+	ret := _m.Called(key)
+
+	var r0 *v1.Node
+	if rf, ok := ret.Get(0).(func(string) *v1.Node); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Node)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPod provides a mock function with given fields: key
 func (_m *MockStorer) GetPod(key string) (*v1.Pod, error) {
 	ret := _m.Called(key)
@@ -189,6 +237,29 @@ func (_m *MockStorer) GetServiceEndpoints(key string) (*v1.Endpoints, error) {
 	return r0, r1
 }
 
+// ListServiceEndpointSlices provides a mock function with given fields: key
+func (_m *MockStorer) ListServiceEndpointSlices(key string) ([]*k8s.EndpointSlice, error) {
+	ret := _m.Called(key)
+
+	var r0 []*k8s.EndpointSlice
+	if rf, ok := ret.Get(0).(func(string) []*k8s.EndpointSlice); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*k8s.EndpointSlice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListNodes provides a mock function with given fields:
 func (_m *MockStorer) ListNodes() []*v1.Node {
 	ret := _m.Called()
@@ -39,18 +39,27 @@ import (
 // Storer is the interface that wraps the required methods to gather information
 // about ingresses, services, secrets and ingress annotations.
 type Storer interface {
+	// GetConfigMap returns the ConfigMap matching key.
+	GetConfigMap(key string) (*corev1.ConfigMap, error)
+
 	// GetService returns the Service matching key.
 	GetService(key string) (*corev1.Service, error)
 
 	// GetServiceEndpoints returns the Endpoints of a Service matching key.
 	GetServiceEndpoints(key string) (*corev1.Endpoints, error)
 
+	// ListServiceEndpointSlices returns the EndpointSlices of a Service matching key.
+	ListServiceEndpointSlices(key string) ([]*k8s.EndpointSlice, error)
+
 	// GetServiceAnnotations returns the parsed annotations of an Service matching key. if ingress is non-nil, merges ingress annotations into the service.
 	GetServiceAnnotations(key string, ingress *annotations.Ingress) (*annotations.Service, error)
 
 	// ListNodes returns a list of all Nodes in the store.
 	ListNodes() []*corev1.Node
 
+	// GetNode returns the Node matching key.
+	GetNode(key string) (*corev1.Node, error)
+
 	// GetIngressAnnotations returns the parsed annotations of an Ingress matching key.
 	GetIngressAnnotations(key string) (*annotations.Ingress, error)
 
@@ -69,11 +78,13 @@ type Storer interface {
 
 // Informer defines the required SharedIndexInformers that interact with the API server.
 type Informer struct {
-	Ingress  cache.SharedIndexInformer
-	Service  cache.SharedIndexInformer
-	Endpoint cache.SharedIndexInformer
-	Node     cache.SharedIndexInformer
-	Pod      cache.SharedIndexInformer
+	Ingress       cache.SharedIndexInformer
+	Service       cache.SharedIndexInformer
+	Endpoint      cache.SharedIndexInformer
+	EndpointSlice cache.SharedIndexInformer
+	Node          cache.SharedIndexInformer
+	Pod           cache.SharedIndexInformer
+	ConfigMap     cache.SharedIndexInformer
 }
 
 // Lister contains object listers (stores).
@@ -81,8 +92,10 @@ type Lister struct {
 	Ingress           IngressLister
 	Service           ServiceLister
 	Endpoint          EndpointLister
+	EndpointSlice     EndpointSliceLister
 	Node              NodeLister
 	Pod               PodLister
+	ConfigMap         ConfigMapLister
 	IngressAnnotation IngressAnnotationsLister
 	ServiceAnnotation ServiceAnnotationsLister
 }
@@ -148,6 +161,17 @@ func New(mgr manager.Manager, cfg *config.Configuration) (Storer, error) {
 	}
 	store.listers.Endpoint.Store = store.informers.Endpoint.GetStore()
 
+	if cfg.EnableEndpointSlices {
+		store.informers.EndpointSlice, err = mgrCache.GetInformer(k8s.NewUnstructuredEndpointSlice())
+		if err != nil {
+			return nil, err
+		}
+		if err := store.informers.EndpointSlice.AddIndexers(cache.Indexers{endpointSliceServiceIndex: indexEndpointSliceByService}); err != nil {
+			return nil, err
+		}
+		store.listers.EndpointSlice.Indexer = store.informers.EndpointSlice.GetIndexer()
+	}
+
 	store.informers.Node, err = mgrCache.GetInformer(&corev1.Node{})
 	if err != nil {
 		return nil, err
@@ -160,6 +184,12 @@ func New(mgr manager.Manager, cfg *config.Configuration) (Storer, error) {
 	}
 	store.listers.Pod.Store = store.informers.Pod.GetStore()
 
+	store.informers.ConfigMap, err = mgrCache.GetInformer(&corev1.ConfigMap{})
+	if err != nil {
+		return nil, err
+	}
+	store.listers.ConfigMap.Store = store.informers.ConfigMap.GetStore()
+
 	ingEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			ing := obj.(*extensions.Ingress)
@@ -246,6 +276,11 @@ func (s *k8sStore) extractServiceAnnotations(svc *corev1.Service) {
 	}
 }
 
+// GetConfigMap returns the ConfigMap matching key.
+func (s k8sStore) GetConfigMap(key string) (*corev1.ConfigMap, error) {
+	return s.listers.ConfigMap.ByKey(key)
+}
+
 // GetService returns the Service matching key.
 func (s k8sStore) GetService(key string) (*corev1.Service, error) {
 	return s.listers.Service.ByKey(key)
@@ -295,6 +330,14 @@ func (s k8sStore) GetServiceEndpoints(key string) (*corev1.Endpoints, error) {
 	return s.listers.Endpoint.ByKey(key)
 }
 
+// ListServiceEndpointSlices returns the EndpointSlices of a Service matching key.
+func (s k8sStore) ListServiceEndpointSlices(key string) ([]*k8s.EndpointSlice, error) {
+	if s.listers.EndpointSlice.Indexer == nil {
+		return nil, fmt.Errorf("EndpointSlices are not enabled")
+	}
+	return s.listers.EndpointSlice.ByService(key)
+}
+
 func (s *k8sStore) GetNodeInstanceID(node *corev1.Node) (string, error) {
 	nodeVersion, _ := semver.ParseTolerant(node.Status.NodeInfo.KubeletVersion)
 	if nodeVersion.Major == 1 && nodeVersion.Minor <= 10 {
@@ -337,6 +380,10 @@ func (s *k8sStore) GetInstanceIDFromPodIP(ip string) (string, error) {
 	return "", fmt.Errorf("Unable to locate a host for pod ip: %v", ip)
 }
 
+func (s k8sStore) GetNode(key string) (*corev1.Node, error) {
+	return s.listers.Node.ByKey(key)
+}
+
 func (s k8sStore) GetPod(key string) (*corev1.Pod, error) {
 	return s.listers.Pod.ByKey(key)
 }
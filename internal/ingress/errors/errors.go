@@ -18,6 +18,7 @@ package errors
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -55,6 +56,24 @@ func (e InvalidContent) Error() string {
 	return e.Name
 }
 
+// RequeueAfter error, which asks for the reconciliation to be retried after Duration rather than failing
+type RequeueAfter struct {
+	Reason   string
+	Duration time.Duration
+}
+
+func (e RequeueAfter) Error() string {
+	return e.Reason
+}
+
+// NewRequeueAfter returns a new RequeueAfter error
+func NewRequeueAfter(duration time.Duration, format string, args ...interface{}) error {
+	return RequeueAfter{
+		Reason:   fmt.Sprintf(format, args...),
+		Duration: duration,
+	}
+}
+
 // IsMissingAnnotations checks if the err is an error which
 // indicates the ingress does not contain annotations
 func IsMissingAnnotations(e error) bool {
@@ -68,6 +87,28 @@ func IsInvalidContent(e error) bool {
 	return ok
 }
 
+// IsRequeueAfter checks if the err is an error which
+// indicates the reconciliation should be retried later
+func IsRequeueAfter(e error) (RequeueAfter, bool) {
+	requeueAfter, ok := e.(RequeueAfter)
+	return requeueAfter, ok
+}
+
+// EarliestRequeueAfter returns the RequeueAfter error among errs that retries the reconciliation first, or nil without any
+func EarliestRequeueAfter(errs ...error) error {
+	var earliest error
+	for _, err := range errs {
+		requeueAfter, ok := IsRequeueAfter(err)
+		if !ok {
+			continue
+		}
+		if current, ok := IsRequeueAfter(earliest); !ok || requeueAfter.Duration < current.Duration {
+			earliest = err
+		}
+	}
+	return earliest
+}
+
 // New returns a new error
 func New(m string) error {
 	return errors.New(m)
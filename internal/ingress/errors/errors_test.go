@@ -16,7 +16,10 @@ limitations under the License.
 
 package errors
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestIsMissingAnnotations(t *testing.T) {
 	if !IsMissingAnnotations(ErrMissingAnnotations) {
@@ -36,3 +39,30 @@ func TestInvalidContent(t *testing.T) {
 		t.Error("expected false")
 	}
 }
+
+func TestRequeueAfter(t *testing.T) {
+	if _, ok := IsRequeueAfter(ErrMissingAnnotations); ok {
+		t.Error("expected false")
+	}
+	err := NewRequeueAfter(time.Second, "demo %v", 1)
+	requeueAfter, ok := IsRequeueAfter(err)
+	if !ok {
+		t.Error("expected true")
+	}
+	if requeueAfter.Duration != time.Second || err.Error() != "demo 1" {
+		t.Errorf("unexpected %v", requeueAfter)
+	}
+	if _, ok := IsRequeueAfter(nil); ok {
+		t.Error("expected false")
+	}
+}
+
+func TestEarliestRequeueAfter(t *testing.T) {
+	if err := EarliestRequeueAfter(nil, ErrMissingAnnotations); err != nil {
+		t.Errorf("unexpected %v", err)
+	}
+	err := EarliestRequeueAfter(NewRequeueAfter(time.Minute, "later"), nil, NewRequeueAfter(time.Second, "sooner"))
+	if requeueAfter, ok := IsRequeueAfter(err); !ok || requeueAfter.Duration != time.Second {
+		t.Errorf("unexpected %v", err)
+	}
+}
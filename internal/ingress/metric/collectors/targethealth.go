@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TargetHealthBackend identifies a target group of an ingress backend, a backend has multiple target groups
+// while one replaces another.
+type TargetHealthBackend struct {
+	Ingress     string
+	Service     string
+	Port        string
+	TargetGroup string
+}
+
+// TargetHealthState identifies the targets of a target group in the same state, for the same reason
+type TargetHealthState struct {
+	State  string
+	Reason string
+}
+
+// TargetHealthController defines metrics about the target health of target groups
+type TargetHealthController struct {
+	prometheus.Collector
+
+	targetHealthCacheLookups *prometheus.CounterVec
+	targets                  *prometheus.GaugeVec
+
+	mutex sync.Mutex
+	// states are the target health states last set for each backend, to delete the ones that no longer have targets
+	states map[TargetHealthBackend][]TargetHealthState
+}
+
+// NewTargetHealthController creates a new prometheus collector for the
+// target health of target groups
+func NewTargetHealthController() *TargetHealthController {
+	return &TargetHealthController{
+		targetHealthCacheLookups: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "target_health_cache_lookups",
+				Help:      `Cumulative number of target health lookups of target groups, by cache result`,
+			},
+			[]string{"result"},
+		),
+		targets: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "targets",
+				Help:      `Number of targets of ingress backends, by target group, target health state and reason`,
+			},
+			[]string{"ingress", "service", "port", "target_group", "state", "reason"},
+		),
+		states: make(map[TargetHealthBackend][]TargetHealthState),
+	}
+}
+
+// IncTargetHealthCacheHitCount increment the target health cache hit counter
+func (th *TargetHealthController) IncTargetHealthCacheHitCount() {
+	th.targetHealthCacheLookups.With(prometheus.Labels{"result": "hit"}).Inc()
+}
+
+// IncTargetHealthCacheMissCount increment the target health cache miss counter
+func (th *TargetHealthController) IncTargetHealthCacheMissCount() {
+	th.targetHealthCacheLookups.With(prometheus.Labels{"result": "miss"}).Inc()
+}
+
+// SetTargets sets the number of targets of backend by target health state,
+// the states of backend missing from counts are removed.
+func (th *TargetHealthController) SetTargets(backend TargetHealthBackend, counts map[TargetHealthState]int) {
+	th.mutex.Lock()
+	defer th.mutex.Unlock()
+
+	for _, state := range th.states[backend] {
+		if _, ok := counts[state]; !ok {
+			th.targets.Delete(targetsLabels(backend, state))
+		}
+	}
+	states := make([]TargetHealthState, 0, len(counts))
+	for state, count := range counts {
+		th.targets.With(targetsLabels(backend, state)).Set(float64(count))
+		states = append(states, state)
+	}
+	th.states[backend] = states
+}
+
+// RemoveTargets removes the number of targets of backend
+func (th *TargetHealthController) RemoveTargets(backend TargetHealthBackend) {
+	th.mutex.Lock()
+	defer th.mutex.Unlock()
+
+	for _, state := range th.states[backend] {
+		th.targets.Delete(targetsLabels(backend, state))
+	}
+	delete(th.states, backend)
+}
+
+func targetsLabels(backend TargetHealthBackend, state TargetHealthState) prometheus.Labels {
+	return prometheus.Labels{
+		"ingress":      backend.Ingress,
+		"service":      backend.Service,
+		"port":         backend.Port,
+		"target_group": backend.TargetGroup,
+		"state":        state.State,
+		"reason":       state.Reason,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (th *TargetHealthController) Describe(ch chan<- *prometheus.Desc) {
+	th.targetHealthCacheLookups.Describe(ch)
+	th.targets.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (th *TargetHealthController) Collect(ch chan<- prometheus.Metric) {
+	th.targetHealthCacheLookups.Collect(ch)
+	th.targets.Collect(ch)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTargetHealthCounters(t *testing.T) {
+	cases := []struct {
+		name    string
+		test    func(*TargetHealthController)
+		metrics []string
+		want    string
+	}{
+		{
+			name: "cache hits and misses are counted by result",
+			test: func(th *TargetHealthController) {
+				th.IncTargetHealthCacheHitCount()
+				th.IncTargetHealthCacheHitCount()
+				th.IncTargetHealthCacheMissCount()
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_target_health_cache_lookups Cumulative number of target health lookups of target groups, by cache result
+				# TYPE aws_alb_ingress_controller_target_health_cache_lookups counter
+				aws_alb_ingress_controller_target_health_cache_lookups{result="hit"} 2
+				aws_alb_ingress_controller_target_health_cache_lookups{result="miss"} 1
+			`,
+			metrics: []string{"aws_alb_ingress_controller_target_health_cache_lookups"},
+		},
+		{
+			name: "targets are counted by state and reason, removing states without targets anymore",
+			test: func(th *TargetHealthController) {
+				backend := TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "80", TargetGroup: "tg1"}
+				th.SetTargets(backend, map[TargetHealthState]int{
+					{State: "healthy"}: 1,
+					{State: "unhealthy", Reason: "Target.FailedHealthChecks"}: 2,
+				})
+				th.SetTargets(backend, map[TargetHealthState]int{
+					{State: "healthy"}: 2,
+					{State: "unhealthy", Reason: "Target.ResponseCodeMismatch"}: 1,
+				})
+				th.SetTargets(TargetHealthBackend{Ingress: "namespace/ingress", Service: "other-service", Port: "80", TargetGroup: "tg2"}, map[TargetHealthState]int{
+					{State: "healthy"}: 1,
+				})
+				th.RemoveTargets(TargetHealthBackend{Ingress: "namespace/ingress", Service: "other-service", Port: "80", TargetGroup: "tg2"})
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_targets Number of targets of ingress backends, by target group, target health state and reason
+				# TYPE aws_alb_ingress_controller_targets gauge
+				aws_alb_ingress_controller_targets{ingress="namespace/ingress",port="80",reason="",service="service",state="healthy",target_group="tg1"} 2
+				aws_alb_ingress_controller_targets{ingress="namespace/ingress",port="80",reason="Target.ResponseCodeMismatch",service="service",state="unhealthy",target_group="tg1"} 1
+			`,
+			metrics: []string{"aws_alb_ingress_controller_targets"},
+		},
+		{
+			name: "target groups of a backend are counted apart, while one replaces another",
+			test: func(th *TargetHealthController) {
+				th.SetTargets(TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "80", TargetGroup: "old"}, map[TargetHealthState]int{
+					{State: "healthy"}: 2,
+				})
+				th.SetTargets(TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "80", TargetGroup: "new"}, map[TargetHealthState]int{
+					{State: "initial", Reason: "Elb.RegistrationInProgress"}: 2,
+				})
+				th.RemoveTargets(TargetHealthBackend{Ingress: "namespace/ingress", Service: "service", Port: "80", TargetGroup: "old"})
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_targets Number of targets of ingress backends, by target group, target health state and reason
+				# TYPE aws_alb_ingress_controller_targets gauge
+				aws_alb_ingress_controller_targets{ingress="namespace/ingress",port="80",reason="Elb.RegistrationInProgress",service="service",state="initial",target_group="new"} 2
+			`,
+			metrics: []string{"aws_alb_ingress_controller_targets"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			th := NewTargetHealthController()
+			reg := prometheus.NewPedanticRegistry()
+			if err := reg.Register(th); err != nil {
+				t.Errorf("registering collector failed: %s", err)
+			}
+
+			c.test(th)
+
+			if err := GatherAndCompare(th, c.want, c.metrics, reg); err != nil {
+				t.Errorf("unexpected error collecting result:\n%s", err)
+			}
+
+			reg.Unregister(th)
+		})
+	}
+}
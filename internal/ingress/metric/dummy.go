@@ -18,6 +18,8 @@ package metric
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric/collectors"
 )
 
 // DummyCollector dummy implementation for mocks in tests
@@ -41,6 +43,19 @@ func (dc DummyCollector) IncAPIErrorCount(prometheus.Labels) {}
 // IncAPIRetryCount ...
 func (dc DummyCollector) IncAPIRetryCount(prometheus.Labels) {}
 
+// IncTargetHealthCacheHitCount ...
+func (dc DummyCollector) IncTargetHealthCacheHitCount() {}
+
+// IncTargetHealthCacheMissCount ...
+func (dc DummyCollector) IncTargetHealthCacheMissCount() {}
+
+// SetTargetHealthTargets ...
+func (dc DummyCollector) SetTargetHealthTargets(collectors.TargetHealthBackend, map[collectors.TargetHealthState]int) {
+}
+
+// RemoveTargetHealthTargets ...
+func (dc DummyCollector) RemoveTargetHealthTargets(collectors.TargetHealthBackend) {}
+
 // Start ...
 func (dc DummyCollector) Start() {}
 
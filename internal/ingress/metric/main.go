@@ -32,6 +32,11 @@ type Collector interface {
 	IncAPIErrorCount(prometheus.Labels)
 	IncAPIRetryCount(prometheus.Labels)
 
+	IncTargetHealthCacheHitCount()
+	IncTargetHealthCacheMissCount()
+	SetTargetHealthTargets(collectors.TargetHealthBackend, map[collectors.TargetHealthState]int)
+	RemoveTargetHealthTargets(collectors.TargetHealthBackend)
+
 	RemoveMetrics(string)
 
 	Start()
@@ -41,6 +46,7 @@ type Collector interface {
 type collector struct {
 	ingressController *collectors.Controller
 	awsAPIController  *collectors.AWSAPIController
+	targetHealth      *collectors.TargetHealthController
 
 	registry *prometheus.Registry
 }
@@ -49,10 +55,12 @@ type collector struct {
 func NewCollector(registry *prometheus.Registry, ingressClass string) (Collector, error) {
 	ic := collectors.NewController(ingressClass)
 	ac := collectors.NewAWSAPIController()
+	th := collectors.NewTargetHealthController()
 
 	return Collector(&collector{
 		ingressController: ic,
 		awsAPIController:  ac,
+		targetHealth:      th,
 		registry:          registry,
 	}), nil
 }
@@ -81,6 +89,22 @@ func (c *collector) IncAPIRetryCount(l prometheus.Labels) {
 	c.awsAPIController.IncAPIRetryCount(l)
 }
 
+func (c *collector) IncTargetHealthCacheHitCount() {
+	c.targetHealth.IncTargetHealthCacheHitCount()
+}
+
+func (c *collector) IncTargetHealthCacheMissCount() {
+	c.targetHealth.IncTargetHealthCacheMissCount()
+}
+
+func (c *collector) SetTargetHealthTargets(backend collectors.TargetHealthBackend, counts map[collectors.TargetHealthState]int) {
+	c.targetHealth.SetTargets(backend, counts)
+}
+
+func (c *collector) RemoveTargetHealthTargets(backend collectors.TargetHealthBackend) {
+	c.targetHealth.RemoveTargets(backend)
+}
+
 func (c *collector) RemoveMetrics(ingressName string) {
 	c.ingressController.RemoveMetrics(ingressName)
 }
@@ -88,9 +112,11 @@ func (c *collector) RemoveMetrics(ingressName string) {
 func (c *collector) Start() {
 	c.registry.MustRegister(c.ingressController)
 	c.registry.MustRegister(c.awsAPIController)
+	c.registry.MustRegister(c.targetHealth)
 }
 
 func (c *collector) Stop() {
 	c.registry.Unregister(c.ingressController)
 	c.registry.Unregister(c.awsAPIController)
+	c.registry.Unregister(c.targetHealth)
 }
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// EndpointSliceServiceNameLabel is the label of EndpointSlices referring to the Service they belong to
+const EndpointSliceServiceNameLabel = "kubernetes.io/service-name"
+
+// EndpointSliceGroupVersion is the group version of EndpointSlices consumed by the controller
+var EndpointSliceGroupVersion = schema.GroupVersion{Group: "discovery.k8s.io", Version: "v1"}
+
+// EndpointSlice mirrors the discovery.k8s.io/v1 EndpointSlice fields used by the controller.
+// The vendored k8s.io/api predates the discovery API group, so EndpointSlices are read as unstructured objects.
+type EndpointSlice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	AddressType string         `json:"addressType"`
+	Endpoints   []Endpoint     `json:"endpoints"`
+	Ports       []EndpointPort `json:"ports,omitempty"`
+}
+
+// Endpoint is a single logical backend of an EndpointSlice.
+// The zone and the topology aware hints of endpoints are left out, the ALB determines the availability zone of targets
+// and load balances across all zones.
+type Endpoint struct {
+	Addresses  []string               `json:"addresses"`
+	Conditions EndpointConditions     `json:"conditions,omitempty"`
+	TargetRef  *apiv1.ObjectReference `json:"targetRef,omitempty"`
+	NodeName   *string                `json:"nodeName,omitempty"`
+}
+
+// EndpointConditions represents the current condition of an endpoint.
+type EndpointConditions struct {
+	Ready       *bool `json:"ready,omitempty"`
+	Serving     *bool `json:"serving,omitempty"`
+	Terminating *bool `json:"terminating,omitempty"`
+}
+
+// EndpointPort represents a port used by an EndpointSlice.
+type EndpointPort struct {
+	Name     *string         `json:"name,omitempty"`
+	Protocol *apiv1.Protocol `json:"protocol,omitempty"`
+	Port     *int32          `json:"port,omitempty"`
+}
+
+// IsReady returns whether the endpoint is ready to receive traffic, an unknown state is interpreted as ready.
+func (c EndpointConditions) IsReady() bool {
+	return c.Ready == nil || *c.Ready
+}
+
+// IsServing returns whether the endpoint is able to receive traffic, regardless of whether it's terminating.
+// an unknown state defers to the ready condition.
+func (c EndpointConditions) IsServing() bool {
+	if c.Serving == nil {
+		return c.IsReady()
+	}
+	return *c.Serving
+}
+
+// IsTerminating returns whether the endpoint is terminating, an unknown state is interpreted as not terminating.
+func (c EndpointConditions) IsTerminating() bool {
+	return c.Terminating != nil && *c.Terminating
+}
+
+// NewUnstructuredEndpointSlice returns an empty unstructured EndpointSlice, to get informers or watch EndpointSlices.
+func NewUnstructuredEndpointSlice() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(EndpointSliceGroupVersion.WithKind("EndpointSlice"))
+	return obj
+}
+
+// NewUnstructuredEndpointSliceList returns an empty unstructured EndpointSliceList, to list EndpointSlices.
+func NewUnstructuredEndpointSliceList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(EndpointSliceGroupVersion.WithKind("EndpointSliceList"))
+	return list
+}
+
+// ConvertEndpointSlice converts an unstructured EndpointSlice into EndpointSlice.
+func ConvertEndpointSlice(obj *unstructured.Unstructured) (*EndpointSlice, error) {
+	endpointSlice := &EndpointSlice{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), endpointSlice); err != nil {
+		return nil, err
+	}
+	return endpointSlice, nil
+}
+
+// IsEndpointSliceSupported checks whether the API server serves EndpointSlices of EndpointSliceGroupVersion.
+func IsEndpointSliceSupported(cfg *rest.Config) (bool, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(EndpointSliceGroupVersion.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == "EndpointSlice" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
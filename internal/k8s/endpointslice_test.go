@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConvertEndpointSlice(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "discovery.k8s.io/v1",
+			"kind":       "EndpointSlice",
+			"metadata": map[string]interface{}{
+				"namespace": "namespace",
+				"name":      "service-abcde",
+				"labels": map[string]interface{}{
+					EndpointSliceServiceNameLabel: "service",
+				},
+			},
+			"addressType": "IPv4",
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"addresses": []interface{}{"10.0.0.1"},
+					"conditions": map[string]interface{}{
+						"ready":       false,
+						"serving":     true,
+						"terminating": true,
+					},
+					"nodeName": "node",
+					"zone":     "us-west-2a",
+					"hints": map[string]interface{}{
+						"forZones": []interface{}{map[string]interface{}{"name": "us-west-2a"}},
+					},
+				},
+				map[string]interface{}{
+					"addresses": []interface{}{"10.0.0.2"},
+				},
+			},
+			"ports": []interface{}{
+				map[string]interface{}{
+					"name": "http",
+					"port": int64(8080),
+				},
+			},
+		},
+	}
+
+	endpointSlice, err := ConvertEndpointSlice(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "service", endpointSlice.Labels[EndpointSliceServiceNameLabel])
+	assert.Equal(t, "IPv4", endpointSlice.AddressType)
+	assert.Equal(t, "http", *endpointSlice.Ports[0].Name)
+	assert.Equal(t, int32(8080), *endpointSlice.Ports[0].Port)
+
+	terminating := endpointSlice.Endpoints[0]
+	assert.Equal(t, []string{"10.0.0.1"}, terminating.Addresses)
+	assert.Equal(t, "node", *terminating.NodeName)
+	assert.False(t, terminating.Conditions.IsReady())
+	assert.True(t, terminating.Conditions.IsServing())
+	assert.True(t, terminating.Conditions.IsTerminating())
+
+	unknown := endpointSlice.Endpoints[1]
+	assert.True(t, unknown.Conditions.IsReady())
+	assert.True(t, unknown.Conditions.IsServing())
+	assert.False(t, unknown.Conditions.IsTerminating())
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	acm "github.com/aws/aws-sdk-go/service/acm"
 
+	cognitoidentityprovider "github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+
 	context "context"
 
 	ec2 "github.com/aws/aws-sdk-go/service/ec2"
@@ -15,8 +17,12 @@ import (
 
 	resourcegroupstaggingapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 
+	secretsmanager "github.com/aws/aws-sdk-go/service/secretsmanager"
+
 	shield "github.com/aws/aws-sdk-go/service/shield"
 
+	ssm "github.com/aws/aws-sdk-go/service/ssm"
+
 	waf "github.com/aws/aws-sdk-go/service/waf"
 
 	wafregional "github.com/aws/aws-sdk-go/service/wafregional"
@@ -66,6 +72,20 @@ func (_m *CloudAPI) AddELBV2TagsWithContext(_a0 context.Context, _a1 *elbv2.AddT
 	return r0, r1
 }
 
+// AddLambdaInvokePermission provides a mock function with given fields: ctx, functionArn, statementID, sourceArn
+func (_m *CloudAPI) AddLambdaInvokePermission(ctx context.Context, functionArn string, statementID string, sourceArn string) error {
+	ret := _m.Called(ctx, functionArn, statementID, sourceArn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, functionArn, statementID, sourceArn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddListenerCertificates provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) AddListenerCertificates(_a0 context.Context, _a1 *elbv2.AddListenerCertificatesInput) (*elbv2.AddListenerCertificatesOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -651,6 +671,29 @@ func (_m *CloudAPI) DescribeTargetHealthWithContext(_a0 context.Context, _a1 *el
 	return r0, r1
 }
 
+// DescribeUserPoolClient provides a mock function with given fields: ctx, userPoolID, clientID
+func (_m *CloudAPI) DescribeUserPoolClient(ctx context.Context, userPoolID string, clientID string) (*cognitoidentityprovider.UserPoolClientType, error) {
+	ret := _m.Called(ctx, userPoolID, clientID)
+
+	var r0 *cognitoidentityprovider.UserPoolClientType
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *cognitoidentityprovider.UserPoolClientType); ok {
+		r0 = rf(ctx, userPoolID, clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cognitoidentityprovider.UserPoolClientType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userPoolID, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DisassociateWAF provides a mock function with given fields: ctx, resourceArn
 func (_m *CloudAPI) DisassociateWAF(ctx context.Context, resourceArn *string) (*wafregional.DisassociateWebACLOutput, error) {
 	ret := _m.Called(ctx, resourceArn)
@@ -879,6 +922,52 @@ func (_m *CloudAPI) GetRules(_a0 context.Context, _a1 string) ([]*elbv2.Rule, er
 	return r0, r1
 }
 
+// GetSecretValue provides a mock function with given fields: ctx, secretID
+func (_m *CloudAPI) GetSecretValue(ctx context.Context, secretID string) (*secretsmanager.GetSecretValueOutput, error) {
+	ret := _m.Called(ctx, secretID)
+
+	var r0 *secretsmanager.GetSecretValueOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) *secretsmanager.GetSecretValueOutput); ok {
+		r0 = rf(ctx, secretID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*secretsmanager.GetSecretValueOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, secretID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSecureStringParameter provides a mock function with given fields: ctx, name
+func (_m *CloudAPI) GetSecureStringParameter(ctx context.Context, name string) (*ssm.Parameter, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *ssm.Parameter
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ssm.Parameter); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ssm.Parameter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSecurityGroupByID provides a mock function with given fields: _a0
 func (_m *CloudAPI) GetSecurityGroupByID(_a0 string) (*ec2.SecurityGroup, error) {
 	ret := _m.Called(_a0)
@@ -1040,6 +1129,52 @@ func (_m *CloudAPI) GetTargetGroupByName(_a0 context.Context, _a1 string) (*elbv
 	return r0, r1
 }
 
+// GetUserPoolByName provides a mock function with given fields: ctx, name
+func (_m *CloudAPI) GetUserPoolByName(ctx context.Context, name string) (*cognitoidentityprovider.UserPoolType, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *cognitoidentityprovider.UserPoolType
+	if rf, ok := ret.Get(0).(func(context.Context, string) *cognitoidentityprovider.UserPoolType); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cognitoidentityprovider.UserPoolType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserPoolClientByName provides a mock function with given fields: ctx, userPoolID, name
+func (_m *CloudAPI) GetUserPoolClientByName(ctx context.Context, userPoolID string, name string) (*cognitoidentityprovider.UserPoolClientType, error) {
+	ret := _m.Called(ctx, userPoolID, name)
+
+	var r0 *cognitoidentityprovider.UserPoolClientType
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *cognitoidentityprovider.UserPoolClientType); ok {
+		r0 = rf(ctx, userPoolID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*cognitoidentityprovider.UserPoolClientType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userPoolID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVpcID provides a mock function with given fields:
 func (_m *CloudAPI) GetVpcID() string {
 	ret := _m.Called()
@@ -1353,6 +1488,20 @@ func (_m *CloudAPI) RemoveELBV2TagsWithContext(_a0 context.Context, _a1 *elbv2.R
 	return r0, r1
 }
 
+// RemoveLambdaInvokePermission provides a mock function with given fields: ctx, functionArn, statementID
+func (_m *CloudAPI) RemoveLambdaInvokePermission(ctx context.Context, functionArn string, statementID string) error {
+	ret := _m.Called(ctx, functionArn, statementID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, functionArn, statementID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveListenerCertificates provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) RemoveListenerCertificates(_a0 context.Context, _a1 *elbv2.RemoveListenerCertificatesInput) (*elbv2.RemoveListenerCertificatesOutput, error) {
 	ret := _m.Called(_a0, _a1)
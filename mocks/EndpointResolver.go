@@ -61,3 +61,26 @@ func (_m *EndpointResolver) ReverseResolve(_a0 *v1beta1.Ingress, _a1 *v1beta1.In
 
 	return r0, r1
 }
+
+// ResolvePods provides a mock function with given fields: _a0, _a1
+func (_m *EndpointResolver) ResolvePods(_a0 *v1beta1.Ingress, _a1 *v1beta1.IngressBackend) ([]*v1.Pod, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*v1.Pod
+	if rf, ok := ret.Get(0).(func(*v1beta1.Ingress, *v1beta1.IngressBackend) []*v1.Pod); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v1.Pod)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1beta1.Ingress, *v1beta1.IngressBackend) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -172,6 +172,162 @@ func (_m *ShieldAPI) AssociateDRTRoleWithContext(_a0 context.Context, _a1 *shiel
 	return r0, r1
 }
 
+// AssociateHealthCheck provides a mock function with given fields: _a0
+func (_m *ShieldAPI) AssociateHealthCheck(_a0 *shield.AssociateHealthCheckInput) (*shield.AssociateHealthCheckOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *shield.AssociateHealthCheckOutput
+	if rf, ok := ret.Get(0).(func(*shield.AssociateHealthCheckInput) *shield.AssociateHealthCheckOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.AssociateHealthCheckOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*shield.AssociateHealthCheckInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssociateHealthCheckRequest provides a mock function with given fields: _a0
+func (_m *ShieldAPI) AssociateHealthCheckRequest(_a0 *shield.AssociateHealthCheckInput) (*request.Request, *shield.AssociateHealthCheckOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*shield.AssociateHealthCheckInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *shield.AssociateHealthCheckOutput
+	if rf, ok := ret.Get(1).(func(*shield.AssociateHealthCheckInput) *shield.AssociateHealthCheckOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*shield.AssociateHealthCheckOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// AssociateHealthCheckWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ShieldAPI) AssociateHealthCheckWithContext(_a0 context.Context, _a1 *shield.AssociateHealthCheckInput, _a2 ...request.Option) (*shield.AssociateHealthCheckOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *shield.AssociateHealthCheckOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.AssociateHealthCheckInput, ...request.Option) *shield.AssociateHealthCheckOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.AssociateHealthCheckOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *shield.AssociateHealthCheckInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssociateProactiveEngagementDetails provides a mock function with given fields: _a0
+func (_m *ShieldAPI) AssociateProactiveEngagementDetails(_a0 *shield.AssociateProactiveEngagementDetailsInput) (*shield.AssociateProactiveEngagementDetailsOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *shield.AssociateProactiveEngagementDetailsOutput
+	if rf, ok := ret.Get(0).(func(*shield.AssociateProactiveEngagementDetailsInput) *shield.AssociateProactiveEngagementDetailsOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.AssociateProactiveEngagementDetailsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*shield.AssociateProactiveEngagementDetailsInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssociateProactiveEngagementDetailsRequest provides a mock function with given fields: _a0
+func (_m *ShieldAPI) AssociateProactiveEngagementDetailsRequest(_a0 *shield.AssociateProactiveEngagementDetailsInput) (*request.Request, *shield.AssociateProactiveEngagementDetailsOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*shield.AssociateProactiveEngagementDetailsInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *shield.AssociateProactiveEngagementDetailsOutput
+	if rf, ok := ret.Get(1).(func(*shield.AssociateProactiveEngagementDetailsInput) *shield.AssociateProactiveEngagementDetailsOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*shield.AssociateProactiveEngagementDetailsOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// AssociateProactiveEngagementDetailsWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ShieldAPI) AssociateProactiveEngagementDetailsWithContext(_a0 context.Context, _a1 *shield.AssociateProactiveEngagementDetailsInput, _a2 ...request.Option) (*shield.AssociateProactiveEngagementDetailsOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *shield.AssociateProactiveEngagementDetailsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.AssociateProactiveEngagementDetailsInput, ...request.Option) *shield.AssociateProactiveEngagementDetailsOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.AssociateProactiveEngagementDetailsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *shield.AssociateProactiveEngagementDetailsInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateProtection provides a mock function with given fields: _a0
 func (_m *ShieldAPI) CreateProtection(_a0 *shield.CreateProtectionInput) (*shield.CreateProtectionOutput, error) {
 	ret := _m.Called(_a0)
@@ -874,6 +1030,84 @@ func (_m *ShieldAPI) DescribeSubscriptionWithContext(_a0 context.Context, _a1 *s
 	return r0, r1
 }
 
+// DisableProactiveEngagement provides a mock function with given fields: _a0
+func (_m *ShieldAPI) DisableProactiveEngagement(_a0 *shield.DisableProactiveEngagementInput) (*shield.DisableProactiveEngagementOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *shield.DisableProactiveEngagementOutput
+	if rf, ok := ret.Get(0).(func(*shield.DisableProactiveEngagementInput) *shield.DisableProactiveEngagementOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.DisableProactiveEngagementOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*shield.DisableProactiveEngagementInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableProactiveEngagementRequest provides a mock function with given fields: _a0
+func (_m *ShieldAPI) DisableProactiveEngagementRequest(_a0 *shield.DisableProactiveEngagementInput) (*request.Request, *shield.DisableProactiveEngagementOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*shield.DisableProactiveEngagementInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *shield.DisableProactiveEngagementOutput
+	if rf, ok := ret.Get(1).(func(*shield.DisableProactiveEngagementInput) *shield.DisableProactiveEngagementOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*shield.DisableProactiveEngagementOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// DisableProactiveEngagementWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ShieldAPI) DisableProactiveEngagementWithContext(_a0 context.Context, _a1 *shield.DisableProactiveEngagementInput, _a2 ...request.Option) (*shield.DisableProactiveEngagementOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *shield.DisableProactiveEngagementOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.DisableProactiveEngagementInput, ...request.Option) *shield.DisableProactiveEngagementOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.DisableProactiveEngagementOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *shield.DisableProactiveEngagementInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DisassociateDRTLogBucket provides a mock function with given fields: _a0
 func (_m *ShieldAPI) DisassociateDRTLogBucket(_a0 *shield.DisassociateDRTLogBucketInput) (*shield.DisassociateDRTLogBucketOutput, error) {
 	ret := _m.Called(_a0)
@@ -1030,6 +1264,162 @@ func (_m *ShieldAPI) DisassociateDRTRoleWithContext(_a0 context.Context, _a1 *sh
 	return r0, r1
 }
 
+// DisassociateHealthCheck provides a mock function with given fields: _a0
+func (_m *ShieldAPI) DisassociateHealthCheck(_a0 *shield.DisassociateHealthCheckInput) (*shield.DisassociateHealthCheckOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *shield.DisassociateHealthCheckOutput
+	if rf, ok := ret.Get(0).(func(*shield.DisassociateHealthCheckInput) *shield.DisassociateHealthCheckOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.DisassociateHealthCheckOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*shield.DisassociateHealthCheckInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisassociateHealthCheckRequest provides a mock function with given fields: _a0
+func (_m *ShieldAPI) DisassociateHealthCheckRequest(_a0 *shield.DisassociateHealthCheckInput) (*request.Request, *shield.DisassociateHealthCheckOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*shield.DisassociateHealthCheckInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *shield.DisassociateHealthCheckOutput
+	if rf, ok := ret.Get(1).(func(*shield.DisassociateHealthCheckInput) *shield.DisassociateHealthCheckOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*shield.DisassociateHealthCheckOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// DisassociateHealthCheckWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ShieldAPI) DisassociateHealthCheckWithContext(_a0 context.Context, _a1 *shield.DisassociateHealthCheckInput, _a2 ...request.Option) (*shield.DisassociateHealthCheckOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *shield.DisassociateHealthCheckOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.DisassociateHealthCheckInput, ...request.Option) *shield.DisassociateHealthCheckOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.DisassociateHealthCheckOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *shield.DisassociateHealthCheckInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnableProactiveEngagement provides a mock function with given fields: _a0
+func (_m *ShieldAPI) EnableProactiveEngagement(_a0 *shield.EnableProactiveEngagementInput) (*shield.EnableProactiveEngagementOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *shield.EnableProactiveEngagementOutput
+	if rf, ok := ret.Get(0).(func(*shield.EnableProactiveEngagementInput) *shield.EnableProactiveEngagementOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.EnableProactiveEngagementOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*shield.EnableProactiveEngagementInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnableProactiveEngagementRequest provides a mock function with given fields: _a0
+func (_m *ShieldAPI) EnableProactiveEngagementRequest(_a0 *shield.EnableProactiveEngagementInput) (*request.Request, *shield.EnableProactiveEngagementOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*shield.EnableProactiveEngagementInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *shield.EnableProactiveEngagementOutput
+	if rf, ok := ret.Get(1).(func(*shield.EnableProactiveEngagementInput) *shield.EnableProactiveEngagementOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*shield.EnableProactiveEngagementOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// EnableProactiveEngagementWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ShieldAPI) EnableProactiveEngagementWithContext(_a0 context.Context, _a1 *shield.EnableProactiveEngagementInput, _a2 ...request.Option) (*shield.EnableProactiveEngagementOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *shield.EnableProactiveEngagementOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.EnableProactiveEngagementInput, ...request.Option) *shield.EnableProactiveEngagementOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shield.EnableProactiveEngagementOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *shield.EnableProactiveEngagementInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSubscriptionState provides a mock function with given fields: _a0
 func (_m *ShieldAPI) GetSubscriptionState(_a0 *shield.GetSubscriptionStateInput) (*shield.GetSubscriptionStateOutput, error) {
 	ret := _m.Called(_a0)
@@ -1131,6 +1521,41 @@ func (_m *ShieldAPI) ListAttacks(_a0 *shield.ListAttacksInput) (*shield.ListAtta
 	return r0, r1
 }
 
+// ListAttacksPages provides a mock function with given fields: _a0, _a1
+func (_m *ShieldAPI) ListAttacksPages(_a0 *shield.ListAttacksInput, _a1 func(*shield.ListAttacksOutput, bool) bool) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*shield.ListAttacksInput, func(*shield.ListAttacksOutput, bool) bool) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAttacksPagesWithContext provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ShieldAPI) ListAttacksPagesWithContext(_a0 context.Context, _a1 *shield.ListAttacksInput, _a2 func(*shield.ListAttacksOutput, bool) bool, _a3 ...request.Option) error {
+	_va := make([]interface{}, len(_a3))
+	for _i := range _a3 {
+		_va[_i] = _a3[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1, _a2)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.ListAttacksInput, func(*shield.ListAttacksOutput, bool) bool, ...request.Option) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ListAttacksRequest provides a mock function with given fields: _a0
 func (_m *ShieldAPI) ListAttacksRequest(_a0 *shield.ListAttacksInput) (*request.Request, *shield.ListAttacksOutput) {
 	ret := _m.Called(_a0)
@@ -1209,6 +1634,41 @@ func (_m *ShieldAPI) ListProtections(_a0 *shield.ListProtectionsInput) (*shield.
 	return r0, r1
 }
 
+// ListProtectionsPages provides a mock function with given fields: _a0, _a1
+func (_m *ShieldAPI) ListProtectionsPages(_a0 *shield.ListProtectionsInput, _a1 func(*shield.ListProtectionsOutput, bool) bool) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*shield.ListProtectionsInput, func(*shield.ListProtectionsOutput, bool) bool) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListProtectionsPagesWithContext provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ShieldAPI) ListProtectionsPagesWithContext(_a0 context.Context, _a1 *shield.ListProtectionsInput, _a2 func(*shield.ListProtectionsOutput, bool) bool, _a3 ...request.Option) error {
+	_va := make([]interface{}, len(_a3))
+	for _i := range _a3 {
+		_va[_i] = _a3[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1, _a2)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *shield.ListProtectionsInput, func(*shield.ListProtectionsOutput, bool) bool, ...request.Option) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ListProtectionsRequest provides a mock function with given fields: _a0
 func (_m *ShieldAPI) ListProtectionsRequest(_a0 *shield.ListProtectionsInput) (*request.Request, *shield.ListProtectionsOutput) {
 	ret := _m.Called(_a0)
@@ -484,6 +484,84 @@ func (_m *WAFV2API) CreateWebACLWithContext(_a0 context.Context, _a1 *wafv2.Crea
 	return r0, r1
 }
 
+// DeleteFirewallManagerRuleGroups provides a mock function with given fields: _a0
+func (_m *WAFV2API) DeleteFirewallManagerRuleGroups(_a0 *wafv2.DeleteFirewallManagerRuleGroupsInput) (*wafv2.DeleteFirewallManagerRuleGroupsOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *wafv2.DeleteFirewallManagerRuleGroupsOutput
+	if rf, ok := ret.Get(0).(func(*wafv2.DeleteFirewallManagerRuleGroupsInput) *wafv2.DeleteFirewallManagerRuleGroupsOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.DeleteFirewallManagerRuleGroupsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*wafv2.DeleteFirewallManagerRuleGroupsInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFirewallManagerRuleGroupsRequest provides a mock function with given fields: _a0
+func (_m *WAFV2API) DeleteFirewallManagerRuleGroupsRequest(_a0 *wafv2.DeleteFirewallManagerRuleGroupsInput) (*request.Request, *wafv2.DeleteFirewallManagerRuleGroupsOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*wafv2.DeleteFirewallManagerRuleGroupsInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *wafv2.DeleteFirewallManagerRuleGroupsOutput
+	if rf, ok := ret.Get(1).(func(*wafv2.DeleteFirewallManagerRuleGroupsInput) *wafv2.DeleteFirewallManagerRuleGroupsOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*wafv2.DeleteFirewallManagerRuleGroupsOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteFirewallManagerRuleGroupsWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WAFV2API) DeleteFirewallManagerRuleGroupsWithContext(_a0 context.Context, _a1 *wafv2.DeleteFirewallManagerRuleGroupsInput, _a2 ...request.Option) (*wafv2.DeleteFirewallManagerRuleGroupsOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wafv2.DeleteFirewallManagerRuleGroupsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *wafv2.DeleteFirewallManagerRuleGroupsInput, ...request.Option) *wafv2.DeleteFirewallManagerRuleGroupsOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.DeleteFirewallManagerRuleGroupsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *wafv2.DeleteFirewallManagerRuleGroupsInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteIPSet provides a mock function with given fields: _a0
 func (_m *WAFV2API) DeleteIPSet(_a0 *wafv2.DeleteIPSetInput) (*wafv2.DeleteIPSetOutput, error) {
 	ret := _m.Called(_a0)
@@ -640,6 +718,84 @@ func (_m *WAFV2API) DeleteLoggingConfigurationWithContext(_a0 context.Context, _
 	return r0, r1
 }
 
+// DeletePermissionPolicy provides a mock function with given fields: _a0
+func (_m *WAFV2API) DeletePermissionPolicy(_a0 *wafv2.DeletePermissionPolicyInput) (*wafv2.DeletePermissionPolicyOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *wafv2.DeletePermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(*wafv2.DeletePermissionPolicyInput) *wafv2.DeletePermissionPolicyOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.DeletePermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*wafv2.DeletePermissionPolicyInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePermissionPolicyRequest provides a mock function with given fields: _a0
+func (_m *WAFV2API) DeletePermissionPolicyRequest(_a0 *wafv2.DeletePermissionPolicyInput) (*request.Request, *wafv2.DeletePermissionPolicyOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*wafv2.DeletePermissionPolicyInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *wafv2.DeletePermissionPolicyOutput
+	if rf, ok := ret.Get(1).(func(*wafv2.DeletePermissionPolicyInput) *wafv2.DeletePermissionPolicyOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*wafv2.DeletePermissionPolicyOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeletePermissionPolicyWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WAFV2API) DeletePermissionPolicyWithContext(_a0 context.Context, _a1 *wafv2.DeletePermissionPolicyInput, _a2 ...request.Option) (*wafv2.DeletePermissionPolicyOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wafv2.DeletePermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *wafv2.DeletePermissionPolicyInput, ...request.Option) *wafv2.DeletePermissionPolicyOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.DeletePermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *wafv2.DeletePermissionPolicyInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteRegexPatternSet provides a mock function with given fields: _a0
 func (_m *WAFV2API) DeleteRegexPatternSet(_a0 *wafv2.DeleteRegexPatternSetInput) (*wafv2.DeleteRegexPatternSetOutput, error) {
 	ret := _m.Called(_a0)
@@ -1186,6 +1342,84 @@ func (_m *WAFV2API) GetLoggingConfigurationWithContext(_a0 context.Context, _a1
 	return r0, r1
 }
 
+// GetPermissionPolicy provides a mock function with given fields: _a0
+func (_m *WAFV2API) GetPermissionPolicy(_a0 *wafv2.GetPermissionPolicyInput) (*wafv2.GetPermissionPolicyOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *wafv2.GetPermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(*wafv2.GetPermissionPolicyInput) *wafv2.GetPermissionPolicyOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.GetPermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*wafv2.GetPermissionPolicyInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPermissionPolicyRequest provides a mock function with given fields: _a0
+func (_m *WAFV2API) GetPermissionPolicyRequest(_a0 *wafv2.GetPermissionPolicyInput) (*request.Request, *wafv2.GetPermissionPolicyOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*wafv2.GetPermissionPolicyInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *wafv2.GetPermissionPolicyOutput
+	if rf, ok := ret.Get(1).(func(*wafv2.GetPermissionPolicyInput) *wafv2.GetPermissionPolicyOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*wafv2.GetPermissionPolicyOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetPermissionPolicyWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WAFV2API) GetPermissionPolicyWithContext(_a0 context.Context, _a1 *wafv2.GetPermissionPolicyInput, _a2 ...request.Option) (*wafv2.GetPermissionPolicyOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wafv2.GetPermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *wafv2.GetPermissionPolicyInput, ...request.Option) *wafv2.GetPermissionPolicyOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.GetPermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *wafv2.GetPermissionPolicyInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRateBasedStatementManagedKeys provides a mock function with given fields: _a0
 func (_m *WAFV2API) GetRateBasedStatementManagedKeys(_a0 *wafv2.GetRateBasedStatementManagedKeysInput) (*wafv2.GetRateBasedStatementManagedKeysOutput, error) {
 	ret := _m.Called(_a0)
@@ -2356,6 +2590,84 @@ func (_m *WAFV2API) PutLoggingConfigurationWithContext(_a0 context.Context, _a1
 	return r0, r1
 }
 
+// PutPermissionPolicy provides a mock function with given fields: _a0
+func (_m *WAFV2API) PutPermissionPolicy(_a0 *wafv2.PutPermissionPolicyInput) (*wafv2.PutPermissionPolicyOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *wafv2.PutPermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(*wafv2.PutPermissionPolicyInput) *wafv2.PutPermissionPolicyOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.PutPermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*wafv2.PutPermissionPolicyInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutPermissionPolicyRequest provides a mock function with given fields: _a0
+func (_m *WAFV2API) PutPermissionPolicyRequest(_a0 *wafv2.PutPermissionPolicyInput) (*request.Request, *wafv2.PutPermissionPolicyOutput) {
+	ret := _m.Called(_a0)
+
+	var r0 *request.Request
+	if rf, ok := ret.Get(0).(func(*wafv2.PutPermissionPolicyInput) *request.Request); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*request.Request)
+		}
+	}
+
+	var r1 *wafv2.PutPermissionPolicyOutput
+	if rf, ok := ret.Get(1).(func(*wafv2.PutPermissionPolicyInput) *wafv2.PutPermissionPolicyOutput); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*wafv2.PutPermissionPolicyOutput)
+		}
+	}
+
+	return r0, r1
+}
+
+// PutPermissionPolicyWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WAFV2API) PutPermissionPolicyWithContext(_a0 context.Context, _a1 *wafv2.PutPermissionPolicyInput, _a2 ...request.Option) (*wafv2.PutPermissionPolicyOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wafv2.PutPermissionPolicyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *wafv2.PutPermissionPolicyInput, ...request.Option) *wafv2.PutPermissionPolicyOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wafv2.PutPermissionPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *wafv2.PutPermissionPolicyInput, ...request.Option) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TagResource provides a mock function with given fields: _a0
 func (_m *WAFV2API) TagResource(_a0 *wafv2.TagResourceInput) (*wafv2.TagResourceOutput, error) {
 	ret := _m.Called(_a0)